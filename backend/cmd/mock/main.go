@@ -1,7 +1,12 @@
 package main
 
 import (
+	"backend/database"
 	"backend/database/generate"
+	"backend/database/migrations"
+	"backend/model"
+	"backend/service"
+	"context"
 	"crypto/rand"
 	"database/sql"
 	"encoding/hex"
@@ -116,78 +121,18 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// createTables brings the schema up to date using the server's migrations
 func createTables(db *sql.DB) error {
-	queries := []string{
-		`CREATE TABLE IF NOT EXISTS users (
-			id SERIAL PRIMARY KEY,
-			username VARCHAR(255) UNIQUE NOT NULL,
-			password_hash VARCHAR(255) NOT NULL,
-			name VARCHAR(255) NOT NULL DEFAULT '',
-			phone VARCHAR(20) DEFAULT '',
-			bio TEXT DEFAULT '',
-			role VARCHAR(50) NOT NULL DEFAULT 'player',
-			avatar_url VARCHAR(500) DEFAULT '',
-			is_active BOOLEAN DEFAULT true,
-			last_login_at TIMESTAMP WITH TIME ZONE,
-			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
-		)`,
-		`CREATE TABLE IF NOT EXISTS user_stats (
-			user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
-			total_matches INTEGER DEFAULT 0,
-			wins INTEGER DEFAULT 0,
-			losses INTEGER DEFAULT 0,
-			win_rate DECIMAL(5,2) DEFAULT 0,
-			current_streak INTEGER DEFAULT 0,
-			best_streak INTEGER DEFAULT 0,
-			skill_level VARCHAR(50) DEFAULT 'Beginner',
-			skill_points INTEGER DEFAULT 0,
-			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
-		)`,
-		`CREATE TABLE IF NOT EXISTS matches (
-			id SERIAL PRIMARY KEY,
-			court VARCHAR(100),
-			team1 INTEGER[] NOT NULL,
-			team2 INTEGER[] NOT NULL,
-			result VARCHAR(50) DEFAULT 'pending',
-			started_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-			ended_at TIMESTAMP WITH TIME ZONE,
-			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
-		)`,
-		`CREATE TABLE IF NOT EXISTS match_scores (
-			id SERIAL PRIMARY KEY,
-			match_id INTEGER REFERENCES matches(id) ON DELETE CASCADE,
-			game_number INTEGER NOT NULL,
-			team1_score INTEGER DEFAULT 0,
-			team2_score INTEGER DEFAULT 0
-		)`,
-		`CREATE TABLE IF NOT EXISTS queue_entries (
-			id SERIAL PRIMARY KEY,
-			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
-			position INTEGER NOT NULL,
-			status VARCHAR(50) DEFAULT 'waiting',
-			joined_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-			called_at TIMESTAMP WITH TIME ZONE
-		)`,
-		`CREATE TABLE IF NOT EXISTS refresh_tokens (
-			id SERIAL PRIMARY KEY,
-			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
-			token VARCHAR(255) UNIQUE NOT NULL,
-			expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
-			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-			revoked_at TIMESTAMP WITH TIME ZONE
-		)`,
-		`CREATE INDEX IF NOT EXISTS idx_users_username ON users(username)`,
-		`CREATE INDEX IF NOT EXISTS idx_users_phone ON users(phone)`,
-		`CREATE INDEX IF NOT EXISTS idx_matches_result ON matches(result)`,
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
 	}
 
-	for _, q := range queries {
-		if _, err := db.Exec(q); err != nil {
-			return fmt.Errorf("query failed: %w\nQuery: %s", err, q[:50])
-		}
+	applied, err := migrator.Up(context.Background())
+	for _, m := range applied {
+		fmt.Printf("  Applied migration %04d_%s\n", m.Version, m.Name)
 	}
-	return nil
+	return err
 }
 
 func hashPassword(password string) string {
@@ -231,6 +176,13 @@ func generatePlayers(db *sql.DB, count int) ([]int64, error) {
 		// Update user with skill_tier
 		db.Exec(`UPDATE users SET skill_tier = $1 WHERE id = $2`, skillTier, id)
 
+		// Join the default club
+		db.Exec(`
+			INSERT INTO club_members (club_id, user_id, role)
+			VALUES ((SELECT id FROM clubs WHERE slug = 'default'), $1, 'player')
+			ON CONFLICT DO NOTHING
+		`, id)
+
 		ids = append(ids, id)
 		fmt.Printf("  [%d/%d] Created: %s (@%s) %s\n", i+1, count, fullName, username, phone)
 	}
@@ -239,7 +191,10 @@ func generatePlayers(db *sql.DB, count int) ([]int64, error) {
 }
 
 func generateMatches(db *sql.DB, playerIDs []int64, count int) (int, error) {
-	courts := []string{"Court 1", "Court 2", "Court 3", "Court 4"}
+	courts, err := loadCourts(db)
+	if err != nil {
+		return 0, err
+	}
 	matchCount := 0
 
 	for i := 0; i < count; i++ {
@@ -275,8 +230,9 @@ func generateMatches(db *sql.DB, playerIDs []int64, count int) (int, error) {
 		// Insert match
 		var matchID int64
 		err := db.QueryRow(`
-			INSERT INTO matches (court, team1, team2, result, started_at, ended_at, created_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $5)
+			INSERT INTO matches (club_id, court_id, court, team1, team2, result, started_at, ended_at, created_at)
+			SELECT c.id, (SELECT id FROM courts WHERE club_id = c.id AND name = $1), $1, $2, $3, $4, $5, $6, $5
+			FROM clubs c WHERE c.slug = 'default'
 			RETURNING id
 		`, court, pq(team1), pq(team2), result, startedAt, endedAt).Scan(&matchID)
 
@@ -306,6 +262,33 @@ func generateMatches(db *sql.DB, playerIDs []int64, count int) (int, error) {
 	return matchCount, nil
 }
 
+// loadCourts returns the names of the default club's active courts
+func loadCourts(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`
+		SELECT name FROM courts
+		WHERE status = 'active' AND club_id = (SELECT id FROM clubs WHERE slug = 'default')
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load courts: %w", err)
+	}
+	defer rows.Close()
+
+	var courts []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		courts = append(courts, name)
+	}
+
+	if len(courts) == 0 {
+		return nil, fmt.Errorf("no active courts registered")
+	}
+	return courts, nil
+}
+
 // Helper to format int64 slice as PostgreSQL array
 func pq(ids []int64) string {
 	strs := make([]string, len(ids))
@@ -315,59 +298,16 @@ func pq(ids []int64) string {
 	return "{" + strings.Join(strs, ",") + "}"
 }
 
+// updatePlayerStats rebuilds every player's record and rating from the generated matches
+// with the same routine the server uses
 func updatePlayerStats(db *sql.DB) error {
-	// Calculate stats from matches
-	_, err := db.Exec(`
-		WITH match_results AS (
-			SELECT 
-				unnest(team1) as user_id,
-				CASE WHEN result = 'team1' THEN 1 ELSE 0 END as win,
-				CASE WHEN result = 'team2' THEN 1 ELSE 0 END as loss
-			FROM matches WHERE result IN ('team1', 'team2')
-			UNION ALL
-			SELECT 
-				unnest(team2) as user_id,
-				CASE WHEN result = 'team2' THEN 1 ELSE 0 END as win,
-				CASE WHEN result = 'team1' THEN 1 ELSE 0 END as loss
-			FROM matches WHERE result IN ('team1', 'team2')
-		),
-		aggregated AS (
-			SELECT 
-				user_id,
-				COUNT(*) as total_matches,
-				SUM(win) as wins,
-				SUM(loss) as losses,
-				ROUND(SUM(win)::numeric * 100 / NULLIF(COUNT(*), 0), 2) as win_rate
-			FROM match_results
-			GROUP BY user_id
-		)
-		INSERT INTO user_stats (user_id, total_matches, wins, losses, win_rate, skill_level, skill_points, updated_at)
-		SELECT 
-			a.user_id,
-			a.total_matches,
-			a.wins,
-			a.losses,
-			a.win_rate,
-			CASE 
-				WHEN a.win_rate >= 75 THEN 'Expert'
-				WHEN a.win_rate >= 55 THEN 'Advanced'
-				WHEN a.win_rate >= 40 THEN 'Intermediate'
-				ELSE 'Beginner'
-			END,
-			(a.total_matches * 10 + a.wins * 5),
-			NOW()
-		FROM aggregated a
-		ON CONFLICT (user_id) DO UPDATE SET
-			total_matches = EXCLUDED.total_matches,
-			wins = EXCLUDED.wins,
-			losses = EXCLUDED.losses,
-			win_rate = EXCLUDED.win_rate,
-			skill_level = EXCLUDED.skill_level,
-			skill_points = EXCLUDED.skill_points,
-			updated_at = NOW()
-	`)
-
-	return err
+	users := service.NewUserService(nil, nil, database.NewPostgresStore(db))
+	result, err := users.RecomputeAllStats(model.Actor{})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  Replayed %d matches for %d players\n", result.Matches, result.Players)
+	return nil
 }
 
 func createAdminUser(db *sql.DB) error {
@@ -386,6 +326,7 @@ func createAdminUser(db *sql.DB) error {
 
 	// Create stats for admin
 	db.Exec(`INSERT INTO user_stats (user_id, skill_level) VALUES ($1, 'Expert') ON CONFLICT DO NOTHING`, id)
+	db.Exec(`INSERT INTO club_members (club_id, user_id, role) VALUES ((SELECT id FROM clubs WHERE slug = 'default'), $1, 'admin') ON CONFLICT DO NOTHING`, id)
 
 	return nil
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"bufio"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -13,6 +14,9 @@ type Config struct {
 	Database DatabaseConfig
 	Auth     AuthConfig
 	CORS     CORSConfig
+	Queue    QueueConfig
+	Notify   NotifyConfig
+	Season   SeasonConfig
 }
 
 // ServerConfig holds HTTP server settings
@@ -20,6 +24,9 @@ type ServerConfig struct {
 	Port     string
 	CertFile string
 	KeyFile  string
+	// TrustedProxies lists the proxy addresses or CIDR ranges whose X-Forwarded-For and
+	// X-Real-IP headers are believed. Other callers are identified by their own address.
+	TrustedProxies []string
 }
 
 // DatabaseConfig holds PostgreSQL connection settings
@@ -30,6 +37,8 @@ type DatabaseConfig struct {
 	Password string
 	Name     string
 	SSLMode  string
+	// AutoMigrate applies pending schema migrations when the server starts
+	AutoMigrate bool
 }
 
 // AuthConfig holds authentication settings
@@ -37,6 +46,27 @@ type AuthConfig struct {
 	SecretKey            string
 	AccessTokenDuration  time.Duration
 	RefreshTokenDuration time.Duration
+	// ResetCodeDuration is how long a password reset code stays usable
+	ResetCodeDuration time.Duration
+}
+
+// QueueConfig holds queue and court rotation settings
+type QueueConfig struct {
+	// AutoDispatch fills a freed court with the next four waiting players
+	AutoDispatch bool
+}
+
+// SeasonConfig holds season settings
+type SeasonConfig struct {
+	// RatingCarryover is the default share of each player's distance from the club's mean
+	// rating kept into a new season: 1 keeps ratings as they are, 0 starts everyone at the mean
+	RatingCarryover float64
+}
+
+// NotifyConfig holds settings for messages sent to users
+type NotifyConfig struct {
+	// Channel picks the notifier: "log" writes messages to the server log
+	Channel string
 }
 
 // CORSConfig holds CORS settings
@@ -54,25 +84,38 @@ func Load() *Config {
 			Port:     getEnv("SERVER_PORT", "8080"),
 			CertFile: getEnv("TLS_CERT_FILE", ""),
 			KeyFile:  getEnv("TLS_KEY_FILE", ""),
+
+			TrustedProxies: getEnvList("TRUSTED_PROXIES"),
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "5432"),
-			User:     getEnv("DB_USER", "kong"),
-			Password: getEnv("DB_PASSWORD", ""),
-			Name:     getEnv("DB_NAME", "smashqueue"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			Host:        getEnv("DB_HOST", "localhost"),
+			Port:        getEnv("DB_PORT", "5432"),
+			User:        getEnv("DB_USER", "kong"),
+			Password:    getEnv("DB_PASSWORD", ""),
+			Name:        getEnv("DB_NAME", "smashqueue"),
+			SSLMode:     getEnv("DB_SSLMODE", "disable"),
+			AutoMigrate: getEnv("DB_AUTO_MIGRATE", "true") == "true",
 		},
 		Auth: AuthConfig{
 			SecretKey:            getEnv("AUTH_SECRET_KEY", "smashqueue-secret-key-change-in-production-32bytes!"),
 			AccessTokenDuration:  30 * time.Minute,
 			RefreshTokenDuration: 7 * 24 * time.Hour,
+			ResetCodeDuration:    15 * time.Minute,
 		},
 		CORS: CORSConfig{
 			AllowedOrigins: []string{
 				getEnv("CORS_ORIGIN", "http://localhost:3000"),
 			},
 		},
+		Queue: QueueConfig{
+			AutoDispatch: getEnv("AUTO_DISPATCH", "false") == "true",
+		},
+		Notify: NotifyConfig{
+			Channel: getEnv("NOTIFY_CHANNEL", "log"),
+		},
+		Season: SeasonConfig{
+			RatingCarryover: getEnvFloat("SEASON_RATING_CARRYOVER", 1),
+		},
 	}
 }
 
@@ -117,6 +160,26 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvList reads a comma-separated environment variable, skipping empty items
+func getEnvList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnvFloat reads a numeric environment variable or returns a default value when it is
+// unset or not a number
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
 // ConnectionString builds the PostgreSQL connection string
 func (d *DatabaseConfig) ConnectionString() string {
 	return "postgresql://" + d.User + ":" + d.Password + "@" + d.Host + ":" + d.Port + "/" + d.Name + "?sslmode=" + d.SSLMode
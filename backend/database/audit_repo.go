@@ -0,0 +1,73 @@
+package database
+
+import (
+	"backend/model"
+	"context"
+	"time"
+)
+
+// auditRepo implements AuditRepository on PostgreSQL
+type auditRepo struct {
+	q dbtx
+}
+
+// Create records an audit event
+func (r *auditRepo) Create(ctx context.Context, e *model.AuditEvent) error {
+	return r.q.QueryRowContext(ctx, `
+		INSERT INTO audit_events (actor_id, club_id, action, target_type, target_id, before, after, request_id, ip, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+		RETURNING id, created_at
+	`, e.ActorID, e.ClubID, string(e.Action), e.TargetType, e.TargetID, jsonArg(e.Before), jsonArg(e.After),
+		e.RequestID, e.IP).Scan(&e.ID, &e.CreatedAt)
+}
+
+// List returns audit events matching the filter, newest first
+func (r *auditRepo) List(ctx context.Context, f AuditFilter) ([]model.AuditEvent, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT id, actor_id, club_id, action, target_type, target_id, before, after, request_id, ip, created_at
+		FROM audit_events
+		WHERE ($1 = 0 OR club_id = $1)
+		  AND ($2 = 0 OR actor_id = $2)
+		  AND ($3 = '' OR action = $3)
+		  AND ($4 = '' OR target_type = $4)
+		  AND ($5 = '' OR target_id = $5)
+		  AND ($6::timestamptz IS NULL OR created_at >= $6)
+		  AND ($7::timestamptz IS NULL OR created_at < $7)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $8
+	`, f.ClubID, f.ActorID, string(f.Action), f.TargetType, f.TargetID, timeArg(f.Since), timeArg(f.Until), f.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []model.AuditEvent{}
+	for rows.Next() {
+		var e model.AuditEvent
+		var before, after []byte
+		if err := rows.Scan(&e.ID, &e.ActorID, &e.ClubID, &e.Action, &e.TargetType, &e.TargetID,
+			&before, &after, &e.RequestID, &e.IP, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.Before, e.After = before, after
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+// jsonArg maps an empty document to NULL
+func jsonArg(doc []byte) interface{} {
+	if len(doc) == 0 {
+		return nil
+	}
+	return string(doc)
+}
+
+// timeArg maps the zero time to NULL
+func timeArg(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,238 @@
+package database
+
+import (
+	"backend/model"
+	"context"
+	"database/sql"
+)
+
+// clubRepo implements ClubRepository on PostgreSQL
+type clubRepo struct {
+	q dbtx
+}
+
+const clubColumns = `id, slug, name, auto_dispatch, created_at`
+
+const memberColumns = `cm.club_id, cm.user_id, u.name, u.username, cm.role, cm.joined_at`
+
+func scanClub(row interface{ Scan(...interface{}) error }) (*model.Club, error) {
+	var c model.Club
+	err := row.Scan(&c.ID, &c.Slug, &c.Name, &c.AutoDispatch, &c.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func scanMember(row interface{ Scan(...interface{}) error }) (*model.ClubMember, error) {
+	var m model.ClubMember
+	err := row.Scan(&m.ClubID, &m.UserID, &m.Name, &m.Username, &m.Role, &m.JoinedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Create inserts a club
+func (r *clubRepo) Create(ctx context.Context, club *model.Club) error {
+	err := r.q.QueryRowContext(ctx, `
+		INSERT INTO clubs (slug, name, created_at) VALUES ($1, $2, NOW())
+		RETURNING id, created_at
+	`, club.Slug, club.Name).Scan(&club.ID, &club.CreatedAt)
+	return duplicateErr(err)
+}
+
+// Get returns a club by ID
+func (r *clubRepo) Get(ctx context.Context, id int64) (*model.Club, error) {
+	return scanClub(r.q.QueryRowContext(ctx, `
+		SELECT `+clubColumns+` FROM clubs WHERE id = $1
+	`, id))
+}
+
+// GetBySlug returns a club by its slug
+func (r *clubRepo) GetBySlug(ctx context.Context, slug string) (*model.Club, error) {
+	return scanClub(r.q.QueryRowContext(ctx, `
+		SELECT `+clubColumns+` FROM clubs WHERE slug = $1
+	`, slug))
+}
+
+// List returns every club by name
+func (r *clubRepo) List(ctx context.Context) ([]model.Club, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT `+clubColumns+` FROM clubs ORDER BY name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	clubs := []model.Club{}
+	for rows.Next() {
+		c, err := scanClub(rows)
+		if err != nil {
+			return nil, err
+		}
+		clubs = append(clubs, *c)
+	}
+
+	return clubs, rows.Err()
+}
+
+// ListForUser returns the clubs a user belongs to with their role in each
+func (r *clubRepo) ListForUser(ctx context.Context, userID int64) ([]model.ClubMembership, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT c.id, c.slug, c.name, c.auto_dispatch, c.created_at, cm.role
+		FROM club_members cm
+		JOIN clubs c ON c.id = cm.club_id
+		WHERE cm.user_id = $1
+		ORDER BY c.name
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	clubs := []model.ClubMembership{}
+	for rows.Next() {
+		var m model.ClubMembership
+		if err := rows.Scan(&m.ID, &m.Slug, &m.Name, &m.AutoDispatch, &m.CreatedAt, &m.Role); err != nil {
+			return nil, err
+		}
+		clubs = append(clubs, m)
+	}
+
+	return clubs, rows.Err()
+}
+
+// SetAutoDispatch stores whether the club refills freed courts automatically
+func (r *clubRepo) SetAutoDispatch(ctx context.Context, id int64, enabled bool) error {
+	result, err := r.q.ExecContext(ctx, `UPDATE clubs SET auto_dispatch = $2 WHERE id = $1`, id, enabled)
+	return expectRow(result, err)
+}
+
+// Member returns a user's membership in a club
+func (r *clubRepo) Member(ctx context.Context, clubID, userID int64) (*model.ClubMember, error) {
+	return scanMember(r.q.QueryRowContext(ctx, `
+		SELECT `+memberColumns+`
+		FROM club_members cm
+		JOIN users u ON u.id = cm.user_id
+		WHERE cm.club_id = $1 AND cm.user_id = $2
+	`, clubID, userID))
+}
+
+// Members returns a club's members by name
+func (r *clubRepo) Members(ctx context.Context, clubID int64) ([]model.ClubMember, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT `+memberColumns+`
+		FROM club_members cm
+		JOIN users u ON u.id = cm.user_id
+		WHERE cm.club_id = $1
+		ORDER BY u.name
+	`, clubID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []model.ClubMember{}
+	for rows.Next() {
+		m, err := scanMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, *m)
+	}
+
+	return members, rows.Err()
+}
+
+// SetMember adds a user to a club or changes their role there
+func (r *clubRepo) SetMember(ctx context.Context, clubID, userID int64, role model.Role) error {
+	_, err := r.q.ExecContext(ctx, `
+		INSERT INTO club_members (club_id, user_id, role, joined_at) VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (club_id, user_id) DO UPDATE SET role = EXCLUDED.role
+	`, clubID, userID, role)
+	return err
+}
+
+// RemoveMember takes a user out of a club
+func (r *clubRepo) RemoveMember(ctx context.Context, clubID, userID int64) error {
+	result, err := r.q.ExecContext(ctx, `
+		DELETE FROM club_members WHERE club_id = $1 AND user_id = $2
+	`, clubID, userID)
+	return expectRow(result, err)
+}
+
+const inviteColumns = `ci.club_id, c.name, ci.user_id, ci.role, ci.invited_by, ci.created_at`
+
+func scanInvite(row interface{ Scan(...interface{}) error }) (*model.ClubInvite, error) {
+	var inv model.ClubInvite
+	err := row.Scan(&inv.ClubID, &inv.ClubName, &inv.UserID, &inv.Role, &inv.InvitedBy, &inv.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// Invite stores an invite to the club, replacing any earlier one for the user
+func (r *clubRepo) Invite(ctx context.Context, invite *model.ClubInvite) error {
+	return r.q.QueryRowContext(ctx, `
+		INSERT INTO club_invites (club_id, user_id, role, invited_by, created_at) VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (club_id, user_id) DO UPDATE
+		SET role = EXCLUDED.role, invited_by = EXCLUDED.invited_by, created_at = EXCLUDED.created_at
+		RETURNING created_at
+	`, invite.ClubID, invite.UserID, invite.Role, invite.InvitedBy).Scan(&invite.CreatedAt)
+}
+
+// GetInvite returns a user's pending invite to a club
+func (r *clubRepo) GetInvite(ctx context.Context, clubID, userID int64) (*model.ClubInvite, error) {
+	return scanInvite(r.q.QueryRowContext(ctx, `
+		SELECT `+inviteColumns+`
+		FROM club_invites ci
+		JOIN clubs c ON c.id = ci.club_id
+		WHERE ci.club_id = $1 AND ci.user_id = $2
+	`, clubID, userID))
+}
+
+// Invites returns a user's pending invites, newest first
+func (r *clubRepo) Invites(ctx context.Context, userID int64) ([]model.ClubInvite, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT `+inviteColumns+`
+		FROM club_invites ci
+		JOIN clubs c ON c.id = ci.club_id
+		WHERE ci.user_id = $1
+		ORDER BY ci.created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invites := []model.ClubInvite{}
+	for rows.Next() {
+		inv, err := scanInvite(rows)
+		if err != nil {
+			return nil, err
+		}
+		invites = append(invites, *inv)
+	}
+
+	return invites, rows.Err()
+}
+
+// DeleteInvite removes a pending invite
+func (r *clubRepo) DeleteInvite(ctx context.Context, clubID, userID int64) error {
+	result, err := r.q.ExecContext(ctx, `
+		DELETE FROM club_invites WHERE club_id = $1 AND user_id = $2
+	`, clubID, userID)
+	return expectRow(result, err)
+}
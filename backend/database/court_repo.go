@@ -0,0 +1,117 @@
+package database
+
+import (
+	"backend/model"
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+// courtRepo implements CourtRepository on PostgreSQL
+type courtRepo struct {
+	q dbtx
+}
+
+const courtColumns = `id, name, venue, status, surface, created_at, updated_at`
+
+func scanCourt(row interface{ Scan(...interface{}) error }) (*model.Court, error) {
+	var c model.Court
+	err := row.Scan(&c.ID, &c.Name, &c.Venue, &c.Status, &c.Surface, &c.CreatedAt, &c.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Create inserts a court
+func (r *courtRepo) Create(ctx context.Context, clubID int64, c *model.Court) error {
+	err := r.q.QueryRowContext(ctx, `
+		INSERT INTO courts (club_id, name, venue, status, surface, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`, clubID, c.Name, c.Venue, c.Status, c.Surface).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+	return duplicateErr(err)
+}
+
+// Get returns one of a club's courts by ID
+func (r *courtRepo) Get(ctx context.Context, clubID, id int64) (*model.Court, error) {
+	return scanCourt(r.q.QueryRowContext(ctx, `
+		SELECT `+courtColumns+` FROM courts WHERE club_id = $1 AND id = $2
+	`, clubID, id))
+}
+
+// GetByName returns one of a club's courts by name
+func (r *courtRepo) GetByName(ctx context.Context, clubID int64, name string) (*model.Court, error) {
+	return scanCourt(r.q.QueryRowContext(ctx, `
+		SELECT `+courtColumns+` FROM courts WHERE club_id = $1 AND name = $2
+	`, clubID, name))
+}
+
+// List returns a club's courts by venue and name
+func (r *courtRepo) List(ctx context.Context, clubID int64, activeOnly bool) ([]model.Court, error) {
+	query := `SELECT ` + courtColumns + ` FROM courts WHERE club_id = $1`
+	if activeOnly {
+		query += ` AND status = 'active'`
+	}
+	query += ` ORDER BY venue, LENGTH(name), name`
+
+	rows, err := r.q.QueryContext(ctx, query, clubID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	courts := []model.Court{}
+	for rows.Next() {
+		c, err := scanCourt(rows)
+		if err != nil {
+			return nil, err
+		}
+		courts = append(courts, *c)
+	}
+
+	return courts, rows.Err()
+}
+
+// Update stores a court's details and renames the court on its pending matches
+func (r *courtRepo) Update(ctx context.Context, clubID int64, c *model.Court) error {
+	err := r.q.QueryRowContext(ctx, `
+		UPDATE courts SET name = $3, venue = $4, status = $5, surface = $6, updated_at = NOW()
+		WHERE id = $1 AND club_id = $2
+		RETURNING created_at, updated_at
+	`, c.ID, clubID, c.Name, c.Venue, c.Status, c.Surface).Scan(&c.CreatedAt, &c.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return duplicateErr(err)
+	}
+
+	_, err = r.q.ExecContext(ctx, `
+		UPDATE matches SET court = $2 WHERE court_id = $1 AND result = 'pending'
+	`, c.ID, c.Name)
+	return err
+}
+
+// Delete removes one of a club's courts
+func (r *courtRepo) Delete(ctx context.Context, clubID, id int64) error {
+	result, err := r.q.ExecContext(ctx, `DELETE FROM courts WHERE id = $1 AND club_id = $2`, id, clubID)
+	return expectRow(result, err)
+}
+
+// NextAvailable returns the club's first active court without a pending match
+func (r *courtRepo) NextAvailable(ctx context.Context, clubID int64, ids []int64) (*model.Court, error) {
+	return scanCourt(r.q.QueryRowContext(ctx, `
+		SELECT c.id, c.name, c.venue, c.status, c.surface, c.created_at, c.updated_at
+		FROM courts c
+		WHERE c.club_id = $1 AND c.status = 'active'
+		  AND (cardinality($2::integer[]) = 0 OR c.id = ANY($2))
+		  AND NOT EXISTS (SELECT 1 FROM matches m WHERE m.court_id = c.id AND m.result = 'pending')
+		ORDER BY c.venue, LENGTH(c.name), c.name
+		LIMIT 1
+	`, clubID, pq.Array(ids)))
+}
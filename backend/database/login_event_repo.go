@@ -0,0 +1,47 @@
+package database
+
+import (
+	"backend/model"
+	"context"
+)
+
+// loginEventRepo implements LoginEventRepository on PostgreSQL
+type loginEventRepo struct {
+	q dbtx
+}
+
+// Create records a login event
+func (r *loginEventRepo) Create(ctx context.Context, event *model.LoginEvent) error {
+	return r.q.QueryRowContext(ctx, `
+		INSERT INTO login_events (user_id, login, kind, ip, user_agent, detail, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING id, created_at
+	`, event.UserID, event.Login, string(event.Kind), event.IP, event.UserAgent, event.Detail).Scan(&event.ID, &event.CreatedAt)
+}
+
+// List returns login events matching the filter, newest first
+func (r *loginEventRepo) List(ctx context.Context, f LoginEventFilter) ([]model.LoginEvent, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT id, user_id, login, kind, ip, user_agent, detail, created_at
+		FROM login_events
+		WHERE ($1 = 0 OR user_id = $1)
+		  AND ($2 = '' OR kind = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`, f.UserID, string(f.Kind), f.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []model.LoginEvent{}
+	for rows.Next() {
+		var e model.LoginEvent
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Login, &e.Kind, &e.IP, &e.UserAgent, &e.Detail, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
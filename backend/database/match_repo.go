@@ -0,0 +1,364 @@
+package database
+
+import (
+	"backend/model"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// matchRepo implements MatchRepository on PostgreSQL
+type matchRepo struct {
+	q dbtx
+}
+
+const matchColumns = `
+	id, club_id, session_id, court_id, court, team1, team2, result,
+	COALESCE(points_to_win, 21), COALESCE(point_cap, 30), COALESCE(best_of, 3),
+	started_at, ended_at, created_at`
+
+func scanMatch(row interface{ Scan(...interface{}) error }) (*model.Match, error) {
+	var m model.Match
+	err := row.Scan(
+		&m.ID, &m.ClubID, &m.SessionID, &m.CourtID, &m.Court, pq.Array(&m.Team1), pq.Array(&m.Team2), &m.Result,
+		&m.Scoring.PointsToWin, &m.Scoring.PointCap, &m.Scoring.BestOf,
+		&m.StartedAt, &m.EndedAt, &m.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// list runs a match query and loads each match's game scores
+func (r *matchRepo) list(ctx context.Context, query string, args ...interface{}) ([]model.Match, error) {
+	rows, err := r.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []model.Match{}
+	for rows.Next() {
+		m, err := scanMatch(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		matches = append(matches, *m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range matches {
+		if matches[i].Scores, err = r.scores(ctx, matches[i].ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return matches, nil
+}
+
+func (r *matchRepo) scores(ctx context.Context, matchID int64) ([]model.GameScore, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT game_number, team1_score, team2_score
+		FROM match_scores WHERE match_id = $1 ORDER BY game_number
+	`, matchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scores []model.GameScore
+	for rows.Next() {
+		var score model.GameScore
+		if err := rows.Scan(&score.Game, &score.Team1Score, &score.Team2Score); err != nil {
+			return nil, err
+		}
+		scores = append(scores, score)
+	}
+
+	return scores, rows.Err()
+}
+
+// Create inserts a pending match
+func (r *matchRepo) Create(ctx context.Context, match *model.Match) error {
+	created, err := scanMatch(r.q.QueryRowContext(ctx, `
+		INSERT INTO matches (club_id, session_id, court_id, court, team1, team2, result, points_to_win, point_cap, best_of, started_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, 'pending', $7, $8, $9, NOW(), NOW())
+		RETURNING `+matchColumns,
+		match.ClubID, match.SessionID, match.CourtID, match.Court, pq.Array(match.Team1), pq.Array(match.Team2),
+		match.Scoring.PointsToWin, match.Scoring.PointCap, match.Scoring.BestOf))
+	if err != nil {
+		return duplicateErr(err)
+	}
+
+	*match = *created
+	return nil
+}
+
+// GetForUpdate returns a club's match and locks it so concurrent updates serialize
+func (r *matchRepo) GetForUpdate(ctx context.Context, clubID, id int64) (*model.Match, error) {
+	match, err := scanMatch(r.q.QueryRowContext(ctx, `
+		SELECT `+matchColumns+` FROM matches WHERE id = $1 AND club_id = $2
+		FOR UPDATE
+	`, id, clubID))
+	if err != nil {
+		return nil, err
+	}
+
+	if match.Scores, err = r.scores(ctx, id); err != nil {
+		return nil, err
+	}
+	return match, nil
+}
+
+// Complete stores the result and game scores of a match
+func (r *matchRepo) Complete(ctx context.Context, id int64, result string, endedAt time.Time, scores []model.GameScore) error {
+	if _, err := r.q.ExecContext(ctx, `
+		UPDATE matches SET result = $2, ended_at = $3 WHERE id = $1
+	`, id, result, endedAt); err != nil {
+		return err
+	}
+
+	return r.insertScores(ctx, id, scores)
+}
+
+// Amend replaces the result and game scores of a finished match
+func (r *matchRepo) Amend(ctx context.Context, id int64, result string, scores []model.GameScore) error {
+	updated, err := r.q.ExecContext(ctx, `UPDATE matches SET result = $2 WHERE id = $1`, id, result)
+	if err := expectRow(updated, err); err != nil {
+		return err
+	}
+
+	if _, err := r.q.ExecContext(ctx, `DELETE FROM match_scores WHERE match_id = $1`, id); err != nil {
+		return err
+	}
+	return r.insertScores(ctx, id, scores)
+}
+
+// insertScores stores a match's games, numbered in order
+func (r *matchRepo) insertScores(ctx context.Context, id int64, scores []model.GameScore) error {
+	for i, score := range scores {
+		if _, err := r.q.ExecContext(ctx, `
+			INSERT INTO match_scores (match_id, game_number, team1_score, team2_score)
+			VALUES ($1, $2, $3, $4)
+		`, id, i+1, score.Team1Score, score.Team2Score); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddCorrection records a change to a finished match
+func (r *matchRepo) AddCorrection(ctx context.Context, c *model.MatchCorrection) error {
+	before, err := json.Marshal(c.ScoresBefore)
+	if err != nil {
+		return err
+	}
+	after, err := json.Marshal(c.ScoresAfter)
+	if err != nil {
+		return err
+	}
+
+	return r.q.QueryRowContext(ctx, `
+		INSERT INTO match_corrections (match_id, corrected_by, kind, reason, result_before, result_after,
+			scores_before, scores_after, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		RETURNING id, created_at
+	`, c.MatchID, c.CorrectedBy, string(c.Kind), c.Reason, c.ResultBefore, c.ResultAfter,
+		string(before), string(after)).Scan(&c.ID, &c.CreatedAt)
+}
+
+// Corrections returns the corrections of a club's match, oldest first
+func (r *matchRepo) Corrections(ctx context.Context, clubID, matchID int64) ([]model.MatchCorrection, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT c.id, c.match_id, c.corrected_by, c.kind, c.reason, c.result_before, c.result_after,
+			c.scores_before, c.scores_after, c.created_at
+		FROM match_corrections c
+		JOIN matches m ON m.id = c.match_id
+		WHERE c.match_id = $1 AND m.club_id = $2
+		ORDER BY c.created_at, c.id
+	`, matchID, clubID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	corrections := []model.MatchCorrection{}
+	for rows.Next() {
+		var c model.MatchCorrection
+		var before, after []byte
+		if err := rows.Scan(&c.ID, &c.MatchID, &c.CorrectedBy, &c.Kind, &c.Reason, &c.ResultBefore,
+			&c.ResultAfter, &before, &after, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(before, &c.ScoresBefore); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(after, &c.ScoresAfter); err != nil {
+			return nil, err
+		}
+		corrections = append(corrections, c)
+	}
+
+	return corrections, rows.Err()
+}
+
+// DecidedFrom returns won or lost matches ending at or after the given match, oldest first
+func (r *matchRepo) DecidedFrom(ctx context.Context, endedAt time.Time, id int64) ([]model.Match, error) {
+	return r.list(ctx, `
+		SELECT `+matchColumns+` FROM matches
+		WHERE result IN ('team1', 'team2') AND (ended_at, id) >= ($1, $2)
+		ORDER BY ended_at, id
+	`, endedAt, id)
+}
+
+// Decided returns won or lost matches, oldest first
+func (r *matchRepo) Decided(ctx context.Context, f MatchFilter) ([]model.Match, error) {
+	return r.list(ctx, `
+		SELECT `+matchColumns+` FROM matches
+		WHERE result IN ('team1', 'team2')
+		  AND ($1 = 0 OR club_id = $1) AND ($2 = 0 OR session_id = $2)
+		  AND ($3 = 0 OR $3 = ANY(team1) OR $3 = ANY(team2))
+		  AND ($4::timestamptz IS NULL OR ended_at >= $4)
+		  AND ($5::timestamptz IS NULL OR ended_at < $5)
+		ORDER BY ended_at, id
+		LIMIT NULLIF($6, 0)
+	`, f.ClubID, f.SessionID, f.UserID, timeArg(f.Since), timeArg(f.Until), f.Limit)
+}
+
+// DecidedByID returns the won or lost matches among the given IDs, oldest first
+func (r *matchRepo) DecidedByID(ctx context.Context, ids []int64) ([]model.Match, error) {
+	return r.list(ctx, `
+		SELECT `+matchColumns+` FROM matches
+		WHERE id = ANY($1::integer[]) AND result IN ('team1', 'team2')
+		ORDER BY ended_at, id
+	`, pq.Array(ids))
+}
+
+// History returns matches a player took part in, newest first
+func (r *matchRepo) History(ctx context.Context, f MatchFilter) ([]model.Match, error) {
+	return r.list(ctx, `
+		SELECT `+matchColumns+` FROM matches
+		WHERE ($1 = ANY(team1) OR $1 = ANY(team2))
+		  AND club_id = $2 AND ($3 = 0 OR session_id = $3)
+		ORDER BY started_at DESC
+		LIMIT $4
+	`, f.UserID, f.ClubID, f.SessionID, f.Limit)
+}
+
+// Active returns pending matches, newest first
+func (r *matchRepo) Active(ctx context.Context, f MatchFilter) ([]model.Match, error) {
+	return r.list(ctx, `
+		SELECT `+matchColumns+` FROM matches
+		WHERE result = 'pending' AND club_id = $1 AND ($2 = 0 OR session_id = $2)
+		ORDER BY started_at DESC
+	`, f.ClubID, f.SessionID)
+}
+
+// Completed returns finished matches, most recently ended first
+func (r *matchRepo) Completed(ctx context.Context, f MatchFilter) ([]model.Match, error) {
+	return r.list(ctx, `
+		SELECT `+matchColumns+` FROM matches
+		WHERE result != 'pending' AND club_id = $1 AND ($2 = 0 OR session_id = $2)
+		ORDER BY ended_at DESC
+		LIMIT $3
+	`, f.ClubID, f.SessionID, f.Limit)
+}
+
+// PendingFor returns a player's unfinished matches across all clubs, newest first
+func (r *matchRepo) PendingFor(ctx context.Context, userID int64) ([]model.Match, error) {
+	return r.list(ctx, `
+		SELECT `+matchColumns+` FROM matches
+		WHERE result = 'pending' AND ($1 = ANY(team1) OR $1 = ANY(team2))
+		ORDER BY started_at DESC
+	`, userID)
+}
+
+// Played returns matches involving any of the players across all clubs, newest first
+func (r *matchRepo) Played(ctx context.Context, userIDs []int64, limit int) ([]model.Match, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT `+matchColumns+` FROM matches
+		WHERE team1 && $1::integer[] OR team2 && $1::integer[]
+		ORDER BY started_at DESC
+		LIMIT $2
+	`, pq.Array(userIDs), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []model.Match
+	for rows.Next() {
+		m, err := scanMatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, *m)
+	}
+
+	return matches, rows.Err()
+}
+
+// Outcomes returns whether the player won each of their decided club matches, oldest first.
+// Club 0 covers every club.
+func (r *matchRepo) Outcomes(ctx context.Context, clubID, userID int64) ([]bool, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT (result = 'team1') = ($1 = ANY(team1))
+		FROM matches
+		WHERE ($2 = 0 OR club_id = $2) AND result IN ('team1', 'team2')
+		  AND ($1 = ANY(team1) OR $1 = ANY(team2))
+		ORDER BY ended_at, id
+	`, userID, clubID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var outcomes []bool
+	for rows.Next() {
+		var won bool
+		if err := rows.Scan(&won); err != nil {
+			return nil, err
+		}
+		outcomes = append(outcomes, won)
+	}
+
+	return outcomes, rows.Err()
+}
+
+// Durations returns how long recent finished club matches took, most recent first
+func (r *matchRepo) Durations(ctx context.Context, clubID int64, limit int, max time.Duration) ([]time.Duration, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT EXTRACT(EPOCH FROM ended_at - started_at)
+		FROM matches
+		WHERE club_id = $1 AND result != 'pending' AND ended_at > started_at
+		  AND EXTRACT(EPOCH FROM ended_at - started_at) < $3
+		ORDER BY ended_at DESC
+		LIMIT $2
+	`, clubID, limit, max.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var durations []time.Duration
+	for rows.Next() {
+		var seconds float64
+		if err := rows.Scan(&seconds); err != nil {
+			return nil, err
+		}
+		durations = append(durations, time.Duration(seconds*float64(time.Second)))
+	}
+
+	return durations, rows.Err()
+}
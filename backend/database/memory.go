@@ -0,0 +1,2111 @@
+package database
+
+import (
+	"backend/model"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryDefaultClubID is the default club new users join in a MemoryStore
+const MemoryDefaultClubID int64 = 1
+
+// MemoryStore implements Store in memory so services can run without Postgres.
+// Transactions hold the store's lock and restore a snapshot when they fail.
+type MemoryStore struct {
+	mu   *sync.Mutex
+	data *memoryData
+	inTx bool
+}
+
+type memoryData struct {
+	lastID        int64
+	users         map[int64]model.User
+	clubs         map[int64]model.Club
+	members       map[int64]map[int64]model.ClubMember // club ID -> user ID -> membership
+	invites       []model.ClubInvite
+	courts        map[int64]memoryCourt
+	sessions      map[int64]model.Session
+	stats         map[int64]model.UserStats
+	ratingHistory []model.RatingChange
+	tokens        map[int64]model.RefreshToken
+	resets        map[int64]model.PasswordReset
+	loginEvents   []model.LoginEvent
+	audit         []model.AuditEvent
+	queue         []memoryQueueEntry
+	matches       map[int64]model.Match
+	corrections   []model.MatchCorrection
+	seasons       map[int64]model.Season
+	seasonStats   map[int64]map[int64]model.SeasonStats // season ID -> user ID -> record
+	tournaments   map[int64]model.Tournament
+	entries       map[int64]model.TournamentEntry
+	brackets      map[int64]model.BracketMatch
+	fixtures      map[int64]model.Fixture
+}
+
+type memoryCourt struct {
+	clubID int64
+	model.Court
+}
+
+type memoryQueueEntry struct {
+	clubID int64
+	model.QueueEntry
+}
+
+// NewMemoryStore creates a store holding only the default club
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		mu: &sync.Mutex{},
+		data: &memoryData{
+			lastID: MemoryDefaultClubID,
+			users:  make(map[int64]model.User),
+			clubs: map[int64]model.Club{
+				MemoryDefaultClubID: {ID: MemoryDefaultClubID, Slug: model.DefaultClubSlug, Name: "SmashQueue", CreatedAt: time.Now()},
+			},
+			members:     map[int64]map[int64]model.ClubMember{MemoryDefaultClubID: {}},
+			courts:      make(map[int64]memoryCourt),
+			sessions:    make(map[int64]model.Session),
+			stats:       make(map[int64]model.UserStats),
+			tokens:      make(map[int64]model.RefreshToken),
+			resets:      make(map[int64]model.PasswordReset),
+			matches:     make(map[int64]model.Match),
+			seasons:     make(map[int64]model.Season),
+			seasonStats: make(map[int64]map[int64]model.SeasonStats),
+			tournaments: make(map[int64]model.Tournament),
+			entries:     make(map[int64]model.TournamentEntry),
+			brackets:    make(map[int64]model.BracketMatch),
+			fixtures:    make(map[int64]model.Fixture),
+		},
+	}
+}
+
+// AddMember puts a user in a club with the given role
+func (s *MemoryStore) AddMember(clubID, userID int64, role model.Role) {
+	defer s.lock()()
+	s.data.setMember(clubID, userID, role)
+}
+
+func (d *memoryData) setMember(clubID, userID int64, role model.Role) {
+	if d.members[clubID] == nil {
+		d.members[clubID] = make(map[int64]model.ClubMember)
+	}
+	m, ok := d.members[clubID][userID]
+	if !ok {
+		m = model.ClubMember{ClubID: clubID, UserID: userID, JoinedAt: time.Now()}
+	}
+	m.Role = role
+	d.members[clubID][userID] = m
+}
+
+func (s *MemoryStore) Users() UserRepository             { return &memoryUsers{s} }
+func (s *MemoryStore) Stats() StatsRepository            { return &memoryStats{s} }
+func (s *MemoryStore) Tokens() TokenRepository           { return &memoryTokens{s} }
+func (s *MemoryStore) Resets() ResetRepository           { return &memoryResets{s} }
+func (s *MemoryStore) LoginEvents() LoginEventRepository { return &memoryLoginEvents{s} }
+func (s *MemoryStore) Audit() AuditRepository            { return &memoryAudit{s} }
+func (s *MemoryStore) Queue() QueueRepository            { return &memoryQueue{s} }
+func (s *MemoryStore) Matches() MatchRepository          { return &memoryMatches{s} }
+func (s *MemoryStore) Seasons() SeasonRepository         { return &memorySeasons{s} }
+func (s *MemoryStore) Tournaments() TournamentRepository { return &memoryTournaments{s} }
+func (s *MemoryStore) Clubs() ClubRepository             { return &memoryClubs{s} }
+func (s *MemoryStore) Courts() CourtRepository           { return &memoryCourts{s} }
+func (s *MemoryStore) Sessions() SessionRepository       { return &memorySessions{s} }
+
+// WithTx runs fn holding the store lock and rolls back every change if it fails
+func (s *MemoryStore) WithTx(ctx context.Context, fn func(tx Store) error) error {
+	if s.inTx {
+		return fn(s)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := s.data.clone()
+	if err := fn(&MemoryStore{mu: s.mu, data: s.data, inTx: true}); err != nil {
+		*s.data = *snapshot
+		return err
+	}
+
+	return nil
+}
+
+// lock takes the store lock unless a transaction already holds it, returning the unlock
+func (s *MemoryStore) lock() func() {
+	if s.inTx {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+func (d *memoryData) nextID() int64 {
+	d.lastID++
+	return d.lastID
+}
+
+func (d *memoryData) clone() *memoryData {
+	c := *d
+
+	c.users = make(map[int64]model.User, len(d.users))
+	for id, u := range d.users {
+		c.users[id] = u
+	}
+	c.clubs = make(map[int64]model.Club, len(d.clubs))
+	for id, club := range d.clubs {
+		c.clubs[id] = club
+	}
+	c.members = make(map[int64]map[int64]model.ClubMember, len(d.members))
+	for clubID, members := range d.members {
+		c.members[clubID] = make(map[int64]model.ClubMember, len(members))
+		for userID, m := range members {
+			c.members[clubID][userID] = m
+		}
+	}
+	c.invites = append([]model.ClubInvite(nil), d.invites...)
+	c.courts = make(map[int64]memoryCourt, len(d.courts))
+	for id, court := range d.courts {
+		c.courts[id] = court
+	}
+	c.sessions = make(map[int64]model.Session, len(d.sessions))
+	for id, sess := range d.sessions {
+		sess.CourtIDs = append([]int64{}, sess.CourtIDs...)
+		c.sessions[id] = sess
+	}
+	c.stats = make(map[int64]model.UserStats, len(d.stats))
+	for id, st := range d.stats {
+		c.stats[id] = st
+	}
+	c.ratingHistory = append([]model.RatingChange(nil), d.ratingHistory...)
+	c.tokens = make(map[int64]model.RefreshToken, len(d.tokens))
+	for id, t := range d.tokens {
+		c.tokens[id] = t
+	}
+	c.resets = make(map[int64]model.PasswordReset, len(d.resets))
+	for id, r := range d.resets {
+		c.resets[id] = r
+	}
+	c.loginEvents = append([]model.LoginEvent(nil), d.loginEvents...)
+	c.audit = append([]model.AuditEvent(nil), d.audit...)
+	c.queue = append([]memoryQueueEntry(nil), d.queue...)
+	c.matches = make(map[int64]model.Match, len(d.matches))
+	for id, m := range d.matches {
+		c.matches[id] = copyMatch(m)
+	}
+	c.corrections = append([]model.MatchCorrection(nil), d.corrections...)
+	c.seasons = make(map[int64]model.Season, len(d.seasons))
+	for id, season := range d.seasons {
+		c.seasons[id] = season
+	}
+	c.tournaments = make(map[int64]model.Tournament, len(d.tournaments))
+	for id, t := range d.tournaments {
+		t.Courts = append([]string(nil), t.Courts...)
+		c.tournaments[id] = t
+	}
+	c.entries = make(map[int64]model.TournamentEntry, len(d.entries))
+	for id, e := range d.entries {
+		e.Players = append([]int64(nil), e.Players...)
+		c.entries[id] = e
+	}
+	c.brackets = make(map[int64]model.BracketMatch, len(d.brackets))
+	for id, m := range d.brackets {
+		c.brackets[id] = m
+	}
+	c.fixtures = make(map[int64]model.Fixture, len(d.fixtures))
+	for id, f := range d.fixtures {
+		c.fixtures[id] = f
+	}
+	c.seasonStats = make(map[int64]map[int64]model.SeasonStats, len(d.seasonStats))
+	for seasonID, players := range d.seasonStats {
+		c.seasonStats[seasonID] = make(map[int64]model.SeasonStats, len(players))
+		for userID, p := range players {
+			c.seasonStats[seasonID][userID] = p
+		}
+	}
+
+	return &c
+}
+
+// copyMatch duplicates a match's slices so callers cannot modify stored data
+func copyMatch(m model.Match) model.Match {
+	if m.CourtID != nil {
+		m.CourtID = int64Ptr(*m.CourtID)
+	}
+	m.Team1 = append([]int64(nil), m.Team1...)
+	m.Team2 = append([]int64(nil), m.Team2...)
+	m.Scores = append([]model.GameScore(nil), m.Scores...)
+	m.NextMatch = nil
+	return m
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func hasID(ids []int64, id int64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// memoryUsers implements UserRepository in memory
+type memoryUsers struct{ s *MemoryStore }
+
+func (r *memoryUsers) Create(ctx context.Context, user *model.User) error {
+	defer r.s.lock()()
+	d := r.s.data
+
+	for _, u := range d.users {
+		if u.Username == user.Username {
+			return fmt.Errorf("username %q already exists", user.Username)
+		}
+	}
+
+	now := time.Now()
+	user.ID = d.nextID()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	d.users[user.ID] = *user
+	d.stats[user.ID] = *newStats(user.ID)
+	d.setMember(MemoryDefaultClubID, user.ID, user.Role)
+
+	return nil
+}
+
+func (r *memoryUsers) GetByID(ctx context.Context, id int64) (*model.User, error) {
+	defer r.s.lock()()
+	u, ok := r.s.data.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &u, nil
+}
+
+func (r *memoryUsers) GetForUpdate(ctx context.Context, id int64) (*model.User, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *memoryUsers) GetByLogin(ctx context.Context, login string) (*model.User, error) {
+	defer r.s.lock()()
+	var byPhone *model.User
+	for _, u := range r.s.data.users {
+		u := u
+		if u.Username == login {
+			return &u, nil
+		}
+		if login != "" && u.Phone == login {
+			byPhone = &u
+		}
+	}
+	if byPhone == nil {
+		return nil, ErrNotFound
+	}
+	return byPhone, nil
+}
+
+func (r *memoryUsers) GetByUsername(ctx context.Context, username string) (*model.User, error) {
+	defer r.s.lock()()
+	for _, u := range r.s.data.users {
+		if u.Username == username {
+			return &u, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryUsers) GetMany(ctx context.Context, ids []int64) ([]model.User, error) {
+	defer r.s.lock()()
+	var users []model.User
+	for _, id := range uniqueIDs(ids) {
+		if u, ok := r.s.data.users[id]; ok {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+func (r *memoryUsers) UsernameExists(ctx context.Context, username string) (bool, error) {
+	_, err := r.GetByUsername(ctx, username)
+	return err == nil, nil
+}
+
+func (r *memoryUsers) PhoneExists(ctx context.Context, phone string) (bool, error) {
+	defer r.s.lock()()
+	for _, u := range r.s.data.users {
+		if u.Phone == phone {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryUsers) UpdateProfile(ctx context.Context, id int64, req model.UpdateProfileRequest) (*model.User, error) {
+	defer r.s.lock()()
+	u, ok := r.s.data.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	u.Name = req.Name
+	u.Bio = req.Bio
+	if req.Phone != "" {
+		u.Phone = req.Phone
+	}
+	u.UpdatedAt = time.Now()
+	r.s.data.users[id] = u
+	return &u, nil
+}
+
+func (r *memoryUsers) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	defer r.s.lock()()
+	u, ok := r.s.data.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	u.PasswordHash = passwordHash
+	u.UpdatedAt = time.Now()
+	r.s.data.users[id] = u
+	return nil
+}
+
+func (r *memoryUsers) UpdatePlayer(ctx context.Context, clubID, id int64, hand model.HandPreference, tier model.SkillTier) error {
+	defer r.s.lock()()
+	u, ok := r.s.data.users[id]
+	if _, member := r.s.data.members[clubID][id]; !ok || !member {
+		return ErrNotFound
+	}
+	u.HandPreference = hand
+	u.SkillTier = tier
+	u.UpdatedAt = time.Now()
+	r.s.data.users[id] = u
+	return nil
+}
+
+func (r *memoryUsers) RecordLogin(ctx context.Context, id int64) error {
+	defer r.s.lock()()
+	if u, ok := r.s.data.users[id]; ok {
+		u.LastLoginAt = timePtr(time.Now())
+		u.FailedLogins = 0
+		u.LockedUntil = nil
+		r.s.data.users[id] = u
+	}
+	return nil
+}
+
+func (r *memoryUsers) RecordFailedLogin(ctx context.Context, id int64) (int, error) {
+	defer r.s.lock()()
+	u, ok := r.s.data.users[id]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	u.FailedLogins++
+	u.LastFailedAt = timePtr(time.Now())
+	r.s.data.users[id] = u
+	return u.FailedLogins, nil
+}
+
+func (r *memoryUsers) Lock(ctx context.Context, id int64, until time.Time) error {
+	defer r.s.lock()()
+	u, ok := r.s.data.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	u.LockedUntil = timePtr(until)
+	r.s.data.users[id] = u
+	return nil
+}
+
+func (r *memoryUsers) Unlock(ctx context.Context, id int64) error {
+	defer r.s.lock()()
+	u, ok := r.s.data.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	u.FailedLogins = 0
+	u.LockedUntil = nil
+	r.s.data.users[id] = u
+	return nil
+}
+
+func (r *memoryUsers) SetRole(ctx context.Context, id int64, role model.Role) error {
+	defer r.s.lock()()
+	u, ok := r.s.data.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	u.Role = role
+	u.UpdatedAt = time.Now()
+	r.s.data.users[id] = u
+	return nil
+}
+
+func (r *memoryUsers) SetActive(ctx context.Context, id int64, active bool) error {
+	defer r.s.lock()()
+	u, ok := r.s.data.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	u.IsActive = active
+	u.UpdatedAt = time.Now()
+	r.s.data.users[id] = u
+	return nil
+}
+
+func (r *memoryUsers) Anonymize(ctx context.Context, id int64, username, passwordHash string) error {
+	defer r.s.lock()()
+	d := r.s.data
+	u, ok := d.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	d.users[id] = model.User{
+		ID:             u.ID,
+		Username:       username,
+		PasswordHash:   passwordHash,
+		Name:           "Deleted player",
+		Role:           model.RolePlayer,
+		HandPreference: u.HandPreference,
+		SkillTier:      u.SkillTier,
+		CreatedAt:      u.CreatedAt,
+		UpdatedAt:      time.Now(),
+	}
+
+	d.forgetUser(id)
+	return nil
+}
+
+func (r *memoryUsers) Delete(ctx context.Context, id int64) error {
+	defer r.s.lock()()
+	d := r.s.data
+	if _, ok := d.users[id]; !ok {
+		return ErrNotFound
+	}
+	delete(d.users, id)
+	delete(d.stats, id)
+	for _, players := range d.seasonStats {
+		delete(players, id)
+	}
+	d.forgetUser(id)
+	history := d.ratingHistory[:0]
+	for _, c := range d.ratingHistory {
+		if c.UserID != id {
+			history = append(history, c)
+		}
+	}
+	d.ratingHistory = history
+	queue := d.queue[:0]
+	for _, e := range d.queue {
+		if e.UserID != id {
+			queue = append(queue, e)
+		}
+	}
+	d.queue = queue
+	return nil
+}
+
+// forgetUser drops a user's login history, reset codes, refresh tokens, club memberships
+// and invites
+func (d *memoryData) forgetUser(id int64) {
+	events := d.loginEvents[:0]
+	for _, e := range d.loginEvents {
+		if e.UserID == nil || *e.UserID != id {
+			events = append(events, e)
+		}
+	}
+	d.loginEvents = events
+	for resetID, reset := range d.resets {
+		if reset.UserID == id {
+			delete(d.resets, resetID)
+		}
+	}
+	for tokenID, t := range d.tokens {
+		if t.UserID == id {
+			delete(d.tokens, tokenID)
+		}
+	}
+	for _, members := range d.members {
+		delete(members, id)
+	}
+	invites := d.invites[:0]
+	for _, inv := range d.invites {
+		if inv.UserID != id {
+			invites = append(invites, inv)
+		}
+	}
+	d.invites = invites
+}
+
+func (r *memoryUsers) CountActiveAdmins(ctx context.Context) (int, error) {
+	defer r.s.lock()()
+	count := 0
+	for _, u := range r.s.data.users {
+		if u.Role == model.RoleAdmin && u.IsActive {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryUsers) ListByClub(ctx context.Context, clubID int64) ([]model.UserListItem, error) {
+	defer r.s.lock()()
+	var users []model.UserListItem
+	for userID, m := range r.s.data.members[clubID] {
+		u, ok := r.s.data.users[userID]
+		if !ok {
+			continue
+		}
+		st, ok := r.s.data.stats[userID]
+		if !ok {
+			st = *newStats(userID)
+		}
+		var lockedUntil *time.Time
+		if u.LockedUntil != nil && u.LockedUntil.After(time.Now()) {
+			lockedUntil = u.LockedUntil
+		}
+		users = append(users, model.UserListItem{
+			ID:             u.ID,
+			Username:       u.Username,
+			Name:           u.Name,
+			Phone:          u.Phone,
+			Role:           m.Role,
+			HandPreference: string(u.HandPreference),
+			SkillTier:      string(u.SkillTier),
+			IsActive:       u.IsActive,
+			LockedUntil:    lockedUntil,
+			SkillLevel:     st.SkillLevel,
+			WinRate:        st.WinRate,
+			TotalMatches:   st.TotalMatches,
+			Wins:           st.Wins,
+		})
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Name < users[j].Name })
+	return users, nil
+}
+
+func (r *memoryUsers) AllInClub(ctx context.Context, clubID int64, ids []int64) (bool, error) {
+	defer r.s.lock()()
+	for _, id := range ids {
+		if _, ok := r.s.data.members[clubID][id]; !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// memoryStats implements StatsRepository in memory
+type memoryStats struct{ s *MemoryStore }
+
+func (r *memoryStats) Get(ctx context.Context, userID int64) (*model.UserStats, error) {
+	defer r.s.lock()()
+	st, ok := r.s.data.stats[userID]
+	if !ok {
+		st = *newStats(userID)
+		r.s.data.stats[userID] = st
+	}
+	return &st, nil
+}
+
+func (r *memoryStats) GetMany(ctx context.Context, userIDs []int64) (map[int64]model.UserStats, error) {
+	defer r.s.lock()()
+	stats := make(map[int64]model.UserStats, len(userIDs))
+	for _, id := range userIDs {
+		if st, ok := r.s.data.stats[id]; ok {
+			stats[id] = st
+		}
+	}
+	return stats, nil
+}
+
+func (r *memoryStats) SaveRecord(ctx context.Context, stats *model.UserStats) error {
+	defer r.s.lock()()
+	st, ok := r.s.data.stats[stats.UserID]
+	if !ok {
+		st = *newStats(stats.UserID)
+	}
+	st.TotalMatches = stats.TotalMatches
+	st.Wins = stats.Wins
+	st.Losses = stats.Losses
+	st.WinRate = stats.WinRate
+	st.CurrentStreak = stats.CurrentStreak
+	st.BestStreak = stats.BestStreak
+	st.SkillLevel = stats.SkillLevel
+	st.SkillPoints = stats.SkillPoints
+	r.s.data.stats[stats.UserID] = st
+	return nil
+}
+
+func (r *memoryStats) SaveRating(ctx context.Context, change model.RatingChange, volatility float64) error {
+	defer r.s.lock()()
+	st, ok := r.s.data.stats[change.UserID]
+	if !ok {
+		st = *newStats(change.UserID)
+	}
+	st.Rating = change.RatingAfter
+	st.RatingDev = change.DeviationAfter
+	st.Volatility = volatility
+	r.s.data.stats[change.UserID] = st
+
+	change.Volatility = volatility
+	if change.CreatedAt.IsZero() {
+		change.CreatedAt = time.Now()
+	}
+	r.s.data.ratingHistory = append(r.s.data.ratingHistory, change)
+	return nil
+}
+
+func (r *memoryStats) RatingHistory(ctx context.Context, userID int64, limit int) ([]model.RatingChange, error) {
+	defer r.s.lock()()
+	history := []model.RatingChange{}
+	for i := len(r.s.data.ratingHistory) - 1; i >= 0 && (limit <= 0 || len(history) < limit); i-- {
+		if c := r.s.data.ratingHistory[i]; c.UserID == userID {
+			history = append(history, c)
+		}
+	}
+	return history, nil
+}
+
+func (r *memoryStats) LatestRatings(ctx context.Context, userIDs []int64) (map[int64]model.RatingChange, error) {
+	defer r.s.lock()()
+	latest := make(map[int64]model.RatingChange, len(userIDs))
+	for i := len(r.s.data.ratingHistory) - 1; i >= 0; i-- {
+		c := r.s.data.ratingHistory[i]
+		if _, seen := latest[c.UserID]; !seen && hasID(userIDs, c.UserID) {
+			latest[c.UserID] = c
+		}
+	}
+	return latest, nil
+}
+
+func (r *memoryStats) RatingChanges(ctx context.Context, matchIDs []int64) ([]model.RatingChange, error) {
+	defer r.s.lock()()
+	changes := []model.RatingChange{}
+	for _, c := range r.s.data.ratingHistory {
+		if hasID(matchIDs, c.MatchID) {
+			changes = append(changes, c)
+		}
+	}
+	return changes, nil
+}
+
+func (r *memoryStats) ClearRatingHistory(ctx context.Context, userID int64) error {
+	defer r.s.lock()()
+	kept := r.s.data.ratingHistory[:0]
+	for _, c := range r.s.data.ratingHistory {
+		if c.UserID != userID {
+			kept = append(kept, c)
+		}
+	}
+	r.s.data.ratingHistory = kept
+	return nil
+}
+
+func (r *memoryStats) Reset(ctx context.Context) error {
+	defer r.s.lock()()
+	r.s.data.ratingHistory = nil
+	for id := range r.s.data.stats {
+		r.s.data.stats[id] = *newStats(id)
+	}
+	return nil
+}
+
+func (r *memoryStats) DeleteRatingChanges(ctx context.Context, matchIDs []int64) error {
+	defer r.s.lock()()
+	kept := r.s.data.ratingHistory[:0]
+	for _, c := range r.s.data.ratingHistory {
+		if !hasID(matchIDs, c.MatchID) {
+			kept = append(kept, c)
+		}
+	}
+	r.s.data.ratingHistory = kept
+	return nil
+}
+
+func (r *memoryStats) SetRating(ctx context.Context, userID int64, rating, deviation, volatility float64) error {
+	defer r.s.lock()()
+	st, ok := r.s.data.stats[userID]
+	if !ok {
+		st = *newStats(userID)
+	}
+	st.Rating = rating
+	st.RatingDev = deviation
+	st.Volatility = volatility
+	r.s.data.stats[userID] = st
+	return nil
+}
+
+// memoryTokens implements TokenRepository in memory
+type memoryTokens struct{ s *MemoryStore }
+
+func (r *memoryTokens) Create(ctx context.Context, token *model.RefreshToken) error {
+	defer r.s.lock()()
+	for _, t := range r.s.data.tokens {
+		if t.TokenHash == token.TokenHash {
+			return fmt.Errorf("refresh token hash already exists")
+		}
+	}
+	token.ID = r.s.data.nextID()
+	token.CreatedAt = time.Now()
+	if token.SignedInAt.IsZero() {
+		token.SignedInAt = token.CreatedAt
+	}
+	r.s.data.tokens[token.ID] = *token
+	return nil
+}
+
+func (r *memoryTokens) GetByHash(ctx context.Context, hash string) (*model.RefreshToken, error) {
+	defer r.s.lock()()
+	for _, t := range r.s.data.tokens {
+		if t.TokenHash == hash {
+			return &t, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryTokens) Rotate(ctx context.Context, id int64) (bool, error) {
+	defer r.s.lock()()
+	t, ok := r.s.data.tokens[id]
+	if !ok || t.RotatedAt != nil || t.RevokedAt != nil {
+		return false, nil
+	}
+	t.RotatedAt = timePtr(time.Now())
+	r.s.data.tokens[id] = t
+	return true, nil
+}
+
+// revoke invalidates the live tokens that match
+func (r *memoryTokens) revoke(match func(t model.RefreshToken) bool) {
+	now := time.Now()
+	for id, t := range r.s.data.tokens {
+		if t.RevokedAt == nil && match(t) {
+			t.RevokedAt = timePtr(now)
+			r.s.data.tokens[id] = t
+		}
+	}
+}
+
+func (r *memoryTokens) RevokeFamily(ctx context.Context, familyID string) error {
+	defer r.s.lock()()
+	r.revoke(func(t model.RefreshToken) bool { return t.FamilyID == familyID })
+	return nil
+}
+
+func (r *memoryTokens) RevokeAllForUser(ctx context.Context, userID int64) error {
+	defer r.s.lock()()
+	r.revoke(func(t model.RefreshToken) bool { return t.UserID == userID })
+	return nil
+}
+
+func (r *memoryTokens) RevokeOthers(ctx context.Context, userID int64, familyID string) error {
+	defer r.s.lock()()
+	r.revoke(func(t model.RefreshToken) bool { return t.UserID == userID && t.FamilyID != familyID })
+	return nil
+}
+
+func (r *memoryTokens) Active(ctx context.Context, userID int64) ([]model.RefreshToken, error) {
+	defer r.s.lock()()
+	tokens := []model.RefreshToken{}
+	for _, t := range r.s.data.tokens {
+		if t.UserID == userID && t.IsValid() {
+			tokens = append(tokens, t)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].ID > tokens[j].ID })
+	return tokens, nil
+}
+
+func (r *memoryTokens) DeleteExpired(ctx context.Context) (int64, error) {
+	defer r.s.lock()()
+	var deleted int64
+	now := time.Now()
+	for id, t := range r.s.data.tokens {
+		if t.ExpiresAt.Before(now) {
+			delete(r.s.data.tokens, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// memoryResets implements ResetRepository in memory
+type memoryResets struct{ s *MemoryStore }
+
+func (r *memoryResets) Create(ctx context.Context, reset *model.PasswordReset) error {
+	defer r.s.lock()()
+	reset.ID = r.s.data.nextID()
+	reset.Attempts = 0
+	reset.CreatedAt = time.Now()
+	r.s.data.resets[reset.ID] = *reset
+	return nil
+}
+
+func (r *memoryResets) Active(ctx context.Context, userID int64) (*model.PasswordReset, error) {
+	defer r.s.lock()()
+	var active *model.PasswordReset
+	now := time.Now()
+	for _, reset := range r.s.data.resets {
+		reset := reset
+		if reset.UserID == userID && reset.UsedAt == nil && reset.ExpiresAt.After(now) &&
+			(active == nil || reset.ID > active.ID) {
+			active = &reset
+		}
+	}
+	if active == nil {
+		return nil, ErrNotFound
+	}
+	return active, nil
+}
+
+func (r *memoryResets) ClaimAttempt(ctx context.Context, id int64, limit int) (bool, error) {
+	defer r.s.lock()()
+	reset, ok := r.s.data.resets[id]
+	if !ok || reset.Attempts >= limit {
+		return false, nil
+	}
+	reset.Attempts++
+	r.s.data.resets[id] = reset
+	return true, nil
+}
+
+func (r *memoryResets) CountSince(ctx context.Context, userID int64, since time.Time) (int, error) {
+	defer r.s.lock()()
+	n := 0
+	for _, reset := range r.s.data.resets {
+		if reset.UserID == userID && reset.CreatedAt.After(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *memoryResets) Use(ctx context.Context, id int64) (bool, error) {
+	defer r.s.lock()()
+	reset, ok := r.s.data.resets[id]
+	if !ok || reset.UsedAt != nil {
+		return false, nil
+	}
+	reset.UsedAt = timePtr(time.Now())
+	r.s.data.resets[id] = reset
+	return true, nil
+}
+
+func (r *memoryResets) InvalidateForUser(ctx context.Context, userID int64) error {
+	defer r.s.lock()()
+	now := time.Now()
+	for id, reset := range r.s.data.resets {
+		if reset.UserID == userID && reset.UsedAt == nil {
+			reset.UsedAt = timePtr(now)
+			r.s.data.resets[id] = reset
+		}
+	}
+	return nil
+}
+
+// memoryLoginEvents implements LoginEventRepository in memory
+type memoryLoginEvents struct{ s *MemoryStore }
+
+func (r *memoryLoginEvents) Create(ctx context.Context, event *model.LoginEvent) error {
+	defer r.s.lock()()
+	event.ID = r.s.data.nextID()
+	event.CreatedAt = time.Now()
+	r.s.data.loginEvents = append(r.s.data.loginEvents, *event)
+	return nil
+}
+
+func (r *memoryLoginEvents) List(ctx context.Context, f LoginEventFilter) ([]model.LoginEvent, error) {
+	defer r.s.lock()()
+	events := []model.LoginEvent{}
+	for i := len(r.s.data.loginEvents) - 1; i >= 0 && (f.Limit <= 0 || len(events) < f.Limit); i-- {
+		e := r.s.data.loginEvents[i]
+		if f.UserID != 0 && (e.UserID == nil || *e.UserID != f.UserID) {
+			continue
+		}
+		if f.Kind != "" && e.Kind != f.Kind {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// memoryAudit implements AuditRepository in memory
+type memoryAudit struct{ s *MemoryStore }
+
+func (r *memoryAudit) Create(ctx context.Context, event *model.AuditEvent) error {
+	defer r.s.lock()()
+	event.ID = r.s.data.nextID()
+	event.CreatedAt = time.Now()
+	r.s.data.audit = append(r.s.data.audit, *event)
+	return nil
+}
+
+func (r *memoryAudit) List(ctx context.Context, f AuditFilter) ([]model.AuditEvent, error) {
+	defer r.s.lock()()
+	events := []model.AuditEvent{}
+	for i := len(r.s.data.audit) - 1; i >= 0 && (f.Limit <= 0 || len(events) < f.Limit); i-- {
+		e := r.s.data.audit[i]
+		switch {
+		case f.ClubID != 0 && (e.ClubID == nil || *e.ClubID != f.ClubID),
+			f.ActorID != 0 && (e.ActorID == nil || *e.ActorID != f.ActorID),
+			f.Action != "" && e.Action != f.Action,
+			f.TargetType != "" && e.TargetType != f.TargetType,
+			f.TargetID != "" && e.TargetID != f.TargetID,
+			!f.Since.IsZero() && e.CreatedAt.Before(f.Since),
+			!f.Until.IsZero() && !e.CreatedAt.Before(f.Until):
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// memoryQueue implements QueueRepository in memory
+type memoryQueue struct{ s *MemoryStore }
+
+// entries returns pointers to a queue's entries with the given status, in position order
+func (r *memoryQueue) entries(clubID, sessionID int64, status model.QueueStatus) []*memoryQueueEntry {
+	var found []*memoryQueueEntry
+	for i := range r.s.data.queue {
+		e := &r.s.data.queue[i]
+		if e.clubID == clubID && sameSession(e.SessionID, sessionID) && e.Status == string(status) {
+			found = append(found, e)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Position < found[j].Position })
+	return found
+}
+
+func sameSession(stored *int64, sessionID int64) bool {
+	if stored == nil {
+		return sessionID == 0
+	}
+	return *stored == sessionID
+}
+
+func (r *memoryQueue) Count(ctx context.Context, clubID, sessionID int64, status model.QueueStatus) (int, error) {
+	defer r.s.lock()()
+	return len(r.entries(clubID, sessionID, status)), nil
+}
+
+func (r *memoryQueue) Position(ctx context.Context, clubID, sessionID, userID int64) (int, error) {
+	defer r.s.lock()()
+	for _, e := range r.entries(clubID, sessionID, model.QueueStatusWaiting) {
+		if e.UserID == userID {
+			return e.Position, nil
+		}
+	}
+	return 0, ErrNotFound
+}
+
+func (r *memoryQueue) Playing(ctx context.Context, clubID, sessionID int64, limit int) ([]model.QueueEntry, error) {
+	defer r.s.lock()()
+	playing := r.entries(clubID, sessionID, model.QueueStatusPlaying)
+	sort.SliceStable(playing, func(i, j int) bool {
+		a, b := playing[i].CalledAt, playing[j].CalledAt
+		return a != nil && (b == nil || a.After(*b))
+	})
+
+	var entries []model.QueueEntry
+	for _, e := range playing {
+		if limit > 0 && len(entries) == limit {
+			break
+		}
+		entries = append(entries, e.QueueEntry)
+	}
+	return entries, nil
+}
+
+func (r *memoryQueue) Add(ctx context.Context, clubID, sessionID, userID int64) (*model.QueueEntry, error) {
+	defer r.s.lock()()
+	position := 1
+	for _, e := range r.entries(clubID, sessionID, model.QueueStatusWaiting) {
+		if e.Position >= position {
+			position = e.Position + 1
+		}
+	}
+
+	entry := model.QueueEntry{
+		ID:       r.s.data.nextID(),
+		UserID:   userID,
+		Position: position,
+		Status:   string(model.QueueStatusWaiting),
+		JoinedAt: time.Now(),
+	}
+	if sessionID != 0 {
+		entry.SessionID = int64Ptr(sessionID)
+	}
+	r.s.data.queue = append(r.s.data.queue, memoryQueueEntry{clubID: clubID, QueueEntry: entry})
+
+	return &entry, nil
+}
+
+func (r *memoryQueue) Remove(ctx context.Context, clubID, sessionID, userID int64) (bool, error) {
+	defer r.s.lock()()
+	return r.remove(clubID, sessionID, model.QueueStatusWaiting, []int64{userID}) > 0, nil
+}
+
+// remove deletes a queue's entries with the given status for the users, returning how many it removed
+func (r *memoryQueue) remove(clubID, sessionID int64, status model.QueueStatus, userIDs []int64) int {
+	kept := r.s.data.queue[:0]
+	removed := 0
+	for _, e := range r.s.data.queue {
+		if e.clubID == clubID && sameSession(e.SessionID, sessionID) && e.Status == string(status) && hasID(userIDs, e.UserID) {
+			removed++
+			continue
+		}
+		kept = append(kept, e)
+	}
+	r.s.data.queue = kept
+	return removed
+}
+
+func (r *memoryQueue) CallNext(ctx context.Context, clubID, sessionID int64, count int) ([]model.QueueEntry, error) {
+	defer r.s.lock()()
+	now := time.Now()
+	var called []model.QueueEntry
+	for _, e := range r.entries(clubID, sessionID, model.QueueStatusWaiting) {
+		if len(called) == count {
+			break
+		}
+		e.Status = string(model.QueueStatusCalled)
+		e.CalledAt = timePtr(now)
+		called = append(called, e.QueueEntry)
+	}
+	return called, nil
+}
+
+func (r *memoryQueue) Called(ctx context.Context, clubID, sessionID int64) ([]model.QueueEntry, error) {
+	defer r.s.lock()()
+	var called []model.QueueEntry
+	for _, e := range r.entries(clubID, sessionID, model.QueueStatusCalled) {
+		called = append(called, e.QueueEntry)
+	}
+	sort.SliceStable(called, func(i, j int) bool { return called[i].CalledAt.Before(*called[j].CalledAt) })
+	return called, nil
+}
+
+func (r *memoryQueue) Requeue(ctx context.Context, clubID, sessionID int64, entries []model.QueueEntry) error {
+	defer r.s.lock()()
+	for _, e := range r.entries(clubID, sessionID, model.QueueStatusWaiting) {
+		e.Position += len(entries)
+	}
+	for i, entry := range entries {
+		for j := range r.s.data.queue {
+			e := &r.s.data.queue[j]
+			if e.ID == entry.ID && e.Status == string(model.QueueStatusCalled) {
+				e.Status = string(model.QueueStatusWaiting)
+				e.Position = i + 1
+				e.CalledAt = nil
+			}
+		}
+	}
+	return nil
+}
+
+func (r *memoryQueue) Clear(ctx context.Context, clubID, sessionID int64) error {
+	defer r.s.lock()()
+	queue := r.s.data.queue[:0]
+	for _, e := range r.s.data.queue {
+		cleared := e.Status == string(model.QueueStatusWaiting) || e.Status == string(model.QueueStatusCalled)
+		if !(cleared && e.clubID == clubID && sameSession(e.SessionID, sessionID)) {
+			queue = append(queue, e)
+		}
+	}
+	r.s.data.queue = queue
+	return nil
+}
+
+func (r *memoryQueue) Reorder(ctx context.Context, clubID, sessionID int64) error {
+	defer r.s.lock()()
+	for i, e := range r.entries(clubID, sessionID, model.QueueStatusWaiting) {
+		e.Position = i + 1
+	}
+	return nil
+}
+
+func (r *memoryQueue) Withdraw(ctx context.Context, userID int64) error {
+	defer r.s.lock()()
+	type queueKey struct {
+		clubID, sessionID int64
+	}
+	touched := make(map[queueKey]bool)
+	kept := r.s.data.queue[:0]
+	for _, e := range r.s.data.queue {
+		if e.UserID == userID && e.Status == string(model.QueueStatusWaiting) {
+			var sessionID int64
+			if e.SessionID != nil {
+				sessionID = *e.SessionID
+			}
+			touched[queueKey{e.clubID, sessionID}] = true
+			continue
+		}
+		kept = append(kept, e)
+	}
+	r.s.data.queue = kept
+
+	for key := range touched {
+		for i, e := range r.entries(key.clubID, key.sessionID, model.QueueStatusWaiting) {
+			e.Position = i + 1
+		}
+	}
+	return nil
+}
+
+func (r *memoryQueue) MarkPlaying(ctx context.Context, clubID, sessionID int64, userIDs []int64) error {
+	defer r.s.lock()()
+	for _, status := range []model.QueueStatus{model.QueueStatusWaiting, model.QueueStatusCalled} {
+		for _, e := range r.entries(clubID, sessionID, status) {
+			if hasID(userIDs, e.UserID) {
+				e.Status = string(model.QueueStatusPlaying)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *memoryQueue) RemovePlaying(ctx context.Context, clubID, sessionID int64, userIDs []int64) error {
+	defer r.s.lock()()
+	r.remove(clubID, sessionID, model.QueueStatusPlaying, userIDs)
+	return nil
+}
+
+// memoryMatches implements MatchRepository in memory
+type memoryMatches struct{ s *MemoryStore }
+
+// find returns copies of the matches keep accepts, sorted by less and cut to limit
+func (r *memoryMatches) find(keep func(m model.Match) bool, less func(a, b model.Match) bool, limit int) []model.Match {
+	matches := []model.Match{}
+	for _, m := range r.s.data.matches {
+		if keep(m) {
+			matches = append(matches, copyMatch(m))
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return less(matches[i], matches[j]) })
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+func newestStarted(a, b model.Match) bool {
+	if a.StartedAt.Equal(b.StartedAt) {
+		return a.ID > b.ID
+	}
+	return a.StartedAt.After(b.StartedAt)
+}
+
+// oldestEnded orders finished matches by when they ended, then by ID
+func oldestEnded(a, b model.Match) bool {
+	if a.EndedAt.Equal(*b.EndedAt) {
+		return a.ID < b.ID
+	}
+	return a.EndedAt.Before(*b.EndedAt)
+}
+
+func inSession(m model.Match, sessionID int64) bool {
+	return sessionID == 0 || (m.SessionID != nil && *m.SessionID == sessionID)
+}
+
+func (r *memoryMatches) Create(ctx context.Context, match *model.Match) error {
+	defer r.s.lock()()
+	if match.CourtID != nil {
+		for _, m := range r.s.data.matches {
+			if m.CourtID != nil && *m.CourtID == *match.CourtID && m.Result == string(model.MatchResultPending) {
+				return ErrDuplicate
+			}
+		}
+	}
+	now := time.Now()
+	match.ID = r.s.data.nextID()
+	match.Result = string(model.MatchResultPending)
+	match.StartedAt = now
+	match.CreatedAt = now
+	match.EndedAt = nil
+	r.s.data.matches[match.ID] = copyMatch(*match)
+	return nil
+}
+
+func (r *memoryMatches) GetForUpdate(ctx context.Context, clubID, id int64) (*model.Match, error) {
+	defer r.s.lock()()
+	m, ok := r.s.data.matches[id]
+	if !ok || m.ClubID != clubID {
+		return nil, ErrNotFound
+	}
+	m = copyMatch(m)
+	return &m, nil
+}
+
+func (r *memoryMatches) Complete(ctx context.Context, id int64, result string, endedAt time.Time, scores []model.GameScore) error {
+	defer r.s.lock()()
+	m, ok := r.s.data.matches[id]
+	if !ok {
+		return ErrNotFound
+	}
+	m.Result = result
+	m.EndedAt = timePtr(endedAt)
+	m.Scores = make([]model.GameScore, len(scores))
+	for i, score := range scores {
+		m.Scores[i] = model.GameScore{Game: i + 1, Team1Score: score.Team1Score, Team2Score: score.Team2Score}
+	}
+	r.s.data.matches[id] = m
+	return nil
+}
+
+func (r *memoryMatches) Amend(ctx context.Context, id int64, result string, scores []model.GameScore) error {
+	defer r.s.lock()()
+	m, ok := r.s.data.matches[id]
+	if !ok {
+		return ErrNotFound
+	}
+	m.Result = result
+	m.Scores = make([]model.GameScore, len(scores))
+	for i, score := range scores {
+		m.Scores[i] = model.GameScore{Game: i + 1, Team1Score: score.Team1Score, Team2Score: score.Team2Score}
+	}
+	r.s.data.matches[id] = m
+	return nil
+}
+
+func (r *memoryMatches) AddCorrection(ctx context.Context, correction *model.MatchCorrection) error {
+	defer r.s.lock()()
+	correction.ID = r.s.data.nextID()
+	correction.CreatedAt = time.Now()
+	c := *correction
+	c.ScoresBefore = append([]model.GameScore(nil), c.ScoresBefore...)
+	c.ScoresAfter = append([]model.GameScore(nil), c.ScoresAfter...)
+	r.s.data.corrections = append(r.s.data.corrections, c)
+	return nil
+}
+
+func (r *memoryMatches) Corrections(ctx context.Context, clubID, matchID int64) ([]model.MatchCorrection, error) {
+	defer r.s.lock()()
+	corrections := []model.MatchCorrection{}
+	if m, ok := r.s.data.matches[matchID]; !ok || m.ClubID != clubID {
+		return corrections, nil
+	}
+	for _, c := range r.s.data.corrections {
+		if c.MatchID == matchID {
+			corrections = append(corrections, c)
+		}
+	}
+	return corrections, nil
+}
+
+func (r *memoryMatches) DecidedFrom(ctx context.Context, endedAt time.Time, id int64) ([]model.Match, error) {
+	defer r.s.lock()()
+	return r.find(func(m model.Match) bool {
+		if m.Result != "team1" && m.Result != "team2" {
+			return false
+		}
+		return m.EndedAt.After(endedAt) || (m.EndedAt.Equal(endedAt) && m.ID >= id)
+	}, oldestEnded, 0), nil
+}
+
+func (r *memoryMatches) Decided(ctx context.Context, f MatchFilter) ([]model.Match, error) {
+	defer r.s.lock()()
+	return r.find(func(m model.Match) bool {
+		return (m.Result == "team1" || m.Result == "team2") &&
+			(f.ClubID == 0 || m.ClubID == f.ClubID) && inSession(m, f.SessionID) &&
+			(f.UserID == 0 || hasID(m.Team1, f.UserID) || hasID(m.Team2, f.UserID)) &&
+			(f.Since.IsZero() || !m.EndedAt.Before(f.Since)) &&
+			(f.Until.IsZero() || m.EndedAt.Before(f.Until))
+	}, oldestEnded, f.Limit), nil
+}
+
+func (r *memoryMatches) DecidedByID(ctx context.Context, ids []int64) ([]model.Match, error) {
+	defer r.s.lock()()
+	return r.find(func(m model.Match) bool {
+		return (m.Result == "team1" || m.Result == "team2") && hasID(ids, m.ID)
+	}, oldestEnded, 0), nil
+}
+
+func (r *memoryMatches) History(ctx context.Context, f MatchFilter) ([]model.Match, error) {
+	defer r.s.lock()()
+	return r.find(func(m model.Match) bool {
+		return m.ClubID == f.ClubID && inSession(m, f.SessionID) && (hasID(m.Team1, f.UserID) || hasID(m.Team2, f.UserID))
+	}, newestStarted, f.Limit), nil
+}
+
+func (r *memoryMatches) Active(ctx context.Context, f MatchFilter) ([]model.Match, error) {
+	defer r.s.lock()()
+	return r.find(func(m model.Match) bool {
+		return m.ClubID == f.ClubID && inSession(m, f.SessionID) && m.Result == string(model.MatchResultPending)
+	}, newestStarted, f.Limit), nil
+}
+
+func (r *memoryMatches) Completed(ctx context.Context, f MatchFilter) ([]model.Match, error) {
+	defer r.s.lock()()
+	return r.find(func(m model.Match) bool {
+		return m.ClubID == f.ClubID && inSession(m, f.SessionID) && m.Result != string(model.MatchResultPending)
+	}, func(a, b model.Match) bool {
+		return a.EndedAt.After(*b.EndedAt)
+	}, f.Limit), nil
+}
+
+func (r *memoryMatches) Played(ctx context.Context, userIDs []int64, limit int) ([]model.Match, error) {
+	defer r.s.lock()()
+	return r.find(func(m model.Match) bool {
+		for _, id := range userIDs {
+			if hasID(m.Team1, id) || hasID(m.Team2, id) {
+				return true
+			}
+		}
+		return false
+	}, newestStarted, limit), nil
+}
+
+func (r *memoryMatches) PendingFor(ctx context.Context, userID int64) ([]model.Match, error) {
+	defer r.s.lock()()
+	return r.find(func(m model.Match) bool {
+		return m.Result == string(model.MatchResultPending) && (hasID(m.Team1, userID) || hasID(m.Team2, userID))
+	}, newestStarted, 0), nil
+}
+
+func (r *memoryMatches) Outcomes(ctx context.Context, clubID, userID int64) ([]bool, error) {
+	defer r.s.lock()()
+	decided := r.find(func(m model.Match) bool {
+		return (clubID == 0 || m.ClubID == clubID) && (m.Result == "team1" || m.Result == "team2") &&
+			(hasID(m.Team1, userID) || hasID(m.Team2, userID))
+	}, oldestEnded, 0)
+
+	outcomes := make([]bool, len(decided))
+	for i, m := range decided {
+		outcomes[i] = (m.Result == "team1") == hasID(m.Team1, userID)
+	}
+	return outcomes, nil
+}
+
+func (r *memoryMatches) Durations(ctx context.Context, clubID int64, limit int, max time.Duration) ([]time.Duration, error) {
+	defer r.s.lock()()
+	finished := r.find(func(m model.Match) bool {
+		if m.ClubID != clubID || m.Result == string(model.MatchResultPending) || m.EndedAt == nil {
+			return false
+		}
+		d := m.EndedAt.Sub(m.StartedAt)
+		return d > 0 && d < max
+	}, func(a, b model.Match) bool {
+		return a.EndedAt.After(*b.EndedAt)
+	}, limit)
+
+	durations := make([]time.Duration, len(finished))
+	for i, m := range finished {
+		durations[i] = m.EndedAt.Sub(m.StartedAt)
+	}
+	return durations, nil
+}
+
+// memorySeasons implements SeasonRepository in memory
+type memorySeasons struct{ s *MemoryStore }
+
+func (r *memorySeasons) Create(ctx context.Context, season *model.Season) error {
+	defer r.s.lock()()
+	for _, existing := range r.s.data.seasons {
+		if existing.ClubID == season.ClubID && existing.Status == model.SeasonActive {
+			return fmt.Errorf("club %d already has an active season", season.ClubID)
+		}
+	}
+	season.ID = r.s.data.nextID()
+	season.Status = model.SeasonActive
+	season.StartedAt = time.Now()
+	r.s.data.seasons[season.ID] = *season
+	return nil
+}
+
+func (r *memorySeasons) Get(ctx context.Context, clubID, id int64) (*model.Season, error) {
+	defer r.s.lock()()
+	season, ok := r.s.data.seasons[id]
+	if !ok || (clubID != 0 && season.ClubID != clubID) {
+		return nil, ErrNotFound
+	}
+	return &season, nil
+}
+
+func (r *memorySeasons) Active(ctx context.Context, clubID int64) (*model.Season, error) {
+	defer r.s.lock()()
+	for _, season := range r.s.data.seasons {
+		if season.ClubID == clubID && season.Status == model.SeasonActive {
+			return &season, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memorySeasons) List(ctx context.Context, clubID int64) ([]model.Season, error) {
+	defer r.s.lock()()
+	seasons := []model.Season{}
+	for _, season := range r.s.data.seasons {
+		if season.ClubID == clubID {
+			seasons = append(seasons, season)
+		}
+	}
+	sort.Slice(seasons, func(i, j int) bool {
+		if seasons[i].StartedAt.Equal(seasons[j].StartedAt) {
+			return seasons[i].ID > seasons[j].ID
+		}
+		return seasons[i].StartedAt.After(seasons[j].StartedAt)
+	})
+	return seasons, nil
+}
+
+func (r *memorySeasons) End(ctx context.Context, id int64, endedBy *int64, endedAt time.Time) error {
+	defer r.s.lock()()
+	season, ok := r.s.data.seasons[id]
+	if !ok || season.Status != model.SeasonActive {
+		return ErrNotFound
+	}
+	season.Status = model.SeasonEnded
+	season.EndedAt = timePtr(endedAt)
+	season.EndedBy = endedBy
+	r.s.data.seasons[id] = season
+	return nil
+}
+
+func (r *memorySeasons) SavePlayers(ctx context.Context, players []model.SeasonStats) error {
+	defer r.s.lock()()
+	for _, p := range players {
+		if r.s.data.seasonStats[p.SeasonID] == nil {
+			r.s.data.seasonStats[p.SeasonID] = make(map[int64]model.SeasonStats)
+		}
+		r.s.data.seasonStats[p.SeasonID][p.UserID] = p
+	}
+	return nil
+}
+
+func (r *memorySeasons) Players(ctx context.Context, seasonID int64) ([]model.SeasonStats, error) {
+	defer r.s.lock()()
+	players := []model.SeasonStats{}
+	for _, p := range r.s.data.seasonStats[seasonID] {
+		players = append(players, p)
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i].UserID < players[j].UserID })
+	return players, nil
+}
+
+// memoryTournaments implements TournamentRepository in memory
+type memoryTournaments struct{ s *MemoryStore }
+
+func (r *memoryTournaments) Create(ctx context.Context, t *model.Tournament) error {
+	defer r.s.lock()()
+	t.ID = r.s.data.nextID()
+	t.Status = model.TournamentRegistration
+	t.CreatedAt = time.Now()
+	stored := *t
+	stored.Courts = append([]string(nil), t.Courts...)
+	r.s.data.tournaments[t.ID] = stored
+	return nil
+}
+
+func (r *memoryTournaments) Get(ctx context.Context, clubID, id int64) (*model.Tournament, error) {
+	defer r.s.lock()()
+	t, ok := r.s.data.tournaments[id]
+	if !ok || t.ClubID != clubID {
+		return nil, ErrNotFound
+	}
+	t.Courts = append([]string(nil), t.Courts...)
+	return &t, nil
+}
+
+func (r *memoryTournaments) GetForUpdate(ctx context.Context, clubID, id int64) (*model.Tournament, error) {
+	return r.Get(ctx, clubID, id)
+}
+
+func (r *memoryTournaments) List(ctx context.Context, clubID int64) ([]model.Tournament, error) {
+	defer r.s.lock()()
+	tournaments := []model.Tournament{}
+	for _, t := range r.s.data.tournaments {
+		if t.ClubID == clubID {
+			t.Courts = append([]string(nil), t.Courts...)
+			tournaments = append(tournaments, t)
+		}
+	}
+	sort.Slice(tournaments, func(i, j int) bool {
+		if tournaments[i].CreatedAt.Equal(tournaments[j].CreatedAt) {
+			return tournaments[i].ID > tournaments[j].ID
+		}
+		return tournaments[i].CreatedAt.After(tournaments[j].CreatedAt)
+	})
+	return tournaments, nil
+}
+
+func (r *memoryTournaments) Update(ctx context.Context, t *model.Tournament) error {
+	defer r.s.lock()()
+	stored, ok := r.s.data.tournaments[t.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	stored.Status = t.Status
+	stored.WinnerEntryID = t.WinnerEntryID
+	stored.StartedAt = t.StartedAt
+	stored.CompletedAt = t.CompletedAt
+	r.s.data.tournaments[t.ID] = stored
+	return nil
+}
+
+func (r *memoryTournaments) AddEntry(ctx context.Context, e *model.TournamentEntry) error {
+	defer r.s.lock()()
+	e.ID = r.s.data.nextID()
+	e.CreatedAt = time.Now()
+	stored := *e
+	stored.Players = append([]int64(nil), e.Players...)
+	stored.Names = nil
+	r.s.data.entries[e.ID] = stored
+	return nil
+}
+
+func (r *memoryTournaments) RemoveEntry(ctx context.Context, tournamentID, entryID int64) error {
+	defer r.s.lock()()
+	e, ok := r.s.data.entries[entryID]
+	if !ok || e.TournamentID != tournamentID {
+		return ErrNotFound
+	}
+	delete(r.s.data.entries, entryID)
+	return nil
+}
+
+func (r *memoryTournaments) Entries(ctx context.Context, tournamentID int64) ([]model.TournamentEntry, error) {
+	defer r.s.lock()()
+	entries := []model.TournamentEntry{}
+	for _, e := range r.s.data.entries {
+		if e.TournamentID == tournamentID {
+			e.Players = append([]int64(nil), e.Players...)
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries, nil
+}
+
+func (r *memoryTournaments) SeedEntries(ctx context.Context, entries []model.TournamentEntry) error {
+	defer r.s.lock()()
+	for _, e := range entries {
+		stored, ok := r.s.data.entries[e.ID]
+		if !ok {
+			continue
+		}
+		stored.Seed = e.Seed
+		stored.Strength = e.Strength
+		stored.Group = e.Group
+		r.s.data.entries[e.ID] = stored
+	}
+	return nil
+}
+
+func (r *memoryTournaments) AddBracketMatch(ctx context.Context, m *model.BracketMatch) error {
+	defer r.s.lock()()
+	m.ID = r.s.data.nextID()
+	r.s.data.brackets[m.ID] = *m
+	return nil
+}
+
+func (r *memoryTournaments) UpdateBracketMatch(ctx context.Context, m model.BracketMatch) error {
+	defer r.s.lock()()
+	stored, ok := r.s.data.brackets[m.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	stored.Entry1ID, stored.Entry2ID = m.Entry1ID, m.Entry2ID
+	stored.WinnerEntryID, stored.LoserEntryID = m.WinnerEntryID, m.LoserEntryID
+	stored.MatchID = m.MatchID
+	stored.Status = m.Status
+	r.s.data.brackets[m.ID] = stored
+	return nil
+}
+
+// bracketOrder sorts winners, losers, then final rounds
+var bracketOrder = map[model.BracketSide]int{model.BracketWinners: 0, model.BracketLosers: 1, model.BracketFinal: 2}
+
+func (r *memoryTournaments) BracketMatches(ctx context.Context, tournamentID int64) ([]model.BracketMatch, error) {
+	defer r.s.lock()()
+	matches := []model.BracketMatch{}
+	for _, m := range r.s.data.brackets {
+		if m.TournamentID == tournamentID {
+			matches = append(matches, m)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Bracket != b.Bracket {
+			return bracketOrder[a.Bracket] < bracketOrder[b.Bracket]
+		}
+		if a.Round != b.Round {
+			return a.Round < b.Round
+		}
+		return a.Position < b.Position
+	})
+	return matches, nil
+}
+
+func (r *memoryTournaments) BracketMatchFor(ctx context.Context, matchID int64) (*model.BracketMatch, error) {
+	defer r.s.lock()()
+	for _, m := range r.s.data.brackets {
+		if m.MatchID != nil && *m.MatchID == matchID {
+			return &m, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryTournaments) AddFixture(ctx context.Context, f *model.Fixture) error {
+	defer r.s.lock()()
+	f.ID = r.s.data.nextID()
+	r.s.data.fixtures[f.ID] = *f
+	return nil
+}
+
+func (r *memoryTournaments) UpdateFixture(ctx context.Context, f model.Fixture) error {
+	defer r.s.lock()()
+	stored, ok := r.s.data.fixtures[f.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	stored.MatchID = f.MatchID
+	stored.Status = f.Status
+	r.s.data.fixtures[f.ID] = stored
+	return nil
+}
+
+func (r *memoryTournaments) Fixtures(ctx context.Context, tournamentID int64) ([]model.Fixture, error) {
+	defer r.s.lock()()
+	fixtures := []model.Fixture{}
+	for _, f := range r.s.data.fixtures {
+		if f.TournamentID == tournamentID {
+			fixtures = append(fixtures, f)
+		}
+	}
+	sort.Slice(fixtures, func(i, j int) bool {
+		if fixtures[i].Slot != fixtures[j].Slot {
+			return fixtures[i].Slot < fixtures[j].Slot
+		}
+		return fixtures[i].Court < fixtures[j].Court
+	})
+	return fixtures, nil
+}
+
+func (r *memoryTournaments) FixtureFor(ctx context.Context, matchID int64) (*model.Fixture, error) {
+	defer r.s.lock()()
+	for _, f := range r.s.data.fixtures {
+		if f.MatchID != nil && *f.MatchID == matchID {
+			return &f, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// memoryClubs implements ClubRepository in memory
+type memoryClubs struct{ s *MemoryStore }
+
+func (r *memoryClubs) Create(ctx context.Context, club *model.Club) error {
+	defer r.s.lock()()
+	for _, c := range r.s.data.clubs {
+		if c.Slug == club.Slug {
+			return ErrDuplicate
+		}
+	}
+	club.ID = r.s.data.nextID()
+	club.CreatedAt = time.Now()
+	r.s.data.clubs[club.ID] = *club
+	return nil
+}
+
+func (r *memoryClubs) Get(ctx context.Context, id int64) (*model.Club, error) {
+	defer r.s.lock()()
+	c, ok := r.s.data.clubs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &c, nil
+}
+
+func (r *memoryClubs) GetBySlug(ctx context.Context, slug string) (*model.Club, error) {
+	defer r.s.lock()()
+	for _, c := range r.s.data.clubs {
+		if c.Slug == slug {
+			return &c, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryClubs) List(ctx context.Context) ([]model.Club, error) {
+	defer r.s.lock()()
+	clubs := []model.Club{}
+	for _, c := range r.s.data.clubs {
+		clubs = append(clubs, c)
+	}
+	sort.Slice(clubs, func(i, j int) bool { return clubs[i].Name < clubs[j].Name })
+	return clubs, nil
+}
+
+func (r *memoryClubs) ListForUser(ctx context.Context, userID int64) ([]model.ClubMembership, error) {
+	defer r.s.lock()()
+	clubs := []model.ClubMembership{}
+	for clubID, members := range r.s.data.members {
+		if m, ok := members[userID]; ok {
+			clubs = append(clubs, model.ClubMembership{Club: r.s.data.clubs[clubID], Role: m.Role})
+		}
+	}
+	sort.Slice(clubs, func(i, j int) bool { return clubs[i].Name < clubs[j].Name })
+	return clubs, nil
+}
+
+func (r *memoryClubs) SetAutoDispatch(ctx context.Context, id int64, enabled bool) error {
+	defer r.s.lock()()
+	c, ok := r.s.data.clubs[id]
+	if !ok {
+		return ErrNotFound
+	}
+	c.AutoDispatch = &enabled
+	r.s.data.clubs[id] = c
+	return nil
+}
+
+// member fills in the user's name on a stored membership
+func (r *memoryClubs) member(m model.ClubMember) model.ClubMember {
+	u := r.s.data.users[m.UserID]
+	m.Name, m.Username = u.Name, u.Username
+	return m
+}
+
+func (r *memoryClubs) Member(ctx context.Context, clubID, userID int64) (*model.ClubMember, error) {
+	defer r.s.lock()()
+	m, ok := r.s.data.members[clubID][userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	m = r.member(m)
+	return &m, nil
+}
+
+func (r *memoryClubs) Members(ctx context.Context, clubID int64) ([]model.ClubMember, error) {
+	defer r.s.lock()()
+	members := []model.ClubMember{}
+	for _, m := range r.s.data.members[clubID] {
+		members = append(members, r.member(m))
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Name < members[j].Name })
+	return members, nil
+}
+
+func (r *memoryClubs) SetMember(ctx context.Context, clubID, userID int64, role model.Role) error {
+	defer r.s.lock()()
+	if _, ok := r.s.data.clubs[clubID]; !ok {
+		return fmt.Errorf("club %d does not exist", clubID)
+	}
+	if _, ok := r.s.data.users[userID]; !ok {
+		return fmt.Errorf("user %d does not exist", userID)
+	}
+	r.s.data.setMember(clubID, userID, role)
+	return nil
+}
+
+func (r *memoryClubs) RemoveMember(ctx context.Context, clubID, userID int64) error {
+	defer r.s.lock()()
+	if _, ok := r.s.data.members[clubID][userID]; !ok {
+		return ErrNotFound
+	}
+	delete(r.s.data.members[clubID], userID)
+	return nil
+}
+
+// findInvite returns the index of a user's invite to a club, or -1
+func (r *memoryClubs) findInvite(clubID, userID int64) int {
+	for i, inv := range r.s.data.invites {
+		if inv.ClubID == clubID && inv.UserID == userID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *memoryClubs) Invite(ctx context.Context, invite *model.ClubInvite) error {
+	defer r.s.lock()()
+	if _, ok := r.s.data.clubs[invite.ClubID]; !ok {
+		return fmt.Errorf("club %d does not exist", invite.ClubID)
+	}
+	if _, ok := r.s.data.users[invite.UserID]; !ok {
+		return fmt.Errorf("user %d does not exist", invite.UserID)
+	}
+	invite.CreatedAt = time.Now()
+	stored := *invite
+	stored.ClubName = ""
+	if i := r.findInvite(invite.ClubID, invite.UserID); i >= 0 {
+		r.s.data.invites[i] = stored
+	} else {
+		r.s.data.invites = append(r.s.data.invites, stored)
+	}
+	return nil
+}
+
+func (r *memoryClubs) GetInvite(ctx context.Context, clubID, userID int64) (*model.ClubInvite, error) {
+	defer r.s.lock()()
+	i := r.findInvite(clubID, userID)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	inv := r.s.data.invites[i]
+	inv.ClubName = r.s.data.clubs[clubID].Name
+	return &inv, nil
+}
+
+func (r *memoryClubs) Invites(ctx context.Context, userID int64) ([]model.ClubInvite, error) {
+	defer r.s.lock()()
+	invites := []model.ClubInvite{}
+	for _, inv := range r.s.data.invites {
+		if inv.UserID == userID {
+			inv.ClubName = r.s.data.clubs[inv.ClubID].Name
+			invites = append(invites, inv)
+		}
+	}
+	sort.SliceStable(invites, func(i, j int) bool { return invites[i].CreatedAt.After(invites[j].CreatedAt) })
+	return invites, nil
+}
+
+func (r *memoryClubs) DeleteInvite(ctx context.Context, clubID, userID int64) error {
+	defer r.s.lock()()
+	i := r.findInvite(clubID, userID)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.s.data.invites = append(r.s.data.invites[:i], r.s.data.invites[i+1:]...)
+	return nil
+}
+
+// memoryCourts implements CourtRepository in memory
+type memoryCourts struct{ s *MemoryStore }
+
+// nameTaken reports whether another of the club's courts has the name
+func (r *memoryCourts) nameTaken(clubID, id int64, name string) bool {
+	for _, c := range r.s.data.courts {
+		if c.clubID == clubID && c.ID != id && c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sorted returns the club's courts that keep accepts by venue and name
+func (r *memoryCourts) sorted(clubID int64, keep func(c model.Court) bool) []model.Court {
+	courts := []model.Court{}
+	for _, c := range r.s.data.courts {
+		if c.clubID == clubID && keep(c.Court) {
+			courts = append(courts, c.Court)
+		}
+	}
+	sort.Slice(courts, func(i, j int) bool {
+		a, b := courts[i], courts[j]
+		if a.Venue != b.Venue {
+			return a.Venue < b.Venue
+		}
+		if len(a.Name) != len(b.Name) {
+			return len(a.Name) < len(b.Name)
+		}
+		return a.Name < b.Name
+	})
+	return courts
+}
+
+func (r *memoryCourts) Create(ctx context.Context, clubID int64, court *model.Court) error {
+	defer r.s.lock()()
+	if r.nameTaken(clubID, 0, court.Name) {
+		return ErrDuplicate
+	}
+	now := time.Now()
+	court.ID = r.s.data.nextID()
+	court.CreatedAt = now
+	court.UpdatedAt = now
+	r.s.data.courts[court.ID] = memoryCourt{clubID: clubID, Court: *court}
+	return nil
+}
+
+func (r *memoryCourts) Get(ctx context.Context, clubID, id int64) (*model.Court, error) {
+	defer r.s.lock()()
+	c, ok := r.s.data.courts[id]
+	if !ok || c.clubID != clubID {
+		return nil, ErrNotFound
+	}
+	return &c.Court, nil
+}
+
+func (r *memoryCourts) GetByName(ctx context.Context, clubID int64, name string) (*model.Court, error) {
+	defer r.s.lock()()
+	for _, c := range r.s.data.courts {
+		if c.clubID == clubID && c.Name == name {
+			return &c.Court, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryCourts) List(ctx context.Context, clubID int64, activeOnly bool) ([]model.Court, error) {
+	defer r.s.lock()()
+	return r.sorted(clubID, func(c model.Court) bool { return !activeOnly || c.IsActive() }), nil
+}
+
+func (r *memoryCourts) Update(ctx context.Context, clubID int64, court *model.Court) error {
+	defer r.s.lock()()
+	stored, ok := r.s.data.courts[court.ID]
+	if !ok || stored.clubID != clubID {
+		return ErrNotFound
+	}
+	if r.nameTaken(clubID, court.ID, court.Name) {
+		return ErrDuplicate
+	}
+	court.CreatedAt = stored.CreatedAt
+	court.UpdatedAt = time.Now()
+	r.s.data.courts[court.ID] = memoryCourt{clubID: clubID, Court: *court}
+	for id, m := range r.s.data.matches {
+		if m.CourtID != nil && *m.CourtID == court.ID && m.Result == string(model.MatchResultPending) {
+			m.Court = court.Name
+			r.s.data.matches[id] = m
+		}
+	}
+	return nil
+}
+
+func (r *memoryCourts) Delete(ctx context.Context, clubID, id int64) error {
+	defer r.s.lock()()
+	c, ok := r.s.data.courts[id]
+	if !ok || c.clubID != clubID {
+		return ErrNotFound
+	}
+	delete(r.s.data.courts, id)
+	for matchID, m := range r.s.data.matches {
+		if m.CourtID != nil && *m.CourtID == id {
+			m.CourtID = nil
+			r.s.data.matches[matchID] = m
+		}
+	}
+	return nil
+}
+
+func (r *memoryCourts) NextAvailable(ctx context.Context, clubID int64, ids []int64) (*model.Court, error) {
+	defer r.s.lock()()
+	busy := make(map[int64]bool)
+	for _, m := range r.s.data.matches {
+		if m.CourtID != nil && m.Result == string(model.MatchResultPending) {
+			busy[*m.CourtID] = true
+		}
+	}
+	courts := r.sorted(clubID, func(c model.Court) bool {
+		return c.IsActive() && (len(ids) == 0 || hasID(ids, c.ID)) && !busy[c.ID]
+	})
+	if len(courts) == 0 {
+		return nil, ErrNotFound
+	}
+	return &courts[0], nil
+}
+
+// memorySessions implements SessionRepository in memory
+type memorySessions struct{ s *MemoryStore }
+
+func copySession(sess model.Session) *model.Session {
+	sess.CourtIDs = append([]int64{}, sess.CourtIDs...)
+	return &sess
+}
+
+func (r *memorySessions) Create(ctx context.Context, sess *model.Session) error {
+	defer r.s.lock()()
+	sess.ID = r.s.data.nextID()
+	sess.Status = model.SessionScheduled
+	sess.CreatedAt = time.Now()
+	if sess.CourtIDs == nil {
+		sess.CourtIDs = []int64{}
+	}
+	r.s.data.sessions[sess.ID] = *copySession(*sess)
+	return nil
+}
+
+func (r *memorySessions) Get(ctx context.Context, clubID, id int64) (*model.Session, error) {
+	defer r.s.lock()()
+	sess, ok := r.s.data.sessions[id]
+	if !ok || (clubID != 0 && sess.ClubID != clubID) {
+		return nil, ErrNotFound
+	}
+	return copySession(sess), nil
+}
+
+func (r *memorySessions) GetForUpdate(ctx context.Context, clubID, id int64) (*model.Session, error) {
+	return r.Get(ctx, clubID, id)
+}
+
+func (r *memorySessions) Current(ctx context.Context, clubID int64) (*model.Session, error) {
+	defer r.s.lock()()
+	var current *model.Session
+	for _, sess := range r.s.data.sessions {
+		if sess.ClubID != clubID || sess.Status != model.SessionOpen {
+			continue
+		}
+		if current == nil || sess.OpenedAt.After(*current.OpenedAt) {
+			current = copySession(sess)
+		}
+	}
+	if current == nil {
+		return nil, ErrNotFound
+	}
+	return current, nil
+}
+
+func (r *memorySessions) List(ctx context.Context, clubID int64, status model.SessionStatus, limit int) ([]model.Session, error) {
+	defer r.s.lock()()
+	sessions := []model.Session{}
+	for _, sess := range r.s.data.sessions {
+		if sess.ClubID == clubID && (status == "" || sess.Status == status) {
+			sessions = append(sessions, *copySession(sess))
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].StartsAt.After(sessions[j].StartsAt) })
+	if limit > 0 && len(sessions) > limit {
+		sessions = sessions[:limit]
+	}
+	return sessions, nil
+}
+
+func (r *memorySessions) Update(ctx context.Context, sess *model.Session) error {
+	defer r.s.lock()()
+	stored, ok := r.s.data.sessions[sess.ID]
+	if !ok || stored.ClubID != sess.ClubID {
+		return ErrNotFound
+	}
+	stored.Name = sess.Name
+	stored.Venue = sess.Venue
+	stored.CourtIDs = append([]int64{}, sess.CourtIDs...)
+	stored.Fee = sess.Fee
+	stored.Scoring = sess.Scoring
+	stored.StartsAt = sess.StartsAt
+	stored.EndsAt = sess.EndsAt
+	r.s.data.sessions[sess.ID] = stored
+	return nil
+}
+
+// transition moves one of a club's sessions from one status to the next, stamping when
+func (r *memorySessions) transition(clubID, id int64, from, to model.SessionStatus, stamp func(sess *model.Session, now time.Time)) (*model.Session, error) {
+	defer r.s.lock()()
+	sess, ok := r.s.data.sessions[id]
+	if !ok || sess.ClubID != clubID || sess.Status != from {
+		return nil, ErrNotFound
+	}
+	sess.Status = to
+	stamp(&sess, time.Now())
+	r.s.data.sessions[id] = sess
+	return copySession(sess), nil
+}
+
+func (r *memorySessions) Open(ctx context.Context, clubID, id int64) (*model.Session, error) {
+	return r.transition(clubID, id, model.SessionScheduled, model.SessionOpen, func(sess *model.Session, now time.Time) {
+		sess.OpenedAt = &now
+	})
+}
+
+func (r *memorySessions) Close(ctx context.Context, clubID, id int64) (*model.Session, error) {
+	return r.transition(clubID, id, model.SessionOpen, model.SessionClosed, func(sess *model.Session, now time.Time) {
+		sess.ClosedAt = &now
+	})
+}
@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS update_users_updated_at ON users;
+DROP FUNCTION IF EXISTS update_updated_at_column();
+
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS queue_entries;
+DROP TABLE IF EXISTS match_scores;
+DROP TABLE IF EXISTS matches;
+DROP TABLE IF EXISTS user_stats;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,15 @@
+-- Court registry, seeded with the courts that used to be hard-coded
+
+CREATE TABLE IF NOT EXISTS courts (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) UNIQUE NOT NULL,
+    venue VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    surface VARCHAR(20) NOT NULL DEFAULT 'synthetic',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+INSERT INTO courts (name)
+SELECT 'Court ' || n FROM generate_series(1, 4) AS n
+WHERE NOT EXISTS (SELECT 1 FROM courts);
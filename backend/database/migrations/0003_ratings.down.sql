@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS rating_history;
+
+ALTER TABLE user_stats DROP COLUMN IF EXISTS volatility;
+ALTER TABLE user_stats DROP COLUMN IF EXISTS rating_deviation;
+ALTER TABLE user_stats DROP COLUMN IF EXISTS rating;
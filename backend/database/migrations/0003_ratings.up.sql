@@ -0,0 +1,19 @@
+-- Glicko-2 ratings and per-match rating history
+
+ALTER TABLE user_stats ADD COLUMN IF NOT EXISTS rating DOUBLE PRECISION DEFAULT 1500;
+ALTER TABLE user_stats ADD COLUMN IF NOT EXISTS rating_deviation DOUBLE PRECISION DEFAULT 350;
+ALTER TABLE user_stats ADD COLUMN IF NOT EXISTS volatility DOUBLE PRECISION DEFAULT 0.06;
+
+CREATE TABLE IF NOT EXISTS rating_history (
+    id SERIAL PRIMARY KEY,
+    match_id INTEGER REFERENCES matches(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    rating_before DOUBLE PRECISION NOT NULL,
+    rating_after DOUBLE PRECISION NOT NULL,
+    deviation_before DOUBLE PRECISION NOT NULL,
+    deviation_after DOUBLE PRECISION NOT NULL,
+    volatility DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rating_history_user ON rating_history(user_id, created_at);
@@ -0,0 +1,3 @@
+ALTER TABLE matches DROP COLUMN IF EXISTS best_of;
+ALTER TABLE matches DROP COLUMN IF EXISTS point_cap;
+ALTER TABLE matches DROP COLUMN IF EXISTS points_to_win;
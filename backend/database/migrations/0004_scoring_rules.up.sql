@@ -0,0 +1,5 @@
+-- Scoring rules each match is played under (BWF defaults)
+
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS points_to_win INTEGER DEFAULT 21;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS point_cap INTEGER DEFAULT 30;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS best_of INTEGER DEFAULT 3;
@@ -0,0 +1,4 @@
+ALTER TABLE matches DROP COLUMN IF EXISTS session_id;
+ALTER TABLE queue_entries DROP COLUMN IF EXISTS session_id;
+
+DROP TABLE IF EXISTS sessions;
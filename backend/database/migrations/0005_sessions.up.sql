@@ -0,0 +1,22 @@
+-- Play sessions (one row per play night) scoping queues and matches
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    venue VARCHAR(255) NOT NULL DEFAULT '',
+    court_ids INTEGER[] NOT NULL DEFAULT '{}',
+    fee NUMERIC(10,2) NOT NULL DEFAULT 0,
+    points_to_win INTEGER NOT NULL DEFAULT 21,
+    point_cap INTEGER NOT NULL DEFAULT 30,
+    best_of INTEGER NOT NULL DEFAULT 3,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    opened_at TIMESTAMP WITH TIME ZONE,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    created_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+ALTER TABLE queue_entries ADD COLUMN IF NOT EXISTS session_id INTEGER REFERENCES sessions(id);
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS session_id INTEGER REFERENCES sessions(id);
@@ -0,0 +1,17 @@
+-- Data of other clubs cascades away; the default club's rows remain
+DELETE FROM clubs WHERE slug != 'default';
+
+DROP INDEX IF EXISTS idx_matches_session;
+DROP INDEX IF EXISTS idx_queue_entries_session;
+DROP INDEX IF EXISTS idx_sessions_club_status;
+DROP INDEX IF EXISTS idx_courts_club_name;
+
+ALTER TABLE matches DROP COLUMN IF EXISTS club_id;
+ALTER TABLE queue_entries DROP COLUMN IF EXISTS club_id;
+ALTER TABLE sessions DROP COLUMN IF EXISTS club_id;
+ALTER TABLE courts DROP COLUMN IF EXISTS club_id;
+
+ALTER TABLE courts ADD CONSTRAINT courts_name_key UNIQUE (name);
+
+DROP TABLE IF EXISTS club_members;
+DROP TABLE IF EXISTS clubs;
@@ -0,0 +1,45 @@
+-- Clubs own their members, courts, sessions, queues and matches.
+-- Everything that existed before clubs belongs to the default club.
+
+CREATE TABLE IF NOT EXISTS clubs (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(50) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+INSERT INTO clubs (slug, name) VALUES ('default', 'SmashQueue')
+ON CONFLICT (slug) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS club_members (
+    club_id INTEGER REFERENCES clubs(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(50) NOT NULL DEFAULT 'player',
+    joined_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (club_id, user_id)
+);
+
+-- Existing users keep their role in the default club
+INSERT INTO club_members (club_id, user_id, role, joined_at)
+SELECT c.id, u.id, u.role, u.created_at FROM users u, clubs c
+WHERE c.slug = 'default'
+ON CONFLICT (club_id, user_id) DO NOTHING;
+
+ALTER TABLE courts ADD COLUMN IF NOT EXISTS club_id INTEGER REFERENCES clubs(id) ON DELETE CASCADE;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS club_id INTEGER REFERENCES clubs(id) ON DELETE CASCADE;
+ALTER TABLE queue_entries ADD COLUMN IF NOT EXISTS club_id INTEGER REFERENCES clubs(id) ON DELETE CASCADE;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS club_id INTEGER REFERENCES clubs(id) ON DELETE CASCADE;
+
+UPDATE courts SET club_id = (SELECT id FROM clubs WHERE slug = 'default') WHERE club_id IS NULL;
+UPDATE sessions SET club_id = (SELECT id FROM clubs WHERE slug = 'default') WHERE club_id IS NULL;
+UPDATE queue_entries SET club_id = (SELECT id FROM clubs WHERE slug = 'default') WHERE club_id IS NULL;
+UPDATE matches SET club_id = (SELECT id FROM clubs WHERE slug = 'default') WHERE club_id IS NULL;
+
+-- Court names only need to be unique within a club
+ALTER TABLE courts DROP CONSTRAINT IF EXISTS courts_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_courts_club_name ON courts(club_id, name);
+
+CREATE INDEX IF NOT EXISTS idx_club_members_user ON club_members(user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_club_status ON sessions(club_id, status, starts_at);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_session ON queue_entries(club_id, session_id, status);
+CREATE INDEX IF NOT EXISTS idx_matches_session ON matches(club_id, session_id);
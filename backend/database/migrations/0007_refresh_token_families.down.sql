@@ -0,0 +1,12 @@
+-- Hashes cannot be turned back into tokens, so everyone signs in again
+DELETE FROM refresh_tokens;
+
+DROP INDEX IF EXISTS idx_refresh_tokens_family;
+DROP INDEX IF EXISTS idx_refresh_tokens_hash;
+
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS rotated_at;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS family_id;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS token_hash;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS token VARCHAR(255) UNIQUE NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_token ON refresh_tokens(token);
@@ -0,0 +1,28 @@
+-- Refresh tokens are stored as SHA-256 hashes and grouped into rotation families,
+-- one family per sign-in
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS token_hash CHAR(64);
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS family_id VARCHAR(64);
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMP WITH TIME ZONE;
+
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'refresh_tokens' AND column_name = 'token'
+    ) THEN
+        UPDATE refresh_tokens SET token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex')
+        WHERE token_hash IS NULL;
+        ALTER TABLE refresh_tokens DROP COLUMN token;
+    END IF;
+END $$;
+
+-- Tokens issued before rotation each start their own family
+UPDATE refresh_tokens SET family_id = 'legacy-' || id WHERE family_id IS NULL;
+
+ALTER TABLE refresh_tokens ALTER COLUMN token_hash SET NOT NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN family_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_refresh_tokens_token;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_hash ON refresh_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens(family_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_user;
+
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS signed_in_at;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS ip;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS user_agent;
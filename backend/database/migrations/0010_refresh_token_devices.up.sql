@@ -0,0 +1,15 @@
+-- Remember which device each sign-in came from so users can review and revoke them
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS user_agent TEXT NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS ip VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS signed_in_at TIMESTAMP WITH TIME ZONE;
+
+-- Existing sign-ins started when the oldest token still stored for them was issued
+UPDATE refresh_tokens t SET signed_in_at = (
+    SELECT MIN(f.created_at) FROM refresh_tokens f WHERE f.family_id = t.family_id
+) WHERE signed_in_at IS NULL;
+
+ALTER TABLE refresh_tokens ALTER COLUMN signed_in_at SET DEFAULT NOW();
+ALTER TABLE refresh_tokens ALTER COLUMN signed_in_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON refresh_tokens(user_id);
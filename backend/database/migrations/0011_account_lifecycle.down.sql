@@ -0,0 +1,5 @@
+ALTER TABLE users DROP COLUMN IF EXISTS anonymized_at;
+
+ALTER TABLE sessions DROP CONSTRAINT IF EXISTS sessions_created_by_fkey;
+ALTER TABLE sessions ADD CONSTRAINT sessions_created_by_fkey
+    FOREIGN KEY (created_by) REFERENCES users(id);
@@ -0,0 +1,7 @@
+-- Accounts can be deleted outright; sessions they scheduled stay behind without a creator
+
+ALTER TABLE sessions DROP CONSTRAINT IF EXISTS sessions_created_by_fkey;
+ALTER TABLE sessions ADD CONSTRAINT sessions_created_by_fkey
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMP WITH TIME ZONE;
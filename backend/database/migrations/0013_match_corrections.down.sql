@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_rating_history_match;
+DROP TABLE IF EXISTS match_corrections;
@@ -0,0 +1,17 @@
+-- Edits and voids of finished matches, kept so every correction can be traced
+
+CREATE TABLE IF NOT EXISTS match_corrections (
+    id SERIAL PRIMARY KEY,
+    match_id INTEGER NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    corrected_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    kind VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    result_before VARCHAR(50) NOT NULL,
+    result_after VARCHAR(50) NOT NULL,
+    scores_before JSONB NOT NULL DEFAULT '[]',
+    scores_after JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_match_corrections_match ON match_corrections(match_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_rating_history_match ON rating_history(match_id);
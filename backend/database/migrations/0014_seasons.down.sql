@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS season_stats;
+DROP TABLE IF EXISTS seasons;
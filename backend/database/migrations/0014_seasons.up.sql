@@ -0,0 +1,35 @@
+-- Club seasons and each player's record and rating within a season
+
+CREATE TABLE IF NOT EXISTS seasons (
+    id SERIAL PRIMARY KEY,
+    club_id INTEGER NOT NULL REFERENCES clubs(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    rating_carryover DOUBLE PRECISION NOT NULL DEFAULT 1,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    ended_at TIMESTAMP WITH TIME ZONE,
+    started_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    ended_by INTEGER REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- A club runs at most one season at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_seasons_active ON seasons(club_id) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_seasons_club ON seasons(club_id, started_at);
+
+CREATE TABLE IF NOT EXISTS season_stats (
+    season_id INTEGER NOT NULL REFERENCES seasons(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    start_rating DOUBLE PRECISION NOT NULL,
+    start_deviation DOUBLE PRECISION NOT NULL,
+    start_volatility DOUBLE PRECISION NOT NULL,
+    total_matches INTEGER NOT NULL DEFAULT 0,
+    wins INTEGER NOT NULL DEFAULT 0,
+    losses INTEGER NOT NULL DEFAULT 0,
+    win_rate DOUBLE PRECISION NOT NULL DEFAULT 0,
+    current_streak INTEGER NOT NULL DEFAULT 0,
+    best_streak INTEGER NOT NULL DEFAULT 0,
+    rating DOUBLE PRECISION NOT NULL,
+    rating_deviation DOUBLE PRECISION NOT NULL,
+    volatility DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (season_id, user_id)
+);
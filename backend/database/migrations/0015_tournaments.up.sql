@@ -0,0 +1,51 @@
+-- Elimination tournaments: registered entries and the bracket they play through
+
+CREATE TABLE IF NOT EXISTS tournaments (
+    id SERIAL PRIMARY KEY,
+    club_id INTEGER NOT NULL REFERENCES clubs(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    format VARCHAR(30) NOT NULL,
+    entry_type VARCHAR(20) NOT NULL,
+    seeding VARCHAR(20) NOT NULL,
+    points_to_win INTEGER NOT NULL DEFAULT 21,
+    point_cap INTEGER NOT NULL DEFAULT 30,
+    best_of INTEGER NOT NULL DEFAULT 3,
+    status VARCHAR(20) NOT NULL DEFAULT 'registration',
+    winner_entry_id INTEGER,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_tournaments_club ON tournaments(club_id, created_at);
+
+CREATE TABLE IF NOT EXISTS tournament_entries (
+    id SERIAL PRIMARY KEY,
+    tournament_id INTEGER NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    players INTEGER[] NOT NULL,
+    seed INTEGER NOT NULL DEFAULT 0,
+    strength DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tournament_entries_tournament ON tournament_entries(tournament_id);
+
+CREATE TABLE IF NOT EXISTS bracket_matches (
+    id SERIAL PRIMARY KEY,
+    tournament_id INTEGER NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    bracket VARCHAR(20) NOT NULL,
+    round INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    entry1_id INTEGER REFERENCES tournament_entries(id) ON DELETE SET NULL,
+    entry2_id INTEGER REFERENCES tournament_entries(id) ON DELETE SET NULL,
+    winner_entry_id INTEGER REFERENCES tournament_entries(id) ON DELETE SET NULL,
+    loser_entry_id INTEGER REFERENCES tournament_entries(id) ON DELETE SET NULL,
+    match_id INTEGER UNIQUE REFERENCES matches(id) ON DELETE SET NULL,
+    next_match_id INTEGER REFERENCES bracket_matches(id) ON DELETE SET NULL,
+    next_slot INTEGER NOT NULL DEFAULT 0,
+    loser_next_match_id INTEGER REFERENCES bracket_matches(id) ON DELETE SET NULL,
+    loser_next_slot INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    UNIQUE (tournament_id, bracket, round, position)
+);
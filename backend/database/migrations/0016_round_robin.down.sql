@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS tournament_fixtures;
+
+ALTER TABLE tournament_entries DROP COLUMN IF EXISTS group_number;
+
+ALTER TABLE tournaments DROP COLUMN IF EXISTS slot_minutes;
+ALTER TABLE tournaments DROP COLUMN IF EXISTS starts_at;
+ALTER TABLE tournaments DROP COLUMN IF EXISTS courts;
+ALTER TABLE tournaments DROP COLUMN IF EXISTS group_count;
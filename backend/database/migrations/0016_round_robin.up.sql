@@ -0,0 +1,25 @@
+-- Round-robin leagues: group draws and the fixture list scheduled across courts and time slots
+
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS group_count INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS courts TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS starts_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS slot_minutes INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE tournament_entries ADD COLUMN IF NOT EXISTS group_number INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS tournament_fixtures (
+    id SERIAL PRIMARY KEY,
+    tournament_id INTEGER NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    group_number INTEGER NOT NULL,
+    round INTEGER NOT NULL,
+    slot INTEGER NOT NULL,
+    court VARCHAR(100) NOT NULL,
+    scheduled_at TIMESTAMP WITH TIME ZONE,
+    entry1_id INTEGER NOT NULL REFERENCES tournament_entries(id) ON DELETE CASCADE,
+    entry2_id INTEGER NOT NULL REFERENCES tournament_entries(id) ON DELETE CASCADE,
+    match_id INTEGER UNIQUE REFERENCES matches(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    UNIQUE (tournament_id, slot, court)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tournament_fixtures_tournament ON tournament_fixtures(tournament_id, slot);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_matches_pending_court;
+
+ALTER TABLE matches DROP COLUMN IF EXISTS court_id;
@@ -0,0 +1,34 @@
+-- Matches point at their court by ID, so renaming a court keeps its pending matches
+-- and a court can only hold one pending match at a time
+
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS court_id INTEGER REFERENCES courts(id) ON DELETE SET NULL;
+
+UPDATE matches m SET court_id = c.id
+FROM courts c
+WHERE m.court_id IS NULL AND c.club_id = m.club_id AND c.name = m.court;
+
+-- Courts that already hold several pending matches keep the newest one; the older ones
+-- were abandoned, so they are voided with a correction explaining why
+WITH duplicates AS (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (PARTITION BY court_id ORDER BY started_at DESC, id DESC) AS n
+        FROM matches
+        WHERE result = 'pending' AND court_id IS NOT NULL
+    ) ranked
+    WHERE n > 1
+), voided AS (
+    UPDATE matches SET result = 'void', ended_at = NOW()
+    WHERE id IN (SELECT id FROM duplicates)
+    RETURNING id
+)
+INSERT INTO match_corrections (match_id, kind, reason, result_before, result_after)
+SELECT id, 'void', 'Another match was pending on the same court', 'pending', 'void' FROM voided;
+
+-- Tournament matches that were on a voided match go back to be put on a court again
+UPDATE bracket_matches SET match_id = NULL, status = 'ready'
+WHERE status = 'playing' AND match_id IN (SELECT id FROM matches WHERE result = 'void');
+
+UPDATE tournament_fixtures SET match_id = NULL, status = 'scheduled'
+WHERE status = 'playing' AND match_id IN (SELECT id FROM matches WHERE result = 'void');
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_matches_pending_court ON matches(court_id) WHERE result = 'pending';
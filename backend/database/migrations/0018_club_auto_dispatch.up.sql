@@ -0,0 +1,3 @@
+-- Auto-dispatch is a club setting. NULL follows the server's AUTO_DISPATCH default.
+
+ALTER TABLE clubs ADD COLUMN IF NOT EXISTS auto_dispatch BOOLEAN;
@@ -0,0 +1,13 @@
+-- Users join a club by accepting an invite from one of its admins, so a club admin
+-- cannot pull someone's account into their club without consent
+
+CREATE TABLE IF NOT EXISTS club_invites (
+    club_id INTEGER REFERENCES clubs(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(50) NOT NULL DEFAULT 'player',
+    invited_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (club_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_club_invites_user ON club_invites(user_id);
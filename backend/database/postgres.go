@@ -3,69 +3,104 @@ package database
 import (
 	"backend/config"
 	"context"
-	"fmt"
-	"log"
+	"database/sql"
+	"errors"
 	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
-// DB holds the database connection pool
-var DB *pgxpool.Pool
-
-// Connect establishes a connection to the PostgreSQL database
-func Connect(cfg *config.Config) (*pgxpool.Pool, error) {
-	connString := cfg.Database.ConnectionString()
-
-	poolConfig, err := pgxpool.ParseConfig(connString)
+// Open connects to PostgreSQL through the pgx driver and configures the pool
+func Open(cfg *config.Config) (*sql.DB, error) {
+	db, err := sql.Open("pgx", cfg.Database.ConnectionString())
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse database config: %w", err)
+		return nil, err
 	}
 
 	// Configure connection pool
-	poolConfig.MaxConns = 25
-	poolConfig.MinConns = 5
-	poolConfig.MaxConnLifetime = time.Hour
-	poolConfig.MaxConnIdleTime = 30 * time.Minute
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(5)
+	db.SetConnMaxLifetime(time.Hour)
+	db.SetConnMaxIdleTime(30 * time.Minute)
 
-	// Create connection pool
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	// Test connection
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create connection pool: %w", err)
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
 	}
 
-	// Test connection
-	if err := pool.Ping(ctx); err != nil {
-		pool.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
-	}
+	return db, nil
+}
 
-	log.Printf("✓ Connected to PostgreSQL at %s:%s/%s",
-		cfg.Database.Host, cfg.Database.Port, cfg.Database.Name)
+// dbtx is satisfied by both *sql.DB and *sql.Tx so repositories can run inside a transaction
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
 
-	DB = pool
-	return pool, nil
+// PostgresStore implements Store on a PostgreSQL database
+type PostgresStore struct {
+	db *sql.DB
+	q  dbtx
 }
 
-// Close closes the database connection pool
-func Close() {
-	if DB != nil {
-		DB.Close()
-		log.Println("Database connection closed")
-	}
+// NewPostgresStore creates a store backed by db
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db, q: db}
 }
 
-// HealthCheck verifies the database connection is alive
-func HealthCheck() error {
-	if DB == nil {
-		return fmt.Errorf("database not connected")
+func (s *PostgresStore) Users() UserRepository             { return &userRepo{q: s.q} }
+func (s *PostgresStore) Stats() StatsRepository            { return &statsRepo{q: s.q} }
+func (s *PostgresStore) Tokens() TokenRepository           { return &tokenRepo{q: s.q} }
+func (s *PostgresStore) Resets() ResetRepository           { return &resetRepo{q: s.q} }
+func (s *PostgresStore) LoginEvents() LoginEventRepository { return &loginEventRepo{q: s.q} }
+func (s *PostgresStore) Audit() AuditRepository            { return &auditRepo{q: s.q} }
+func (s *PostgresStore) Queue() QueueRepository            { return &queueRepo{q: s.q} }
+func (s *PostgresStore) Matches() MatchRepository          { return &matchRepo{q: s.q} }
+func (s *PostgresStore) Seasons() SeasonRepository         { return &seasonRepo{q: s.q} }
+func (s *PostgresStore) Tournaments() TournamentRepository { return &tournamentRepo{q: s.q} }
+func (s *PostgresStore) Clubs() ClubRepository             { return &clubRepo{q: s.q} }
+func (s *PostgresStore) Courts() CourtRepository           { return &courtRepo{q: s.q} }
+func (s *PostgresStore) Sessions() SessionRepository       { return &sessionRepo{q: s.q} }
+
+// WithTx runs fn in a transaction, committing on success and rolling back on error.
+// Calls made while already in a transaction join it.
+func (s *PostgresStore) WithTx(ctx context.Context, fn func(tx Store) error) error {
+	if _, inTx := s.q.(*sql.Tx); inTx {
+		return fn(s)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
 
-	return DB.Ping(ctx)
+	if err := fn(&PostgresStore{db: s.db, q: tx}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// sessionArg maps session 0 (the club's queue outside any session) to NULL
+func sessionArg(sessionID int64) interface{} {
+	if sessionID == 0 {
+		return nil
+	}
+	return sessionID
+}
+
+// duplicateErr maps a unique constraint violation to ErrDuplicate
+func duplicateErr(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrDuplicate
+	}
+	return err
 }
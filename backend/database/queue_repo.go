@@ -0,0 +1,208 @@
+package database
+
+import (
+	"backend/model"
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+// queueRepo implements QueueRepository on PostgreSQL
+type queueRepo struct {
+	q dbtx
+}
+
+const queueColumns = `id, session_id, user_id, position, status, joined_at, called_at`
+
+func scanQueueEntries(rows *sql.Rows, err error) ([]model.QueueEntry, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []model.QueueEntry
+	for rows.Next() {
+		var entry model.QueueEntry
+		if err := rows.Scan(&entry.ID, &entry.SessionID, &entry.UserID, &entry.Position,
+			&entry.Status, &entry.JoinedAt, &entry.CalledAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// Count returns how many entries in a queue have the given status
+func (r *queueRepo) Count(ctx context.Context, clubID, sessionID int64, status model.QueueStatus) (int, error) {
+	var count int
+	err := r.q.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM queue_entries
+		WHERE status = $3 AND club_id = $1 AND session_id IS NOT DISTINCT FROM $2
+	`, clubID, sessionArg(sessionID), status).Scan(&count)
+	return count, err
+}
+
+// Position returns a waiting player's place in line
+func (r *queueRepo) Position(ctx context.Context, clubID, sessionID, userID int64) (int, error) {
+	var position int
+	err := r.q.QueryRowContext(ctx, `
+		SELECT position FROM queue_entries
+		WHERE user_id = $3 AND status = 'waiting' AND club_id = $1 AND session_id IS NOT DISTINCT FROM $2
+	`, clubID, sessionArg(sessionID), userID).Scan(&position)
+	if err == sql.ErrNoRows {
+		return 0, ErrNotFound
+	}
+	return position, err
+}
+
+// Playing returns the most recently called players now on court
+func (r *queueRepo) Playing(ctx context.Context, clubID, sessionID int64, limit int) ([]model.QueueEntry, error) {
+	return scanQueueEntries(r.q.QueryContext(ctx, `
+		SELECT `+queueColumns+`
+		FROM queue_entries WHERE status = 'playing' AND club_id = $1 AND session_id IS NOT DISTINCT FROM $2
+		ORDER BY called_at DESC LIMIT $3
+	`, clubID, sessionArg(sessionID), limit))
+}
+
+// Add appends a waiting player to the end of the line
+func (r *queueRepo) Add(ctx context.Context, clubID, sessionID, userID int64) (*model.QueueEntry, error) {
+	var entry model.QueueEntry
+	err := r.q.QueryRowContext(ctx, `
+		INSERT INTO queue_entries (club_id, session_id, user_id, position, status, joined_at)
+		SELECT $1, $2, $3, COALESCE(MAX(position), 0) + 1, 'waiting', NOW()
+		FROM queue_entries
+		WHERE status = 'waiting' AND club_id = $1 AND session_id IS NOT DISTINCT FROM $2
+		RETURNING `+queueColumns,
+		clubID, sessionArg(sessionID), userID).Scan(
+		&entry.ID, &entry.SessionID, &entry.UserID, &entry.Position, &entry.Status, &entry.JoinedAt, &entry.CalledAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Remove takes a waiting player out of line
+func (r *queueRepo) Remove(ctx context.Context, clubID, sessionID, userID int64) (bool, error) {
+	result, err := r.q.ExecContext(ctx, `
+		DELETE FROM queue_entries
+		WHERE user_id = $3 AND status = 'waiting' AND club_id = $1 AND session_id IS NOT DISTINCT FROM $2
+	`, clubID, sessionArg(sessionID), userID)
+	if err != nil {
+		return false, err
+	}
+
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// CallNext marks the first count waiting players as called
+func (r *queueRepo) CallNext(ctx context.Context, clubID, sessionID int64, count int) ([]model.QueueEntry, error) {
+	return scanQueueEntries(r.q.QueryContext(ctx, `
+		UPDATE queue_entries
+		SET status = 'called', called_at = NOW()
+		WHERE id IN (
+			SELECT id FROM queue_entries
+			WHERE status = 'waiting' AND club_id = $1 AND session_id IS NOT DISTINCT FROM $2
+			ORDER BY position LIMIT $3
+		)
+		RETURNING `+queueColumns,
+		clubID, sessionArg(sessionID), count))
+}
+
+// Called returns players who have been called but not yet put on a court
+func (r *queueRepo) Called(ctx context.Context, clubID, sessionID int64) ([]model.QueueEntry, error) {
+	return scanQueueEntries(r.q.QueryContext(ctx, `
+		SELECT `+queueColumns+`
+		FROM queue_entries WHERE status = 'called' AND club_id = $1 AND session_id IS NOT DISTINCT FROM $2
+		ORDER BY called_at, position
+	`, clubID, sessionArg(sessionID)))
+}
+
+// Requeue puts called entries back at the front of the line in the given order
+func (r *queueRepo) Requeue(ctx context.Context, clubID, sessionID int64, entries []model.QueueEntry) error {
+	// Make room at the front of the queue
+	if _, err := r.q.ExecContext(ctx, `
+		UPDATE queue_entries SET position = position + $3
+		WHERE status = 'waiting' AND club_id = $1 AND session_id IS NOT DISTINCT FROM $2
+	`, clubID, sessionArg(sessionID), len(entries)); err != nil {
+		return err
+	}
+
+	for i, entry := range entries {
+		if _, err := r.q.ExecContext(ctx, `
+			UPDATE queue_entries SET status = 'waiting', position = $2, called_at = NULL
+			WHERE id = $1 AND status = 'called'
+		`, entry.ID, i+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Clear drops everyone still waiting or called from a line
+func (r *queueRepo) Clear(ctx context.Context, clubID, sessionID int64) error {
+	_, err := r.q.ExecContext(ctx, `
+		DELETE FROM queue_entries
+		WHERE status IN ('waiting', 'called') AND club_id = $1 AND session_id IS NOT DISTINCT FROM $2
+	`, clubID, sessionArg(sessionID))
+	return err
+}
+
+// Reorder closes gaps in waiting positions
+func (r *queueRepo) Reorder(ctx context.Context, clubID, sessionID int64) error {
+	_, err := r.q.ExecContext(ctx, `
+		WITH ordered AS (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY position) as new_pos
+			FROM queue_entries WHERE status = 'waiting' AND club_id = $1 AND session_id IS NOT DISTINCT FROM $2
+		)
+		UPDATE queue_entries SET position = ordered.new_pos
+		FROM ordered WHERE queue_entries.id = ordered.id
+	`, clubID, sessionArg(sessionID))
+	return err
+}
+
+// Withdraw takes a player out of every line they are waiting in and closes the gaps left behind
+func (r *queueRepo) Withdraw(ctx context.Context, userID int64) error {
+	result, err := r.q.ExecContext(ctx, `
+		DELETE FROM queue_entries WHERE user_id = $1 AND status = 'waiting'
+	`, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil
+	}
+
+	_, err = r.q.ExecContext(ctx, `
+		WITH ordered AS (
+			SELECT id, ROW_NUMBER() OVER (PARTITION BY club_id, session_id ORDER BY position) as new_pos
+			FROM queue_entries WHERE status = 'waiting'
+		)
+		UPDATE queue_entries SET position = ordered.new_pos
+		FROM ordered WHERE queue_entries.id = ordered.id AND queue_entries.position != ordered.new_pos
+	`)
+	return err
+}
+
+// MarkPlaying moves waiting or called players onto a court
+func (r *queueRepo) MarkPlaying(ctx context.Context, clubID, sessionID int64, userIDs []int64) error {
+	_, err := r.q.ExecContext(ctx, `
+		UPDATE queue_entries SET status = 'playing'
+		WHERE user_id = ANY($3) AND status IN ('waiting', 'called')
+		  AND club_id = $1 AND session_id IS NOT DISTINCT FROM $2
+	`, clubID, sessionArg(sessionID), pq.Array(userIDs))
+	return err
+}
+
+// RemovePlaying drops players whose match has finished
+func (r *queueRepo) RemovePlaying(ctx context.Context, clubID, sessionID int64, userIDs []int64) error {
+	_, err := r.q.ExecContext(ctx, `
+		DELETE FROM queue_entries
+		WHERE user_id = ANY($3) AND status = 'playing' AND club_id = $1 AND session_id IS NOT DISTINCT FROM $2
+	`, clubID, sessionArg(sessionID), pq.Array(userIDs))
+	return err
+}
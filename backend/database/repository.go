@@ -0,0 +1,375 @@
+package database
+
+import (
+	"backend/model"
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotFound  = errors.New("record not found")
+	ErrDuplicate = errors.New("record already exists")
+)
+
+// Store gives services access to the repositories. Postgres and in-memory
+// implementations behave the same so services can be exercised without a database.
+type Store interface {
+	Users() UserRepository
+	Stats() StatsRepository
+	Tokens() TokenRepository
+	Resets() ResetRepository
+	LoginEvents() LoginEventRepository
+	Audit() AuditRepository
+	Queue() QueueRepository
+	Matches() MatchRepository
+	Seasons() SeasonRepository
+	Tournaments() TournamentRepository
+	Clubs() ClubRepository
+	Courts() CourtRepository
+	Sessions() SessionRepository
+
+	// WithTx runs fn with repositories that commit together or not at all
+	WithTx(ctx context.Context, fn func(tx Store) error) error
+}
+
+// UserRepository stores user accounts and their club memberships
+type UserRepository interface {
+	// Create inserts a user, initializes their stats and adds them to the default club
+	// with their account role. ID and timestamps are set on user.
+	Create(ctx context.Context, user *model.User) error
+	GetByID(ctx context.Context, id int64) (*model.User, error)
+	// GetForUpdate returns a user, locking their row until the transaction ends
+	GetForUpdate(ctx context.Context, id int64) (*model.User, error)
+	// GetByLogin finds a user by username or phone, including the password hash
+	GetByLogin(ctx context.Context, login string) (*model.User, error)
+	GetByUsername(ctx context.Context, username string) (*model.User, error)
+	GetMany(ctx context.Context, ids []int64) ([]model.User, error)
+	UsernameExists(ctx context.Context, username string) (bool, error)
+	PhoneExists(ctx context.Context, phone string) (bool, error)
+	UpdateProfile(ctx context.Context, id int64, req model.UpdateProfileRequest) (*model.User, error)
+	UpdatePassword(ctx context.Context, id int64, passwordHash string) error
+	// UpdatePlayer sets hand and tier for a member of the club
+	UpdatePlayer(ctx context.Context, clubID, id int64, hand model.HandPreference, tier model.SkillTier) error
+	// RecordLogin stamps a successful login and clears failed attempts and any lockout
+	RecordLogin(ctx context.Context, id int64) error
+	// RecordFailedLogin counts a wrong password and returns the failures since the last success
+	RecordFailedLogin(ctx context.Context, id int64) (int, error)
+	// Lock blocks logins to the account until the given time
+	Lock(ctx context.Context, id int64, until time.Time) error
+	// Unlock clears failed attempts and any lockout
+	Unlock(ctx context.Context, id int64) error
+	// SetRole changes a user's account role
+	SetRole(ctx context.Context, id int64, role model.Role) error
+	// SetActive enables or disables logins for the account
+	SetActive(ctx context.Context, id int64, active bool) error
+	// Anonymize scrubs personal details from the account, demotes and disables it, keeping the row
+	// so matches and stats stay consistent. Login history, reset codes, club memberships and
+	// invites are deleted.
+	Anonymize(ctx context.Context, id int64, username, passwordHash string) error
+	// Delete removes the account and everything that cascades from it
+	Delete(ctx context.Context, id int64) error
+	// CountActiveAdmins returns how many enabled accounts have the admin role
+	CountActiveAdmins(ctx context.Context) (int, error)
+	// ListByClub returns a club's members with their club role and stats
+	ListByClub(ctx context.Context, clubID int64) ([]model.UserListItem, error)
+	// AllInClub reports whether every user belongs to the club
+	AllInClub(ctx context.Context, clubID int64, ids []int64) (bool, error)
+}
+
+// StatsRepository stores player records, ratings and rating history
+type StatsRepository interface {
+	// Get returns a player's stats, creating an empty record if they have none
+	Get(ctx context.Context, userID int64) (*model.UserStats, error)
+	// GetMany returns stats for the players that have them
+	GetMany(ctx context.Context, userIDs []int64) (map[int64]model.UserStats, error)
+	// SaveRecord stores match counts, streaks, win rate and skill level
+	SaveRecord(ctx context.Context, stats *model.UserStats) error
+	// SaveRating stores a player's new rating and appends the change to their history,
+	// dated change.CreatedAt or now when that is zero
+	SaveRating(ctx context.Context, change model.RatingChange, volatility float64) error
+	RatingHistory(ctx context.Context, userID int64, limit int) ([]model.RatingChange, error)
+	// LatestRatings returns each player's most recent rating change, for players that have one
+	LatestRatings(ctx context.Context, userIDs []int64) (map[int64]model.RatingChange, error)
+	// RatingChanges returns every player's rating change from the given matches
+	RatingChanges(ctx context.Context, matchIDs []int64) ([]model.RatingChange, error)
+	// DeleteRatingChanges drops the rating history of the given matches
+	DeleteRatingChanges(ctx context.Context, matchIDs []int64) error
+	// ClearRatingHistory drops all of a player's rating changes
+	ClearRatingHistory(ctx context.Context, userID int64) error
+	// Reset returns every player to an unplayed record and rating and drops all rating history
+	Reset(ctx context.Context) error
+	// SetRating stores a player's rating without recording a change
+	SetRating(ctx context.Context, userID int64, rating, deviation, volatility float64) error
+}
+
+// TokenRepository stores refresh tokens by hash. Tokens issued by refreshing one
+// another share a family, so a whole sign-in can be revoked at once.
+type TokenRepository interface {
+	Create(ctx context.Context, token *model.RefreshToken) error
+	// GetByHash finds a token by the hash of its value, whether or not it is still usable
+	GetByHash(ctx context.Context, hash string) (*model.RefreshToken, error)
+	// Rotate marks a usable token as replaced, reporting false if it was already rotated or revoked
+	Rotate(ctx context.Context, id int64) (bool, error)
+	// RevokeFamily invalidates every token from the same sign-in
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID int64) error
+	// RevokeOthers invalidates every token for a user outside the given family
+	RevokeOthers(ctx context.Context, userID int64, familyID string) error
+	// Active returns a user's usable tokens, one per signed-in device, most recently used first
+	Active(ctx context.Context, userID int64) ([]model.RefreshToken, error)
+	// DeleteExpired removes expired tokens. Rotated and revoked tokens are kept until
+	// they expire so reuse can still be detected.
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// ResetRepository stores password reset codes by hash
+type ResetRepository interface {
+	Create(ctx context.Context, reset *model.PasswordReset) error
+	// Active returns a user's newest unused, unexpired reset code
+	Active(ctx context.Context, userID int64) (*model.PasswordReset, error)
+	// ClaimAttempt counts a guess against a code, reporting false once it has had limit guesses
+	ClaimAttempt(ctx context.Context, id int64, limit int) (bool, error)
+	// CountSince counts the codes issued to a user since the given time
+	CountSince(ctx context.Context, userID int64, since time.Time) (int, error)
+	// Use marks a code as spent, reporting false if it was already used
+	Use(ctx context.Context, id int64) (bool, error)
+	// InvalidateForUser spends every outstanding code for a user
+	InvalidateForUser(ctx context.Context, userID int64) error
+}
+
+// LoginEventFilter narrows login event listings. Zero values mean no filter.
+type LoginEventFilter struct {
+	UserID int64
+	Kind   model.LoginEventKind
+	Limit  int
+}
+
+// LoginEventRepository stores suspicious and security-relevant login events
+type LoginEventRepository interface {
+	Create(ctx context.Context, event *model.LoginEvent) error
+	// List returns matching events, newest first
+	List(ctx context.Context, filter LoginEventFilter) ([]model.LoginEvent, error)
+}
+
+// AuditFilter narrows audit log listings. Zero values mean no filter.
+type AuditFilter struct {
+	ClubID     int64
+	ActorID    int64
+	Action     model.AuditAction
+	TargetType string
+	TargetID   string
+	Since      time.Time
+	Until      time.Time
+	Limit      int
+}
+
+// AuditRepository stores the audit log of privileged changes
+type AuditRepository interface {
+	Create(ctx context.Context, event *model.AuditEvent) error
+	// List returns matching events, newest first
+	List(ctx context.Context, filter AuditFilter) ([]model.AuditEvent, error)
+}
+
+// QueueRepository stores queue entries. Every call is scoped to one club session,
+// where session 0 is the club's queue outside any session.
+type QueueRepository interface {
+	Count(ctx context.Context, clubID, sessionID int64, status model.QueueStatus) (int, error)
+	// Position returns a waiting player's place in line
+	Position(ctx context.Context, clubID, sessionID, userID int64) (int, error)
+	// Playing returns the most recently called players now on court
+	Playing(ctx context.Context, clubID, sessionID int64, limit int) ([]model.QueueEntry, error)
+	// Add appends a waiting player to the end of the line
+	Add(ctx context.Context, clubID, sessionID, userID int64) (*model.QueueEntry, error)
+	// Remove takes a waiting player out of line and reports whether they were in it
+	Remove(ctx context.Context, clubID, sessionID, userID int64) (bool, error)
+	// CallNext marks the first count waiting players as called
+	CallNext(ctx context.Context, clubID, sessionID int64, count int) ([]model.QueueEntry, error)
+	Called(ctx context.Context, clubID, sessionID int64) ([]model.QueueEntry, error)
+	// Requeue puts called entries back at the front of the line in the given order
+	Requeue(ctx context.Context, clubID, sessionID int64, entries []model.QueueEntry) error
+	// Clear drops everyone still waiting or called from a line
+	Clear(ctx context.Context, clubID, sessionID int64) error
+	// Reorder closes gaps in waiting positions
+	Reorder(ctx context.Context, clubID, sessionID int64) error
+	// MarkPlaying moves waiting or called players onto a court
+	MarkPlaying(ctx context.Context, clubID, sessionID int64, userIDs []int64) error
+	// RemovePlaying drops players whose match has finished
+	RemovePlaying(ctx context.Context, clubID, sessionID int64, userIDs []int64) error
+	// Withdraw takes a player out of every line they are waiting in, in any club or session
+	Withdraw(ctx context.Context, userID int64) error
+}
+
+// MatchFilter narrows match listings. Zero values mean no filter.
+type MatchFilter struct {
+	ClubID    int64
+	SessionID int64
+	UserID    int64
+	Since     time.Time // Matches that ended at or after
+	Until     time.Time // Matches that ended before
+	Limit     int
+}
+
+// MatchRepository stores matches and their game scores
+type MatchRepository interface {
+	// Create inserts a pending match; ID and timestamps are set on match. ErrDuplicate when
+	// its court already has a pending match.
+	Create(ctx context.Context, match *model.Match) error
+	// GetForUpdate returns a club's match with its scores, locking it until the transaction ends
+	GetForUpdate(ctx context.Context, clubID, id int64) (*model.Match, error)
+	// Complete stores the result and game scores of a match
+	Complete(ctx context.Context, id int64, result string, endedAt time.Time, scores []model.GameScore) error
+	// Amend replaces the result and game scores of a finished match, keeping when it ended
+	Amend(ctx context.Context, id int64, result string, scores []model.GameScore) error
+	// AddCorrection records a change to a finished match; ID and CreatedAt are set on correction
+	AddCorrection(ctx context.Context, correction *model.MatchCorrection) error
+	// Corrections returns the corrections of a club's match, oldest first
+	Corrections(ctx context.Context, clubID, matchID int64) ([]model.MatchCorrection, error)
+	// DecidedFrom returns won or lost matches in every club that ended at or after the given
+	// match (ordered by end time, then ID), oldest first with their scores
+	DecidedFrom(ctx context.Context, endedAt time.Time, id int64) ([]model.Match, error)
+	// Decided returns won or lost matches, oldest first with their scores. Club 0 covers every club.
+	Decided(ctx context.Context, filter MatchFilter) ([]model.Match, error)
+	// DecidedByID returns the won or lost matches among the given IDs, oldest first with their scores
+	DecidedByID(ctx context.Context, ids []int64) ([]model.Match, error)
+	// History returns matches a player took part in, newest first
+	History(ctx context.Context, filter MatchFilter) ([]model.Match, error)
+	// Active returns pending matches, newest first
+	Active(ctx context.Context, filter MatchFilter) ([]model.Match, error)
+	// Completed returns finished matches with scores, most recently ended first
+	Completed(ctx context.Context, filter MatchFilter) ([]model.Match, error)
+	// Played returns matches involving any of the players, newest first
+	Played(ctx context.Context, userIDs []int64, limit int) ([]model.Match, error)
+	// PendingFor returns a player's unfinished matches in every club, newest first
+	PendingFor(ctx context.Context, userID int64) ([]model.Match, error)
+	// Outcomes returns whether the player won each of their decided club matches, oldest first.
+	// Club 0 covers every club.
+	Outcomes(ctx context.Context, clubID, userID int64) ([]bool, error)
+	// Durations returns how long recent finished club matches took, ignoring any over max
+	Durations(ctx context.Context, clubID int64, limit int, max time.Duration) ([]time.Duration, error)
+}
+
+// SeasonRepository stores club seasons and each player's record within them
+type SeasonRepository interface {
+	// Create inserts an active season starting now; ID, status and StartedAt are set on season
+	Create(ctx context.Context, season *model.Season) error
+	// Get returns a season by ID. Club 0 matches any club.
+	Get(ctx context.Context, clubID, id int64) (*model.Season, error)
+	// Active returns the club's running season
+	Active(ctx context.Context, clubID int64) (*model.Season, error)
+	// List returns the club's seasons, newest first
+	List(ctx context.Context, clubID int64) ([]model.Season, error)
+	// End marks an active season ended
+	End(ctx context.Context, id int64, endedBy *int64, endedAt time.Time) error
+	// SavePlayers inserts or replaces players' season records
+	SavePlayers(ctx context.Context, players []model.SeasonStats) error
+	// Players returns a season's player records
+	Players(ctx context.Context, seasonID int64) ([]model.SeasonStats, error)
+}
+
+// TournamentRepository stores tournaments, their entries, brackets and round-robin fixtures
+type TournamentRepository interface {
+	// Create inserts a tournament open for registration; ID, status and CreatedAt are set on it
+	Create(ctx context.Context, tournament *model.Tournament) error
+	// Get returns one of a club's tournaments
+	Get(ctx context.Context, clubID, id int64) (*model.Tournament, error)
+	// GetForUpdate returns one of a club's tournaments, locking it until the transaction ends
+	GetForUpdate(ctx context.Context, clubID, id int64) (*model.Tournament, error)
+	// List returns the club's tournaments, newest first
+	List(ctx context.Context, clubID int64) ([]model.Tournament, error)
+	// Update stores a tournament's status, winner and start and completion times
+	Update(ctx context.Context, tournament *model.Tournament) error
+	// AddEntry registers a player or pair; ID and CreatedAt are set on entry
+	AddEntry(ctx context.Context, entry *model.TournamentEntry) error
+	// RemoveEntry withdraws an entry from a tournament
+	RemoveEntry(ctx context.Context, tournamentID, entryID int64) error
+	// Entries returns a tournament's entries in registration order
+	Entries(ctx context.Context, tournamentID int64) ([]model.TournamentEntry, error)
+	// SeedEntries stores each entry's seed, strength and group
+	SeedEntries(ctx context.Context, entries []model.TournamentEntry) error
+	// AddBracketMatch inserts a bracket match; ID is set on match
+	AddBracketMatch(ctx context.Context, match *model.BracketMatch) error
+	// UpdateBracketMatch stores a bracket match's entries, result, match and status
+	UpdateBracketMatch(ctx context.Context, match model.BracketMatch) error
+	// BracketMatches returns a tournament's bracket: winners, losers, then final rounds
+	BracketMatches(ctx context.Context, tournamentID int64) ([]model.BracketMatch, error)
+	// BracketMatchFor returns the bracket match played as the given match
+	BracketMatchFor(ctx context.Context, matchID int64) (*model.BracketMatch, error)
+	// AddFixture inserts a round-robin fixture; ID is set on fixture
+	AddFixture(ctx context.Context, fixture *model.Fixture) error
+	// UpdateFixture stores a fixture's match and status
+	UpdateFixture(ctx context.Context, fixture model.Fixture) error
+	// Fixtures returns a tournament's fixtures by slot, then court
+	Fixtures(ctx context.Context, tournamentID int64) ([]model.Fixture, error)
+	// FixtureFor returns the fixture played as the given match
+	FixtureFor(ctx context.Context, matchID int64) (*model.Fixture, error)
+}
+
+// ClubRepository stores club tenants and their memberships
+type ClubRepository interface {
+	// Create inserts a club; ID and CreatedAt are set on club. ErrDuplicate when the slug is taken.
+	Create(ctx context.Context, club *model.Club) error
+	Get(ctx context.Context, id int64) (*model.Club, error)
+	GetBySlug(ctx context.Context, slug string) (*model.Club, error)
+	// List returns every club by name
+	List(ctx context.Context) ([]model.Club, error)
+	// ListForUser returns the clubs a user belongs to, by name, with their role in each
+	ListForUser(ctx context.Context, userID int64) ([]model.ClubMembership, error)
+	// SetAutoDispatch stores whether the club refills freed courts automatically
+	SetAutoDispatch(ctx context.Context, id int64, enabled bool) error
+	// Member returns a user's membership in a club
+	Member(ctx context.Context, clubID, userID int64) (*model.ClubMember, error)
+	// Members returns a club's members by name
+	Members(ctx context.Context, clubID int64) ([]model.ClubMember, error)
+	// SetMember adds a user to a club or changes their role there
+	SetMember(ctx context.Context, clubID, userID int64, role model.Role) error
+	// RemoveMember takes a user out of a club
+	RemoveMember(ctx context.Context, clubID, userID int64) error
+	// Invite stores an invite to the club, replacing any earlier one for the user;
+	// CreatedAt is set on invite
+	Invite(ctx context.Context, invite *model.ClubInvite) error
+	// GetInvite returns a user's pending invite to a club
+	GetInvite(ctx context.Context, clubID, userID int64) (*model.ClubInvite, error)
+	// Invites returns a user's pending invites, newest first
+	Invites(ctx context.Context, userID int64) ([]model.ClubInvite, error)
+	// DeleteInvite removes a pending invite
+	DeleteInvite(ctx context.Context, clubID, userID int64) error
+}
+
+// CourtRepository stores each club's court registry
+type CourtRepository interface {
+	// Create inserts a court; ID and timestamps are set on court. ErrDuplicate when the club
+	// already has a court with that name.
+	Create(ctx context.Context, clubID int64, court *model.Court) error
+	Get(ctx context.Context, clubID, id int64) (*model.Court, error)
+	GetByName(ctx context.Context, clubID int64, name string) (*model.Court, error)
+	// List returns a club's courts by venue and name, optionally only those open for play
+	List(ctx context.Context, clubID int64, activeOnly bool) ([]model.Court, error)
+	// Update stores a court's details; UpdatedAt is set on court. Pending matches on the court
+	// take its new name. ErrDuplicate when the new name is taken.
+	Update(ctx context.Context, clubID int64, court *model.Court) error
+	Delete(ctx context.Context, clubID, id int64) error
+	// NextAvailable returns the club's first active court without a pending match.
+	// When ids is non-empty only those courts are considered.
+	NextAvailable(ctx context.Context, clubID int64, ids []int64) (*model.Court, error)
+}
+
+// SessionRepository stores play sessions
+type SessionRepository interface {
+	// Create inserts a scheduled session; ID, status and CreatedAt are set on session
+	Create(ctx context.Context, session *model.Session) error
+	// Get returns a session by ID. Club 0 matches any club.
+	Get(ctx context.Context, clubID, id int64) (*model.Session, error)
+	// GetForUpdate returns one of a club's sessions, locking it until the transaction ends
+	GetForUpdate(ctx context.Context, clubID, id int64) (*model.Session, error)
+	// Current returns the club's most recently opened session that is still open
+	Current(ctx context.Context, clubID int64) (*model.Session, error)
+	// List returns a club's sessions, latest start first. An empty status matches any.
+	List(ctx context.Context, clubID int64, status model.SessionStatus, limit int) ([]model.Session, error)
+	// Update stores a session's details
+	Update(ctx context.Context, session *model.Session) error
+	// Open marks a scheduled session open; ErrNotFound unless it was scheduled
+	Open(ctx context.Context, clubID, id int64) (*model.Session, error)
+	// Close marks an open session closed; ErrNotFound unless it was open
+	Close(ctx context.Context, clubID, id int64) (*model.Session, error)
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"backend/model"
+	"context"
+	"database/sql"
+	"time"
+)
+
+// resetRepo implements ResetRepository on PostgreSQL
+type resetRepo struct {
+	q dbtx
+}
+
+// Create stores a new reset code
+func (r *resetRepo) Create(ctx context.Context, reset *model.PasswordReset) error {
+	return r.q.QueryRowContext(ctx, `
+		INSERT INTO password_resets (user_id, code_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, NOW())
+		RETURNING id, attempts, created_at
+	`, reset.UserID, reset.CodeHash, reset.ExpiresAt).Scan(&reset.ID, &reset.Attempts, &reset.CreatedAt)
+}
+
+// Active returns the user's newest reset code that can still be used
+func (r *resetRepo) Active(ctx context.Context, userID int64) (*model.PasswordReset, error) {
+	var reset model.PasswordReset
+	err := r.q.QueryRowContext(ctx, `
+		SELECT id, user_id, code_hash, attempts, expires_at, used_at, created_at
+		FROM password_resets
+		WHERE user_id = $1 AND used_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`, userID).Scan(&reset.ID, &reset.UserID, &reset.CodeHash, &reset.Attempts,
+		&reset.ExpiresAt, &reset.UsedAt, &reset.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &reset, nil
+}
+
+// ClaimAttempt counts a guess against a reset code unless it has used up its guesses.
+// Checking and counting in one statement keeps parallel guesses within the limit.
+func (r *resetRepo) ClaimAttempt(ctx context.Context, id int64, limit int) (bool, error) {
+	result, err := r.q.ExecContext(ctx, `
+		UPDATE password_resets SET attempts = attempts + 1 WHERE id = $1 AND attempts < $2
+	`, id, limit)
+	if err != nil {
+		return false, err
+	}
+
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// CountSince counts the reset codes issued to a user since the given time
+func (r *resetRepo) CountSince(ctx context.Context, userID int64, since time.Time) (int, error) {
+	var n int
+	err := r.q.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM password_resets WHERE user_id = $1 AND created_at > $2
+	`, userID, since).Scan(&n)
+	return n, err
+}
+
+// Use marks a reset code as spent if nothing has used it first
+func (r *resetRepo) Use(ctx context.Context, id int64) (bool, error) {
+	result, err := r.q.ExecContext(ctx, `
+		UPDATE password_resets SET used_at = NOW() WHERE id = $1 AND used_at IS NULL
+	`, id)
+	if err != nil {
+		return false, err
+	}
+
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// InvalidateForUser spends every outstanding reset code for a user
+func (r *resetRepo) InvalidateForUser(ctx context.Context, userID int64) error {
+	_, err := r.q.ExecContext(ctx, `
+		UPDATE password_resets SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL
+	`, userID)
+	return err
+}
@@ -0,0 +1,140 @@
+package database
+
+import (
+	"backend/model"
+	"context"
+	"database/sql"
+	"time"
+)
+
+// seasonRepo implements SeasonRepository on PostgreSQL
+type seasonRepo struct {
+	q dbtx
+}
+
+const seasonColumns = `id, club_id, name, status, rating_carryover, started_at, ended_at, started_by, ended_by`
+
+func scanSeason(row interface{ Scan(...interface{}) error }) (*model.Season, error) {
+	var s model.Season
+	err := row.Scan(&s.ID, &s.ClubID, &s.Name, &s.Status, &s.RatingCarryover,
+		&s.StartedAt, &s.EndedAt, &s.StartedBy, &s.EndedBy)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Create inserts an active season starting now
+func (r *seasonRepo) Create(ctx context.Context, s *model.Season) error {
+	s.Status = model.SeasonActive
+	return r.q.QueryRowContext(ctx, `
+		INSERT INTO seasons (club_id, name, status, rating_carryover, started_by, started_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		RETURNING id, started_at
+	`, s.ClubID, s.Name, s.Status, s.RatingCarryover, s.StartedBy).Scan(&s.ID, &s.StartedAt)
+}
+
+// Get returns a season by ID
+func (r *seasonRepo) Get(ctx context.Context, clubID, id int64) (*model.Season, error) {
+	return scanSeason(r.q.QueryRowContext(ctx, `
+		SELECT `+seasonColumns+` FROM seasons WHERE id = $1 AND ($2 = 0 OR club_id = $2)
+	`, id, clubID))
+}
+
+// Active returns the club's running season
+func (r *seasonRepo) Active(ctx context.Context, clubID int64) (*model.Season, error) {
+	return scanSeason(r.q.QueryRowContext(ctx, `
+		SELECT `+seasonColumns+` FROM seasons WHERE club_id = $1 AND status = 'active'
+	`, clubID))
+}
+
+// List returns the club's seasons, newest first
+func (r *seasonRepo) List(ctx context.Context, clubID int64) ([]model.Season, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT `+seasonColumns+` FROM seasons WHERE club_id = $1 ORDER BY started_at DESC, id DESC
+	`, clubID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seasons := []model.Season{}
+	for rows.Next() {
+		s, err := scanSeason(rows)
+		if err != nil {
+			return nil, err
+		}
+		seasons = append(seasons, *s)
+	}
+
+	return seasons, rows.Err()
+}
+
+// End marks an active season ended
+func (r *seasonRepo) End(ctx context.Context, id int64, endedBy *int64, endedAt time.Time) error {
+	result, err := r.q.ExecContext(ctx, `
+		UPDATE seasons SET status = 'ended', ended_at = $2, ended_by = $3
+		WHERE id = $1 AND status = 'active'
+	`, id, endedAt, endedBy)
+	return expectRow(result, err)
+}
+
+// SavePlayers inserts or replaces players' season snapshots
+func (r *seasonRepo) SavePlayers(ctx context.Context, players []model.SeasonStats) error {
+	for _, p := range players {
+		if _, err := r.q.ExecContext(ctx, `
+			INSERT INTO season_stats (season_id, user_id, start_rating, start_deviation, start_volatility,
+				total_matches, wins, losses, win_rate, current_streak, best_streak,
+				rating, rating_deviation, volatility)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			ON CONFLICT (season_id, user_id) DO UPDATE SET
+				start_rating = EXCLUDED.start_rating,
+				start_deviation = EXCLUDED.start_deviation,
+				start_volatility = EXCLUDED.start_volatility,
+				total_matches = EXCLUDED.total_matches,
+				wins = EXCLUDED.wins,
+				losses = EXCLUDED.losses,
+				win_rate = EXCLUDED.win_rate,
+				current_streak = EXCLUDED.current_streak,
+				best_streak = EXCLUDED.best_streak,
+				rating = EXCLUDED.rating,
+				rating_deviation = EXCLUDED.rating_deviation,
+				volatility = EXCLUDED.volatility
+		`, p.SeasonID, p.UserID, p.StartRating, p.StartDeviation, p.StartVolatility,
+			p.TotalMatches, p.Wins, p.Losses, p.WinRate, p.CurrentStreak, p.BestStreak,
+			p.Rating, p.RatingDev, p.Volatility); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Players returns a season's player snapshots
+func (r *seasonRepo) Players(ctx context.Context, seasonID int64) ([]model.SeasonStats, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT season_id, user_id, start_rating, start_deviation, start_volatility,
+		       total_matches, wins, losses, win_rate, current_streak, best_streak,
+		       rating, rating_deviation, volatility
+		FROM season_stats WHERE season_id = $1 ORDER BY user_id
+	`, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	players := []model.SeasonStats{}
+	for rows.Next() {
+		var p model.SeasonStats
+		if err := rows.Scan(&p.SeasonID, &p.UserID, &p.StartRating, &p.StartDeviation, &p.StartVolatility,
+			&p.TotalMatches, &p.Wins, &p.Losses, &p.WinRate, &p.CurrentStreak, &p.BestStreak,
+			&p.Rating, &p.RatingDev, &p.Volatility); err != nil {
+			return nil, err
+		}
+		players = append(players, p)
+	}
+
+	return players, rows.Err()
+}
@@ -0,0 +1,135 @@
+package database
+
+import (
+	"backend/model"
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+// sessionRepo implements SessionRepository on PostgreSQL
+type sessionRepo struct {
+	q dbtx
+}
+
+const sessionColumns = `
+	id, club_id, name, venue, court_ids, fee, points_to_win, point_cap, best_of,
+	status, starts_at, ends_at, opened_at, closed_at, COALESCE(created_by, 0), created_at`
+
+func scanSession(row interface{ Scan(...interface{}) error }) (*model.Session, error) {
+	var sess model.Session
+	err := row.Scan(
+		&sess.ID, &sess.ClubID, &sess.Name, &sess.Venue, pq.Array(&sess.CourtIDs), &sess.Fee,
+		&sess.Scoring.PointsToWin, &sess.Scoring.PointCap, &sess.Scoring.BestOf,
+		&sess.Status, &sess.StartsAt, &sess.EndsAt, &sess.OpenedAt, &sess.ClosedAt,
+		&sess.CreatedBy, &sess.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if sess.CourtIDs == nil {
+		sess.CourtIDs = []int64{}
+	}
+	return &sess, nil
+}
+
+// Create inserts a scheduled session
+func (r *sessionRepo) Create(ctx context.Context, sess *model.Session) error {
+	sess.Status = model.SessionScheduled
+	if sess.CourtIDs == nil {
+		sess.CourtIDs = []int64{}
+	}
+	return r.q.QueryRowContext(ctx, `
+		INSERT INTO sessions (club_id, name, venue, court_ids, fee, points_to_win, point_cap, best_of,
+		                      status, starts_at, ends_at, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW())
+		RETURNING id, created_at
+	`, sess.ClubID, sess.Name, sess.Venue, pq.Array(sess.CourtIDs), sess.Fee,
+		sess.Scoring.PointsToWin, sess.Scoring.PointCap, sess.Scoring.BestOf,
+		sess.Status, sess.StartsAt, sess.EndsAt, sess.CreatedBy,
+	).Scan(&sess.ID, &sess.CreatedAt)
+}
+
+// Get returns a session by ID
+func (r *sessionRepo) Get(ctx context.Context, clubID, id int64) (*model.Session, error) {
+	return scanSession(r.q.QueryRowContext(ctx, `
+		SELECT `+sessionColumns+` FROM sessions WHERE id = $1 AND ($2 = 0 OR club_id = $2)
+	`, id, clubID))
+}
+
+// GetForUpdate returns one of a club's sessions, locking it until the transaction ends
+func (r *sessionRepo) GetForUpdate(ctx context.Context, clubID, id int64) (*model.Session, error) {
+	return scanSession(r.q.QueryRowContext(ctx, `
+		SELECT `+sessionColumns+` FROM sessions WHERE id = $1 AND club_id = $2 FOR UPDATE
+	`, id, clubID))
+}
+
+// Current returns the club's most recently opened session that is still open
+func (r *sessionRepo) Current(ctx context.Context, clubID int64) (*model.Session, error) {
+	return scanSession(r.q.QueryRowContext(ctx, `
+		SELECT `+sessionColumns+`
+		FROM sessions WHERE club_id = $1 AND status = 'open'
+		ORDER BY opened_at DESC
+		LIMIT 1
+	`, clubID))
+}
+
+// List returns a club's sessions, latest start first
+func (r *sessionRepo) List(ctx context.Context, clubID int64, status model.SessionStatus, limit int) ([]model.Session, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT `+sessionColumns+`
+		FROM sessions
+		WHERE club_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY starts_at DESC
+		LIMIT $3
+	`, clubID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []model.Session{}
+	for rows.Next() {
+		sess, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *sess)
+	}
+
+	return sessions, rows.Err()
+}
+
+// Update stores a session's details
+func (r *sessionRepo) Update(ctx context.Context, sess *model.Session) error {
+	if sess.CourtIDs == nil {
+		sess.CourtIDs = []int64{}
+	}
+	result, err := r.q.ExecContext(ctx, `
+		UPDATE sessions SET name = $3, venue = $4, court_ids = $5, fee = $6,
+		       points_to_win = $7, point_cap = $8, best_of = $9, starts_at = $10, ends_at = $11
+		WHERE id = $1 AND club_id = $2
+	`, sess.ID, sess.ClubID, sess.Name, sess.Venue, pq.Array(sess.CourtIDs), sess.Fee,
+		sess.Scoring.PointsToWin, sess.Scoring.PointCap, sess.Scoring.BestOf, sess.StartsAt, sess.EndsAt)
+	return expectRow(result, err)
+}
+
+// Open marks a scheduled session open
+func (r *sessionRepo) Open(ctx context.Context, clubID, id int64) (*model.Session, error) {
+	return scanSession(r.q.QueryRowContext(ctx, `
+		UPDATE sessions SET status = 'open', opened_at = NOW()
+		WHERE id = $1 AND club_id = $2 AND status = 'scheduled'
+		RETURNING `+sessionColumns, id, clubID))
+}
+
+// Close marks an open session closed
+func (r *sessionRepo) Close(ctx context.Context, clubID, id int64) (*model.Session, error) {
+	return scanSession(r.q.QueryRowContext(ctx, `
+		UPDATE sessions SET status = 'closed', closed_at = NOW()
+		WHERE id = $1 AND club_id = $2 AND status = 'open'
+		RETURNING `+sessionColumns, id, clubID))
+}
@@ -0,0 +1,241 @@
+package database
+
+import (
+	"backend/model"
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+// Glicko-2 starting values for players without a rating
+const (
+	initialRating     = 1500
+	initialDeviation  = 350
+	initialVolatility = 0.06
+)
+
+// statsRepo implements StatsRepository on PostgreSQL
+type statsRepo struct {
+	q dbtx
+}
+
+const statsColumns = `
+	user_id, COALESCE(total_matches, 0), COALESCE(wins, 0), COALESCE(losses, 0), COALESCE(win_rate, 0),
+	COALESCE(current_streak, 0), COALESCE(best_streak, 0), COALESCE(skill_level, 'Beginner'), COALESCE(skill_points, 0),
+	COALESCE(rating, 1500), COALESCE(rating_deviation, 350), COALESCE(volatility, 0.06)`
+
+func scanStats(row interface{ Scan(...interface{}) error }) (*model.UserStats, error) {
+	var stats model.UserStats
+	err := row.Scan(
+		&stats.UserID, &stats.TotalMatches, &stats.Wins, &stats.Losses, &stats.WinRate,
+		&stats.CurrentStreak, &stats.BestStreak, &stats.SkillLevel, &stats.SkillPoints,
+		&stats.Rating, &stats.RatingDev, &stats.Volatility,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// newStats returns the record of a player who has not played yet
+func newStats(userID int64) *model.UserStats {
+	return &model.UserStats{
+		UserID:     userID,
+		SkillLevel: "Beginner",
+		Rating:     initialRating,
+		RatingDev:  initialDeviation,
+		Volatility: initialVolatility,
+	}
+}
+
+// Get returns a player's stats, initializing them if missing
+func (r *statsRepo) Get(ctx context.Context, userID int64) (*model.UserStats, error) {
+	stats, err := scanStats(r.q.QueryRowContext(ctx, `SELECT `+statsColumns+` FROM user_stats WHERE user_id = $1`, userID))
+	if err == sql.ErrNoRows {
+		if _, err := r.q.ExecContext(ctx, `
+			INSERT INTO user_stats (user_id, skill_level) VALUES ($1, 'Beginner') ON CONFLICT DO NOTHING
+		`, userID); err != nil {
+			return nil, err
+		}
+		return newStats(userID), nil
+	}
+	return stats, err
+}
+
+// GetMany returns stats for the players that have them
+func (r *statsRepo) GetMany(ctx context.Context, userIDs []int64) (map[int64]model.UserStats, error) {
+	rows, err := r.q.QueryContext(ctx, `SELECT `+statsColumns+` FROM user_stats WHERE user_id = ANY($1)`, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make(map[int64]model.UserStats, len(userIDs))
+	for rows.Next() {
+		s, err := scanStats(rows)
+		if err != nil {
+			return nil, err
+		}
+		stats[s.UserID] = *s
+	}
+
+	return stats, rows.Err()
+}
+
+// SaveRecord stores a player's match record; the rating columns are left alone
+func (r *statsRepo) SaveRecord(ctx context.Context, stats *model.UserStats) error {
+	_, err := r.q.ExecContext(ctx, `
+		INSERT INTO user_stats (user_id, total_matches, wins, losses, win_rate,
+			current_streak, best_streak, skill_level, skill_points, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET
+			total_matches = EXCLUDED.total_matches, wins = EXCLUDED.wins, losses = EXCLUDED.losses,
+			win_rate = EXCLUDED.win_rate, current_streak = EXCLUDED.current_streak,
+			best_streak = EXCLUDED.best_streak, skill_level = EXCLUDED.skill_level,
+			skill_points = EXCLUDED.skill_points, updated_at = NOW()
+	`, stats.UserID, stats.TotalMatches, stats.Wins, stats.Losses, stats.WinRate,
+		stats.CurrentStreak, stats.BestStreak, stats.SkillLevel, stats.SkillPoints)
+	return err
+}
+
+// SaveRating stores a player's new rating and records the change
+func (r *statsRepo) SaveRating(ctx context.Context, change model.RatingChange, volatility float64) error {
+	if err := r.SetRating(ctx, change.UserID, change.RatingAfter, change.DeviationAfter, volatility); err != nil {
+		return err
+	}
+
+	_, err := r.q.ExecContext(ctx, `
+		INSERT INTO rating_history (match_id, user_id, rating_before, rating_after, deviation_before, deviation_after, volatility, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8, NOW()))
+	`, change.MatchID, change.UserID, change.RatingBefore, change.RatingAfter,
+		change.DeviationBefore, change.DeviationAfter, volatility, timeArg(change.CreatedAt))
+
+	return err
+}
+
+// RatingHistory returns a player's rating changes, most recent first
+func (r *statsRepo) RatingHistory(ctx context.Context, userID int64, limit int) ([]model.RatingChange, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT `+ratingChangeColumns+`
+		FROM rating_history WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []model.RatingChange{}
+	for rows.Next() {
+		c, err := scanRatingChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, *c)
+	}
+
+	return history, rows.Err()
+}
+
+const ratingChangeColumns = `
+	match_id, user_id, rating_before, rating_after, deviation_before, deviation_after, volatility, created_at`
+
+func scanRatingChange(row interface{ Scan(...interface{}) error }) (*model.RatingChange, error) {
+	var c model.RatingChange
+	if err := row.Scan(&c.MatchID, &c.UserID, &c.RatingBefore, &c.RatingAfter,
+		&c.DeviationBefore, &c.DeviationAfter, &c.Volatility, &c.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// LatestRatings returns each player's most recent rating change
+func (r *statsRepo) LatestRatings(ctx context.Context, userIDs []int64) (map[int64]model.RatingChange, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT DISTINCT ON (user_id) `+ratingChangeColumns+`
+		FROM rating_history WHERE user_id = ANY($1)
+		ORDER BY user_id, created_at DESC, id DESC
+	`, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	latest := make(map[int64]model.RatingChange, len(userIDs))
+	for rows.Next() {
+		c, err := scanRatingChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		latest[c.UserID] = *c
+	}
+
+	return latest, rows.Err()
+}
+
+// RatingChanges returns every player's rating change from the given matches
+func (r *statsRepo) RatingChanges(ctx context.Context, matchIDs []int64) ([]model.RatingChange, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT `+ratingChangeColumns+`
+		FROM rating_history WHERE match_id = ANY($1)
+		ORDER BY created_at, id
+	`, pq.Array(matchIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []model.RatingChange{}
+	for rows.Next() {
+		c, err := scanRatingChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, *c)
+	}
+
+	return changes, rows.Err()
+}
+
+// ClearRatingHistory drops all of a player's rating changes
+func (r *statsRepo) ClearRatingHistory(ctx context.Context, userID int64) error {
+	_, err := r.q.ExecContext(ctx, `DELETE FROM rating_history WHERE user_id = $1`, userID)
+	return err
+}
+
+// Reset returns every player to an unplayed record and rating and drops all rating history
+func (r *statsRepo) Reset(ctx context.Context) error {
+	if _, err := r.q.ExecContext(ctx, `DELETE FROM rating_history`); err != nil {
+		return err
+	}
+
+	_, err := r.q.ExecContext(ctx, `
+		UPDATE user_stats SET
+			total_matches = 0, wins = 0, losses = 0, win_rate = 0,
+			current_streak = 0, best_streak = 0, skill_level = 'Beginner', skill_points = 0,
+			rating = $1, rating_deviation = $2, volatility = $3, updated_at = NOW()
+	`, initialRating, initialDeviation, initialVolatility)
+	return err
+}
+
+// DeleteRatingChanges drops the rating history of the given matches
+func (r *statsRepo) DeleteRatingChanges(ctx context.Context, matchIDs []int64) error {
+	_, err := r.q.ExecContext(ctx, `DELETE FROM rating_history WHERE match_id = ANY($1)`, pq.Array(matchIDs))
+	return err
+}
+
+// SetRating stores a player's rating without recording a change
+func (r *statsRepo) SetRating(ctx context.Context, userID int64, rating, deviation, volatility float64) error {
+	_, err := r.q.ExecContext(ctx, `
+		INSERT INTO user_stats (user_id, skill_level, rating, rating_deviation, volatility, updated_at)
+		VALUES ($1, 'Beginner', $2, $3, $4, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET
+			rating = EXCLUDED.rating,
+			rating_deviation = EXCLUDED.rating_deviation,
+			volatility = EXCLUDED.volatility,
+			updated_at = NOW()
+	`, userID, rating, deviation, volatility)
+	return err
+}
@@ -3,93 +3,123 @@ package database
 import (
 	"backend/model"
 	"context"
-	"time"
+	"database/sql"
 )
 
-// TokenRepository handles refresh token database operations
-type TokenRepository struct{}
-
-// NewTokenRepository creates a new token repository
-func NewTokenRepository() *TokenRepository {
-	return &TokenRepository{}
+// tokenRepo implements TokenRepository on PostgreSQL
+type tokenRepo struct {
+	q dbtx
 }
 
-// Create stores a new refresh token
-func (r *TokenRepository) Create(ctx context.Context, token *model.RefreshToken) error {
-	query := `
-		INSERT INTO refresh_tokens (user_id, token, expires_at, created_at)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id
-	`
-
-	return DB.QueryRow(ctx, query,
-		token.UserID,
-		token.Token,
-		token.ExpiresAt,
-		time.Now(),
-	).Scan(&token.ID)
+const tokenColumns = `
+	id, user_id, token_hash, family_id, user_agent, ip, signed_in_at,
+	expires_at, created_at, rotated_at, revoked_at`
+
+func scanToken(row interface{ Scan(...interface{}) error }) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := row.Scan(&token.ID, &token.UserID, &token.TokenHash, &token.FamilyID,
+		&token.UserAgent, &token.IP, &token.SignedInAt,
+		&token.ExpiresAt, &token.CreatedAt, &token.RotatedAt, &token.RevokedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
 }
 
-// GetByToken finds a refresh token by its value
-func (r *TokenRepository) GetByToken(ctx context.Context, tokenValue string) (*model.RefreshToken, error) {
-	query := `
-		SELECT id, user_id, token, expires_at, created_at, revoked_at
-		FROM refresh_tokens
-		WHERE token = $1 AND revoked_at IS NULL
-	`
+// Create stores a new refresh token. A zero SignedInAt starts a new sign-in now.
+func (r *tokenRepo) Create(ctx context.Context, token *model.RefreshToken) error {
+	var signedInAt interface{}
+	if !token.SignedInAt.IsZero() {
+		signedInAt = token.SignedInAt
+	}
+	return r.q.QueryRowContext(ctx, `
+		INSERT INTO refresh_tokens (user_id, token_hash, family_id, user_agent, ip, signed_in_at, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, COALESCE($6, NOW()), $7, NOW())
+		RETURNING id, signed_in_at, created_at
+	`, token.UserID, token.TokenHash, token.FamilyID, token.UserAgent, token.IP, signedInAt, token.ExpiresAt,
+	).Scan(&token.ID, &token.SignedInAt, &token.CreatedAt)
+}
 
-	token := &model.RefreshToken{}
-	err := DB.QueryRow(ctx, query, tokenValue).Scan(
-		&token.ID,
-		&token.UserID,
-		&token.Token,
-		&token.ExpiresAt,
-		&token.CreatedAt,
-		&token.RevokedAt,
-	)
+// GetByHash finds a refresh token by its hash, rotated, revoked or not
+func (r *tokenRepo) GetByHash(ctx context.Context, hash string) (*model.RefreshToken, error) {
+	return scanToken(r.q.QueryRowContext(ctx, `
+		SELECT `+tokenColumns+` FROM refresh_tokens WHERE token_hash = $1
+	`, hash))
+}
 
+// Active returns a user's usable tokens, most recently issued first
+func (r *tokenRepo) Active(ctx context.Context, userID int64) ([]model.RefreshToken, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT `+tokenColumns+` FROM refresh_tokens
+		WHERE user_id = $1 AND rotated_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC, id DESC
+	`, userID)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+
+	tokens := []model.RefreshToken{}
+	for rows.Next() {
+		token, err := scanToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
 
-	return token, nil
+	return tokens, rows.Err()
 }
 
-// Revoke invalidates a refresh token
-func (r *TokenRepository) Revoke(ctx context.Context, tokenValue string) error {
-	query := `
-		UPDATE refresh_tokens
-		SET revoked_at = NOW()
-		WHERE token = $1
-	`
+// Rotate marks a refresh token as replaced if nothing else has used or revoked it first
+func (r *tokenRepo) Rotate(ctx context.Context, id int64) (bool, error) {
+	result, err := r.q.ExecContext(ctx, `
+		UPDATE refresh_tokens SET rotated_at = NOW()
+		WHERE id = $1 AND rotated_at IS NULL AND revoked_at IS NULL
+	`, id)
+	if err != nil {
+		return false, err
+	}
+
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
 
-	_, err := DB.Exec(ctx, query, tokenValue)
+// RevokeFamily invalidates all refresh tokens from one sign-in
+func (r *tokenRepo) RevokeFamily(ctx context.Context, familyID string) error {
+	_, err := r.q.ExecContext(ctx, `
+		UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL
+	`, familyID)
 	return err
 }
 
 // RevokeAllForUser invalidates all refresh tokens for a user
-func (r *TokenRepository) RevokeAllForUser(ctx context.Context, userID int64) error {
-	query := `
-		UPDATE refresh_tokens
-		SET revoked_at = NOW()
-		WHERE user_id = $1 AND revoked_at IS NULL
-	`
-
-	_, err := DB.Exec(ctx, query, userID)
+func (r *tokenRepo) RevokeAllForUser(ctx context.Context, userID int64) error {
+	_, err := r.q.ExecContext(ctx, `
+		UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL
+	`, userID)
 	return err
 }
 
-// CleanupExpired removes expired tokens
-func (r *TokenRepository) CleanupExpired(ctx context.Context) (int64, error) {
-	query := `
-		DELETE FROM refresh_tokens
-		WHERE expires_at < NOW() OR revoked_at IS NOT NULL
-	`
+// RevokeOthers invalidates a user's refresh tokens from every other sign-in
+func (r *tokenRepo) RevokeOthers(ctx context.Context, userID int64, familyID string) error {
+	_, err := r.q.ExecContext(ctx, `
+		UPDATE refresh_tokens SET revoked_at = NOW()
+		WHERE user_id = $1 AND family_id != $2 AND revoked_at IS NULL
+	`, userID, familyID)
+	return err
+}
 
-	result, err := DB.Exec(ctx, query)
+// DeleteExpired removes expired tokens
+func (r *tokenRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := r.q.ExecContext(ctx, `
+		DELETE FROM refresh_tokens WHERE expires_at < NOW()
+	`)
 	if err != nil {
 		return 0, err
 	}
-
-	return result.RowsAffected(), nil
+	return result.RowsAffected()
 }
@@ -0,0 +1,279 @@
+package database
+
+import (
+	"backend/model"
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+// tournamentRepo implements TournamentRepository on PostgreSQL
+type tournamentRepo struct {
+	q dbtx
+}
+
+const tournamentColumns = `
+	id, club_id, name, format, entry_type, seeding, points_to_win, point_cap, best_of,
+	status, group_count, courts, starts_at, slot_minutes, winner_entry_id, created_by, created_at, started_at, completed_at`
+
+func scanTournament(row interface{ Scan(...interface{}) error }) (*model.Tournament, error) {
+	var t model.Tournament
+	err := row.Scan(&t.ID, &t.ClubID, &t.Name, &t.Format, &t.EntryType, &t.Seeding,
+		&t.Scoring.PointsToWin, &t.Scoring.PointCap, &t.Scoring.BestOf,
+		&t.Status, &t.Groups, pq.Array(&t.Courts), &t.StartsAt, &t.SlotMinutes, &t.WinnerEntryID, &t.CreatedBy, &t.CreatedAt, &t.StartedAt, &t.CompletedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+const bracketMatchColumns = `
+	id, tournament_id, bracket, round, position, entry1_id, entry2_id, winner_entry_id, loser_entry_id,
+	match_id, next_match_id, next_slot, loser_next_match_id, loser_next_slot, status`
+
+const fixtureColumns = `
+	id, tournament_id, group_number, round, slot, court, scheduled_at, entry1_id, entry2_id, match_id, status`
+
+func scanFixture(row interface{ Scan(...interface{}) error }) (*model.Fixture, error) {
+	var f model.Fixture
+	err := row.Scan(&f.ID, &f.TournamentID, &f.Group, &f.Round, &f.Slot, &f.Court, &f.ScheduledAt,
+		&f.Entry1ID, &f.Entry2ID, &f.MatchID, &f.Status)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func scanBracketMatch(row interface{ Scan(...interface{}) error }) (*model.BracketMatch, error) {
+	var m model.BracketMatch
+	err := row.Scan(&m.ID, &m.TournamentID, &m.Bracket, &m.Round, &m.Position,
+		&m.Entry1ID, &m.Entry2ID, &m.WinnerEntryID, &m.LoserEntryID,
+		&m.MatchID, &m.NextMatchID, &m.NextSlot, &m.LoserNextMatchID, &m.LoserNextSlot, &m.Status)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Create inserts a tournament open for registration
+func (r *tournamentRepo) Create(ctx context.Context, t *model.Tournament) error {
+	t.Status = model.TournamentRegistration
+	return r.q.QueryRowContext(ctx, `
+		INSERT INTO tournaments (club_id, name, format, entry_type, seeding, points_to_win, point_cap, best_of,
+			status, group_count, courts, starts_at, slot_minutes, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NOW())
+		RETURNING id, created_at
+	`, t.ClubID, t.Name, t.Format, t.EntryType, t.Seeding,
+		t.Scoring.PointsToWin, t.Scoring.PointCap, t.Scoring.BestOf,
+		t.Status, t.Groups, pq.Array(t.Courts), t.StartsAt, t.SlotMinutes, t.CreatedBy).Scan(&t.ID, &t.CreatedAt)
+}
+
+// Get returns one of a club's tournaments
+func (r *tournamentRepo) Get(ctx context.Context, clubID, id int64) (*model.Tournament, error) {
+	return scanTournament(r.q.QueryRowContext(ctx, `
+		SELECT `+tournamentColumns+` FROM tournaments WHERE id = $1 AND club_id = $2
+	`, id, clubID))
+}
+
+// GetForUpdate returns one of a club's tournaments and locks it so bracket changes serialize
+func (r *tournamentRepo) GetForUpdate(ctx context.Context, clubID, id int64) (*model.Tournament, error) {
+	return scanTournament(r.q.QueryRowContext(ctx, `
+		SELECT `+tournamentColumns+` FROM tournaments WHERE id = $1 AND club_id = $2
+		FOR UPDATE
+	`, id, clubID))
+}
+
+// List returns the club's tournaments, newest first
+func (r *tournamentRepo) List(ctx context.Context, clubID int64) ([]model.Tournament, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT `+tournamentColumns+` FROM tournaments WHERE club_id = $1 ORDER BY created_at DESC, id DESC
+	`, clubID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tournaments := []model.Tournament{}
+	for rows.Next() {
+		t, err := scanTournament(rows)
+		if err != nil {
+			return nil, err
+		}
+		tournaments = append(tournaments, *t)
+	}
+
+	return tournaments, rows.Err()
+}
+
+// Update stores a tournament's status, winner and start and completion times
+func (r *tournamentRepo) Update(ctx context.Context, t *model.Tournament) error {
+	result, err := r.q.ExecContext(ctx, `
+		UPDATE tournaments SET status = $2, winner_entry_id = $3, started_at = $4, completed_at = $5
+		WHERE id = $1
+	`, t.ID, t.Status, t.WinnerEntryID, t.StartedAt, t.CompletedAt)
+	return expectRow(result, err)
+}
+
+// AddEntry registers a player or pair
+func (r *tournamentRepo) AddEntry(ctx context.Context, e *model.TournamentEntry) error {
+	return r.q.QueryRowContext(ctx, `
+		INSERT INTO tournament_entries (tournament_id, players, created_at)
+		VALUES ($1, $2, NOW())
+		RETURNING id, created_at
+	`, e.TournamentID, pq.Array(e.Players)).Scan(&e.ID, &e.CreatedAt)
+}
+
+// RemoveEntry withdraws an entry from a tournament
+func (r *tournamentRepo) RemoveEntry(ctx context.Context, tournamentID, entryID int64) error {
+	result, err := r.q.ExecContext(ctx, `
+		DELETE FROM tournament_entries WHERE id = $1 AND tournament_id = $2
+	`, entryID, tournamentID)
+	return expectRow(result, err)
+}
+
+// Entries returns a tournament's entries in registration order
+func (r *tournamentRepo) Entries(ctx context.Context, tournamentID int64) ([]model.TournamentEntry, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT id, tournament_id, players, seed, strength, group_number, created_at
+		FROM tournament_entries WHERE tournament_id = $1 ORDER BY id
+	`, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []model.TournamentEntry{}
+	for rows.Next() {
+		var e model.TournamentEntry
+		if err := rows.Scan(&e.ID, &e.TournamentID, pq.Array(&e.Players), &e.Seed, &e.Strength, &e.Group, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// SeedEntries stores each entry's seed, strength and group
+func (r *tournamentRepo) SeedEntries(ctx context.Context, entries []model.TournamentEntry) error {
+	for _, e := range entries {
+		if _, err := r.q.ExecContext(ctx, `
+			UPDATE tournament_entries SET seed = $2, strength = $3, group_number = $4 WHERE id = $1
+		`, e.ID, e.Seed, e.Strength, e.Group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddBracketMatch inserts a bracket match
+func (r *tournamentRepo) AddBracketMatch(ctx context.Context, m *model.BracketMatch) error {
+	return r.q.QueryRowContext(ctx, `
+		INSERT INTO bracket_matches (tournament_id, bracket, round, position, entry1_id, entry2_id,
+			next_match_id, next_slot, loser_next_match_id, loser_next_slot, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`, m.TournamentID, m.Bracket, m.Round, m.Position, m.Entry1ID, m.Entry2ID,
+		m.NextMatchID, m.NextSlot, m.LoserNextMatchID, m.LoserNextSlot, m.Status).Scan(&m.ID)
+}
+
+// UpdateBracketMatch stores a bracket match's entries, result, match and status
+func (r *tournamentRepo) UpdateBracketMatch(ctx context.Context, m model.BracketMatch) error {
+	result, err := r.q.ExecContext(ctx, `
+		UPDATE bracket_matches
+		SET entry1_id = $2, entry2_id = $3, winner_entry_id = $4, loser_entry_id = $5, match_id = $6, status = $7
+		WHERE id = $1
+	`, m.ID, m.Entry1ID, m.Entry2ID, m.WinnerEntryID, m.LoserEntryID, m.MatchID, m.Status)
+	return expectRow(result, err)
+}
+
+// BracketMatches returns a tournament's bracket: winners, losers, then final rounds
+func (r *tournamentRepo) BracketMatches(ctx context.Context, tournamentID int64) ([]model.BracketMatch, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT `+bracketMatchColumns+` FROM bracket_matches
+		WHERE tournament_id = $1
+		ORDER BY CASE bracket WHEN 'winners' THEN 0 WHEN 'losers' THEN 1 ELSE 2 END, round, position
+	`, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := []model.BracketMatch{}
+	for rows.Next() {
+		m, err := scanBracketMatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, *m)
+	}
+
+	return matches, rows.Err()
+}
+
+// BracketMatchFor returns the bracket match played as the given match
+func (r *tournamentRepo) BracketMatchFor(ctx context.Context, matchID int64) (*model.BracketMatch, error) {
+	return scanBracketMatch(r.q.QueryRowContext(ctx, `
+		SELECT `+bracketMatchColumns+` FROM bracket_matches WHERE match_id = $1
+	`, matchID))
+}
+
+// AddFixture inserts a round-robin fixture
+func (r *tournamentRepo) AddFixture(ctx context.Context, f *model.Fixture) error {
+	return r.q.QueryRowContext(ctx, `
+		INSERT INTO tournament_fixtures (tournament_id, group_number, round, slot, court, scheduled_at,
+			entry1_id, entry2_id, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`, f.TournamentID, f.Group, f.Round, f.Slot, f.Court, f.ScheduledAt,
+		f.Entry1ID, f.Entry2ID, f.Status).Scan(&f.ID)
+}
+
+// UpdateFixture stores a fixture's match and status
+func (r *tournamentRepo) UpdateFixture(ctx context.Context, f model.Fixture) error {
+	result, err := r.q.ExecContext(ctx, `
+		UPDATE tournament_fixtures SET match_id = $2, status = $3 WHERE id = $1
+	`, f.ID, f.MatchID, f.Status)
+	return expectRow(result, err)
+}
+
+// Fixtures returns a tournament's fixtures by slot, then court
+func (r *tournamentRepo) Fixtures(ctx context.Context, tournamentID int64) ([]model.Fixture, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT `+fixtureColumns+` FROM tournament_fixtures
+		WHERE tournament_id = $1
+		ORDER BY slot, court
+	`, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fixtures := []model.Fixture{}
+	for rows.Next() {
+		f, err := scanFixture(rows)
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, *f)
+	}
+
+	return fixtures, rows.Err()
+}
+
+// FixtureFor returns the fixture played as the given match
+func (r *tournamentRepo) FixtureFor(ctx context.Context, matchID int64) (*model.Fixture, error) {
+	return scanFixture(r.q.QueryRowContext(ctx, `
+		SELECT `+fixtureColumns+` FROM tournament_fixtures WHERE match_id = $1
+	`, matchID))
+}
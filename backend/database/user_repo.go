@@ -3,196 +3,309 @@ package database
 import (
 	"backend/model"
 	"context"
-	"errors"
+	"database/sql"
 	"time"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
 )
 
-// UserRepository handles user database operations
-type UserRepository struct{}
-
-// NewUserRepository creates a new user repository
-func NewUserRepository() *UserRepository {
-	return &UserRepository{}
-}
-
-// Create inserts a new user into the database
-func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
-	query := `
-		INSERT INTO users (username, password_hash, name, bio, role, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id
-	`
-
-	now := time.Now()
-	err := DB.QueryRow(ctx, query,
-		user.Username,
-		user.PasswordHash,
-		user.Name,
-		user.Bio,
-		user.Role,
-		now,
-		now,
-	).Scan(&user.ID)
+// userRepo implements UserRepository on PostgreSQL
+type userRepo struct {
+	q dbtx
+}
+
+const userColumns = `
+	id, username, password_hash, name, COALESCE(phone, ''), COALESCE(bio, ''), role,
+	COALESCE(hand_preference, 'right'), COALESCE(skill_tier, 'N'),
+	COALESCE(is_active, true), last_login_at, failed_logins, last_failed_login_at, locked_until,
+	created_at, updated_at`
 
+func scanUser(row interface{ Scan(...interface{}) error }) (*model.User, error) {
+	var user model.User
+	err := row.Scan(
+		&user.ID, &user.Username, &user.PasswordHash, &user.Name, &user.Phone, &user.Bio, &user.Role,
+		&user.HandPreference, &user.SkillTier, &user.IsActive, &user.LastLoginAt,
+		&user.FailedLogins, &user.LastFailedAt, &user.LockedUntil, &user.CreatedAt, &user.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return &user, nil
+}
 
-	user.CreatedAt = now
-	user.UpdatedAt = now
+// Create inserts a new user with empty stats and default club membership
+func (r *userRepo) Create(ctx context.Context, user *model.User) error {
+	err := r.q.QueryRowContext(ctx, `
+		INSERT INTO users (username, password_hash, name, phone, bio, role, hand_preference, skill_tier, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`, user.Username, user.PasswordHash, user.Name, user.Phone, user.Bio, user.Role,
+		user.HandPreference, user.SkillTier, user.IsActive).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return err
+	}
 
-	// Initialize user stats
-	statsQuery := `
-		INSERT INTO user_stats (user_id, skill_level)
-		VALUES ($1, 'Beginner')
+	if _, err := r.q.ExecContext(ctx, `
+		INSERT INTO user_stats (user_id, skill_level, skill_points)
+		VALUES ($1, 'Beginner', 0)
 		ON CONFLICT (user_id) DO NOTHING
-	`
-	_, _ = DB.Exec(ctx, statsQuery, user.ID)
+	`, user.ID); err != nil {
+		return err
+	}
 
-	return nil
+	// New users start in the default club; other clubs add them as members
+	_, err = r.q.ExecContext(ctx, `
+		INSERT INTO club_members (club_id, user_id, role, joined_at)
+		SELECT id, $1, $2, NOW() FROM clubs WHERE slug = $3
+		ON CONFLICT (club_id, user_id) DO NOTHING
+	`, user.ID, user.Role, model.DefaultClubSlug)
+
+	return err
+}
+
+// GetByID finds a user by ID
+func (r *userRepo) GetByID(ctx context.Context, id int64) (*model.User, error) {
+	return scanUser(r.q.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1`, id))
+}
+
+// GetForUpdate returns a user, locking their row until the transaction ends
+func (r *userRepo) GetForUpdate(ctx context.Context, id int64) (*model.User, error) {
+	return scanUser(r.q.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1 FOR UPDATE`, id))
+}
+
+// GetByLogin finds a user by username or phone number
+func (r *userRepo) GetByLogin(ctx context.Context, login string) (*model.User, error) {
+	return scanUser(r.q.QueryRowContext(ctx, `
+		SELECT `+userColumns+` FROM users WHERE username = $1 OR phone = $1
+		ORDER BY username = $1 DESC
+		LIMIT 1
+	`, login))
 }
 
 // GetByUsername finds a user by username
-func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*model.User, error) {
-	query := `
-		SELECT id, username, password_hash, name, bio, role, created_at, updated_at
-		FROM users
-		WHERE username = $1
-	`
-
-	user := &model.User{}
-	err := DB.QueryRow(ctx, query, username).Scan(
-		&user.ID,
-		&user.Username,
-		&user.PasswordHash,
-		&user.Name,
-		&user.Bio,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+func (r *userRepo) GetByUsername(ctx context.Context, username string) (*model.User, error) {
+	return scanUser(r.q.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE username = $1`, username))
+}
 
-	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, nil
-	}
+// GetMany returns the users with the given IDs in no particular order
+func (r *userRepo) GetMany(ctx context.Context, ids []int64) ([]model.User, error) {
+	rows, err := r.q.QueryContext(ctx, `SELECT `+userColumns+` FROM users WHERE id = ANY($1)`, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
 
-	return user, nil
+	return users, rows.Err()
 }
 
-// GetByID finds a user by ID
-func (r *UserRepository) GetByID(ctx context.Context, id int64) (*model.User, error) {
-	query := `
-		SELECT id, username, password_hash, name, bio, role, created_at, updated_at
-		FROM users
+// UsernameExists reports whether the username is taken
+func (r *userRepo) UsernameExists(ctx context.Context, username string) (bool, error) {
+	var exists bool
+	err := r.q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE username = $1)`, username).Scan(&exists)
+	return exists, err
+}
+
+// PhoneExists reports whether the phone number is registered
+func (r *userRepo) PhoneExists(ctx context.Context, phone string) (bool, error) {
+	var exists bool
+	err := r.q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE phone = $1)`, phone).Scan(&exists)
+	return exists, err
+}
+
+// UpdateProfile changes name and bio, and phone when one is given
+func (r *userRepo) UpdateProfile(ctx context.Context, id int64, req model.UpdateProfileRequest) (*model.User, error) {
+	return scanUser(r.q.QueryRowContext(ctx, `
+		UPDATE users
+		SET name = $2, bio = $3, phone = COALESCE(NULLIF($4, ''), phone), updated_at = NOW()
 		WHERE id = $1
-	`
-
-	user := &model.User{}
-	err := DB.QueryRow(ctx, query, id).Scan(
-		&user.ID,
-		&user.Username,
-		&user.PasswordHash,
-		&user.Name,
-		&user.Bio,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+		RETURNING `+userColumns, id, req.Name, req.Bio, req.Phone))
+}
 
-	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, err
-	}
+// UpdatePassword stores a new password hash
+func (r *userRepo) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	result, err := r.q.ExecContext(ctx, `
+		UPDATE users SET password_hash = $2, updated_at = NOW() WHERE id = $1
+	`, id, passwordHash)
+	return expectRow(result, err)
+}
 
-	return user, nil
+// UpdatePlayer sets a club member's hand preference and skill tier
+func (r *userRepo) UpdatePlayer(ctx context.Context, clubID, id int64, hand model.HandPreference, tier model.SkillTier) error {
+	result, err := r.q.ExecContext(ctx, `
+		UPDATE users SET hand_preference = $2, skill_tier = $3, updated_at = NOW()
+		WHERE id = $1 AND EXISTS (SELECT 1 FROM club_members WHERE club_id = $4 AND user_id = $1)
+	`, id, hand, tier, clubID)
+	return expectRow(result, err)
 }
 
-// Update updates a user's profile
-func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
-	query := `
+// SetRole changes a user's account role
+func (r *userRepo) SetRole(ctx context.Context, id int64, role model.Role) error {
+	result, err := r.q.ExecContext(ctx, `
+		UPDATE users SET role = $2, updated_at = NOW() WHERE id = $1
+	`, id, role)
+	return expectRow(result, err)
+}
+
+// SetActive enables or disables logins for the account
+func (r *userRepo) SetActive(ctx context.Context, id int64, active bool) error {
+	result, err := r.q.ExecContext(ctx, `
+		UPDATE users SET is_active = $2, updated_at = NOW() WHERE id = $1
+	`, id, active)
+	return expectRow(result, err)
+}
+
+// Anonymize scrubs personal details and disables the account
+func (r *userRepo) Anonymize(ctx context.Context, id int64, username, passwordHash string) error {
+	result, err := r.q.ExecContext(ctx, `
 		UPDATE users
-		SET name = $2, bio = $3, updated_at = $4
+		SET username = $2, password_hash = $3, name = 'Deleted player', phone = '', bio = '', avatar_url = '', role = 'player',
+		    is_active = false, failed_logins = 0, last_failed_login_at = NULL, locked_until = NULL,
+		    last_login_at = NULL, anonymized_at = NOW(), updated_at = NOW()
 		WHERE id = $1
-	`
+	`, id, username, passwordHash)
+	if err := expectRow(result, err); err != nil {
+		return err
+	}
 
-	user.UpdatedAt = time.Now()
-	_, err := DB.Exec(ctx, query, user.ID, user.Name, user.Bio, user.UpdatedAt)
-	return err
+	for _, query := range []string{
+		`DELETE FROM login_events WHERE user_id = $1`,
+		`DELETE FROM password_resets WHERE user_id = $1`,
+		`DELETE FROM refresh_tokens WHERE user_id = $1`,
+		`DELETE FROM club_members WHERE user_id = $1`,
+		`DELETE FROM club_invites WHERE user_id = $1`,
+	} {
+		if _, err := r.q.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-// UsernameExists checks if a username is already taken
-func (r *UserRepository) UsernameExists(ctx context.Context, username string) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)`
+// Delete removes the account; stats, memberships, tokens and queue entries cascade
+func (r *userRepo) Delete(ctx context.Context, id int64) error {
+	result, err := r.q.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id)
+	return expectRow(result, err)
+}
 
-	var exists bool
-	err := DB.QueryRow(ctx, query, username).Scan(&exists)
-	return exists, err
+// CountActiveAdmins returns how many enabled accounts have the admin role
+func (r *userRepo) CountActiveAdmins(ctx context.Context) (int, error) {
+	var count int
+	err := r.q.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM users WHERE role = 'admin' AND COALESCE(is_active, true)
+	`).Scan(&count)
+	return count, err
 }
 
-// GetStats returns a user's statistics
-func (r *UserRepository) GetStats(ctx context.Context, userID int64) (*model.UserStats, error) {
-	query := `
-		SELECT user_id, total_matches, wins, losses, win_rate, current_streak, skill_level
-		FROM user_stats
-		WHERE user_id = $1
-	`
-
-	stats := &model.UserStats{}
-	err := DB.QueryRow(ctx, query, userID).Scan(
-		&stats.UserID,
-		&stats.TotalMatches,
-		&stats.Wins,
-		&stats.Losses,
-		&stats.WinRate,
-		&stats.CurrentStreak,
-		&stats.SkillLevel,
-	)
+// RecordLogin stamps the user's last login time and clears failed attempts
+func (r *userRepo) RecordLogin(ctx context.Context, id int64) error {
+	_, err := r.q.ExecContext(ctx, `
+		UPDATE users SET last_login_at = NOW(), failed_logins = 0, locked_until = NULL WHERE id = $1
+	`, id)
+	return err
+}
 
-	if errors.Is(err, pgx.ErrNoRows) {
-		// Return default stats
-		return &model.UserStats{
-			UserID:     userID,
-			SkillLevel: "Beginner",
-		}, nil
+// RecordFailedLogin counts a wrong password against the account
+func (r *userRepo) RecordFailedLogin(ctx context.Context, id int64) (int, error) {
+	var failures int
+	err := r.q.QueryRowContext(ctx, `
+		UPDATE users SET failed_logins = failed_logins + 1, last_failed_login_at = NOW()
+		WHERE id = $1
+		RETURNING failed_logins
+	`, id).Scan(&failures)
+	if err == sql.ErrNoRows {
+		return 0, ErrNotFound
 	}
+	return failures, err
+}
+
+// Lock blocks logins to the account until the given time
+func (r *userRepo) Lock(ctx context.Context, id int64, until time.Time) error {
+	result, err := r.q.ExecContext(ctx, `UPDATE users SET locked_until = $2 WHERE id = $1`, id, until)
+	return expectRow(result, err)
+}
+
+// Unlock clears failed attempts and any lockout
+func (r *userRepo) Unlock(ctx context.Context, id int64) error {
+	result, err := r.q.ExecContext(ctx, `
+		UPDATE users SET failed_logins = 0, locked_until = NULL WHERE id = $1
+	`, id)
+	return expectRow(result, err)
+}
+
+// ListByClub returns a club's members with their club role and stats, by name
+func (r *userRepo) ListByClub(ctx context.Context, clubID int64) ([]model.UserListItem, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT u.id, u.username, u.name, COALESCE(u.phone, ''), cm.role,
+		       COALESCE(u.hand_preference, 'right'), COALESCE(u.skill_tier, 'N'),
+		       COALESCE(u.is_active, true), CASE WHEN u.locked_until > NOW() THEN u.locked_until END,
+		       COALESCE(us.skill_level, 'Beginner'), COALESCE(us.win_rate, 0),
+		       COALESCE(us.total_matches, 0), COALESCE(us.wins, 0)
+		FROM users u
+		JOIN club_members cm ON cm.user_id = u.id AND cm.club_id = $1
+		LEFT JOIN user_stats us ON u.id = us.user_id
+		ORDER BY u.name ASC
+	`, clubID)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return stats, nil
-}
-
-// UpdateStats updates a user's statistics
-func (r *UserRepository) UpdateStats(ctx context.Context, stats *model.UserStats) error {
-	query := `
-		INSERT INTO user_stats (user_id, total_matches, wins, losses, win_rate, current_streak, skill_level, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
-		ON CONFLICT (user_id) DO UPDATE SET
-			total_matches = $2,
-			wins = $3,
-			losses = $4,
-			win_rate = $5,
-			current_streak = $6,
-			skill_level = $7,
-			updated_at = NOW()
-	`
-
-	_, err := DB.Exec(ctx, query,
-		stats.UserID,
-		stats.TotalMatches,
-		stats.Wins,
-		stats.Losses,
-		stats.WinRate,
-		stats.CurrentStreak,
-		stats.SkillLevel,
-	)
+	var users []model.UserListItem
+	for rows.Next() {
+		var u model.UserListItem
+		if err := rows.Scan(&u.ID, &u.Username, &u.Name, &u.Phone, &u.Role,
+			&u.HandPreference, &u.SkillTier, &u.IsActive, &u.LockedUntil, &u.SkillLevel, &u.WinRate,
+			&u.TotalMatches, &u.Wins); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
 
-	return err
+	return users, rows.Err()
+}
+
+// AllInClub reports whether every user belongs to the club
+func (r *userRepo) AllInClub(ctx context.Context, clubID int64, ids []int64) (bool, error) {
+	var count int
+	err := r.q.QueryRowContext(ctx, `
+		SELECT COUNT(DISTINCT user_id) FROM club_members WHERE club_id = $1 AND user_id = ANY($2)
+	`, clubID, pq.Array(ids)).Scan(&count)
+	return count == len(uniqueIDs(ids)), err
+}
+
+// expectRow turns an update that matched nothing into ErrNotFound
+func expectRow(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// uniqueIDs drops repeated IDs, keeping the first occurrence
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
 }
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"backend/model"
+	"backend/service"
+	"encoding/json"
+	"net/http"
+)
+
+// SetUserRole handles PUT /api/admin/users/{userID}/role (platform admin only)
+func (h *AuthHandler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req model.UpdateRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := h.authService.SetUserRole(ActorFrom(r), userID, req.Role); err != nil {
+		respondAccountError(w, err, "Failed to change role")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Role updated successfully"})
+}
+
+// SetUserStatus handles PUT /api/admin/users/{userID}/status (platform admin only)
+func (h *AuthHandler) SetUserStatus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req model.UpdateStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+	if req.IsActive == nil {
+		respondError(w, http.StatusBadRequest, "is_active is required", "")
+		return
+	}
+
+	if err := h.authService.SetUserActive(ActorFrom(r), userID, *req.IsActive); err != nil {
+		respondAccountError(w, err, "Failed to update account")
+		return
+	}
+
+	message := "User deactivated successfully"
+	if *req.IsActive {
+		message = "User activated successfully"
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"message": message})
+}
+
+// DeleteUser handles DELETE /api/admin/users/{userID} (platform admin only)
+// Anonymizes the account by default; ?mode=delete removes it and its stats entirely,
+// which is refused once the user has played a match
+func (h *AuthHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	mode := r.URL.Query().Get("mode")
+	if mode != "" && mode != "anonymize" && mode != "delete" {
+		respondError(w, http.StatusBadRequest, "Invalid mode", "Use anonymize or delete")
+		return
+	}
+
+	if err := h.authService.DeleteUser(ActorFrom(r), userID, mode != "delete"); err != nil {
+		respondAccountError(w, err, "Failed to delete user")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "User deleted successfully"})
+}
+
+// respondAccountError maps account lifecycle errors to responses
+func respondAccountError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case service.ErrUserNotFound:
+		respondError(w, http.StatusNotFound, "User not found", "")
+	case service.ErrInvalidRole:
+		respondError(w, http.StatusBadRequest, "Invalid role", "Use player, organizer or admin")
+	case service.ErrOwnAccount:
+		respondError(w, http.StatusBadRequest, "Cannot change your own account", "Ask another admin")
+	case service.ErrLastAdmin:
+		respondError(w, http.StatusConflict, "At least one active admin must remain", "")
+	case service.ErrUserInMatch:
+		respondError(w, http.StatusConflict, "User is in an unfinished match", "Record or cancel the match first")
+	case service.ErrLastClubAdmin:
+		respondError(w, http.StatusConflict, "User is the only admin of a club", "Make another member a club admin first")
+	case service.ErrUserHasMatches:
+		respondError(w, http.StatusConflict, "User has match history", "Anonymize the account instead of deleting it")
+	default:
+		respondError(w, http.StatusInternalServerError, fallback, err.Error())
+	}
+}
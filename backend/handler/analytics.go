@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"backend/model"
+	"backend/service"
+	"net/http"
+	"strconv"
+)
+
+// GetAnalytics handles GET /api/analytics?min_matches=&user_id=
+// Returns the caller's record by partner and opponent in the club, with their best and worst
+// partners and nemesis among those they played at least min_matches with (default 3).
+// Organizers can pass ?user_id= to see another member's.
+func (h *MatchHandler) GetAnalytics(w http.ResponseWriter, r *http.Request) {
+	payload := getUserFromContext(r.Context())
+	if payload == nil {
+		respondError(w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	query := r.URL.Query()
+	userID := payload.UserID
+	if v := query.Get("user_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			respondError(w, http.StatusBadRequest, "Invalid user ID", "")
+			return
+		}
+		if id != userID && !hasClubRole(r.Context(), model.RoleOrganizer, model.RoleAdmin) {
+			respondError(w, http.StatusForbidden, "Organizer access required", "")
+			return
+		}
+		userID = id
+	}
+
+	var minMatches int
+	if v := query.Get("min_matches"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			respondError(w, http.StatusBadRequest, "Invalid min_matches", "Must be a positive number")
+			return
+		}
+		minMatches = n
+	}
+
+	analytics, err := h.matchService.PlayerAnalytics(getClubID(r.Context()), userID, minMatches)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get analytics", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, analytics)
+}
+
+// GetHeadToHead handles GET /api/analytics/head-to-head?user_id=
+// Returns the caller's record against another club member and as their partner
+func (h *MatchHandler) GetHeadToHead(w http.ResponseWriter, r *http.Request) {
+	payload := getUserFromContext(r.Context())
+	if payload == nil {
+		respondError(w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	otherID, err := strconv.ParseInt(r.URL.Query().Get("user_id"), 10, 64)
+	if err != nil || otherID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid user ID", "Pass the other player as ?user_id=")
+		return
+	}
+
+	h2h, err := h.matchService.HeadToHead(getClubID(r.Context()), payload.UserID, otherID)
+	if err != nil {
+		switch err {
+		case service.ErrSamePlayer:
+			respondError(w, http.StatusBadRequest, "Cannot compare a player with themselves", "")
+		case service.ErrUserNotFound:
+			respondError(w, http.StatusNotFound, "User not found", "")
+		default:
+			respondError(w, http.StatusInternalServerError, "Failed to get head-to-head", err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, h2h)
+}
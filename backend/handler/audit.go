@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"backend/database"
+	"backend/model"
+	"backend/service"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// AuditHandler handles audit log endpoints
+type AuditHandler struct {
+	audit *service.AuditLog
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(audit *service.AuditLog) *AuditHandler {
+	return &AuditHandler{
+		audit: audit,
+	}
+}
+
+// List handles GET /api/admin/audit?actor_id=&action=&target_type=&target_id=&since=&until=&limit= (admin only)
+// Returns the club's changes; platform admins can pass ?all=true to include every club
+// and account-wide changes. since and until are RFC 3339 times.
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := database.AuditFilter{
+		Action:     model.AuditAction(query.Get("action")),
+		TargetType: query.Get("target_type"),
+		TargetID:   query.Get("target_id"),
+	}
+
+	payload := getUserFromContext(r.Context())
+	if query.Get("all") != "true" || payload == nil || payload.Role != model.RoleAdmin {
+		filter.ClubID = getClubID(r.Context())
+	}
+
+	if v := query.Get("actor_id"); v != "" {
+		actorID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || actorID <= 0 {
+			respondError(w, http.StatusBadRequest, "Invalid actor ID", "")
+			return
+		}
+		filter.ActorID = actorID
+	}
+	for name, dst := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if v := query.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				respondError(w, http.StatusBadRequest, "Invalid "+name+" time", "Use RFC 3339, e.g. 2024-01-02T15:04:05Z")
+				return
+			}
+			*dst = t
+		}
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			respondError(w, http.StatusBadRequest, "Invalid limit", "")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	events, err := h.audit.List(filter)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get audit log", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, events)
+}
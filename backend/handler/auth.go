@@ -1,12 +1,18 @@
 package handler
 
 import (
+	"backend/database"
 	"backend/model"
 	"backend/service"
 	"encoding/json"
+	"errors"
+	"math"
+	"net"
 	"net/http"
 	"strconv"
 	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
 )
 
 // AuthHandler handles authentication endpoints
@@ -72,22 +78,31 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, refreshToken, err := h.authService.Login(req)
+	resp, refreshToken, err := h.authService.Login(req, clientInfo(r))
+	var blocked *service.LoginBlockedError
+	if errors.As(err, &blocked) {
+		retry := int(math.Ceil(time.Until(blocked.Until).Seconds()))
+		if retry < 1 {
+			retry = 1
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(retry))
+		if blocked.Err == service.ErrAccountLocked {
+			respondError(w, http.StatusLocked, "Account temporarily locked", "Too many failed logins, try again later")
+		} else {
+			respondError(w, http.StatusTooManyRequests, "Too many failed logins", "Wait before trying again")
+		}
+		return
+	}
+	if err == service.ErrAccountDisabled {
+		respondError(w, http.StatusForbidden, "Account is deactivated", "Contact an administrator")
+		return
+	}
 	if err != nil {
 		respondError(w, http.StatusUnauthorized, "Invalid credentials", "")
 		return
 	}
 
-	// Set refresh token as HttpOnly cookie
-	http.SetCookie(w, &http.Cookie{
-		Name:     "refresh_token",
-		Value:    refreshToken,
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   false, // Set to true in production with HTTPS
-		SameSite: http.SameSiteLaxMode,
-		MaxAge:   int(7 * 24 * time.Hour / time.Second),
-	})
+	setRefreshCookie(w, refreshToken)
 
 	respondJSON(w, http.StatusOK, resp)
 }
@@ -100,12 +115,23 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.authService.RefreshAccessToken(cookie.Value)
+	resp, refreshToken, err := h.authService.RefreshAccessToken(cookie.Value, clientInfo(r))
 	if err != nil {
-		respondError(w, http.StatusUnauthorized, "Invalid or expired refresh token", "")
+		switch err {
+		case service.ErrTokenReused:
+			clearRefreshCookie(w)
+			respondError(w, http.StatusUnauthorized, "Refresh token already used", "Please log in again")
+		case service.ErrInvalidToken:
+			respondError(w, http.StatusUnauthorized, "Invalid or expired refresh token", "")
+		default:
+			respondError(w, http.StatusInternalServerError, "Failed to refresh token", err.Error())
+		}
 		return
 	}
 
+	// Every refresh replaces the cookie; the old token is now spent
+	setRefreshCookie(w, refreshToken)
+
 	respondJSON(w, http.StatusOK, resp)
 }
 
@@ -116,7 +142,44 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 		h.authService.Logout(cookie.Value)
 	}
 
-	// Clear the cookie
+	clearRefreshCookie(w)
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Logged out successfully"})
+}
+
+// StreamTicket handles POST /api/events/ticket
+// Returns a short-lived, single-use ticket for opening /api/events?ticket= from a browser
+func (h *AuthHandler) StreamTicket(w http.ResponseWriter, r *http.Request) {
+	payload := getUserFromContext(r.Context())
+	if payload == nil {
+		respondError(w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	ticket, err := h.authService.IssueStreamTicket(payload.UserID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to issue stream ticket", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, ticket)
+}
+
+// setRefreshCookie stores the refresh token as an HttpOnly cookie
+func setRefreshCookie(w http.ResponseWriter, token string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "refresh_token",
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   false, // Set to true in production with HTTPS
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   int(7 * 24 * time.Hour / time.Second),
+	})
+}
+
+// clearRefreshCookie removes the refresh token cookie
+func clearRefreshCookie(w http.ResponseWriter) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     "refresh_token",
 		Value:    "",
@@ -126,11 +189,9 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 		SameSite: http.SameSiteLaxMode,
 		MaxAge:   -1,
 	})
-
-	respondJSON(w, http.StatusOK, map[string]string{"message": "Logged out successfully"})
 }
 
-// ForgotPassword handles POST /api/forgot-password (verify username + phone)
+// ForgotPassword handles POST /api/forgot-password (send a reset code)
 func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 	var req model.ForgotPasswordRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -138,25 +199,18 @@ func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Username == "" || req.Phone == "" {
-		respondError(w, http.StatusBadRequest, "Username and phone are required", "")
+	if req.Username == "" {
+		respondError(w, http.StatusBadRequest, "Username or phone is required", "")
 		return
 	}
 
-	err := h.authService.VerifyUserForReset(req)
-	if err != nil {
-		switch err {
-		case service.ErrUserNotFound:
-			respondError(w, http.StatusNotFound, "User not found", "")
-		case service.ErrPhoneMismatch:
-			respondError(w, http.StatusBadRequest, "Phone number does not match", "")
-		default:
-			respondError(w, http.StatusInternalServerError, "Verification failed", err.Error())
-		}
+	if err := h.authService.RequestPasswordReset(req); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to send reset code", err.Error())
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{"message": "Verification successful. You can now reset your password."})
+	// Same answer whether or not the account exists
+	respondJSON(w, http.StatusOK, map[string]string{"message": "If the account exists, a reset code has been sent."})
 }
 
 // ResetPassword handles POST /api/reset-password
@@ -167,7 +221,7 @@ func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Username == "" || req.Phone == "" || req.NewPassword == "" {
+	if req.Username == "" || req.Code == "" || req.NewPassword == "" {
 		respondError(w, http.StatusBadRequest, "All fields are required", "")
 		return
 	}
@@ -175,12 +229,13 @@ func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	err := h.authService.ResetPassword(req)
 	if err != nil {
 		switch err {
-		case service.ErrUserNotFound:
-			respondError(w, http.StatusNotFound, "User not found", "")
-		case service.ErrPhoneMismatch:
-			respondError(w, http.StatusBadRequest, "Phone number does not match", "")
+		case service.ErrInvalidResetCode:
+			respondError(w, http.StatusBadRequest, "Invalid or expired reset code", "")
 		case service.ErrWeakPassword:
-			respondError(w, http.StatusBadRequest, "Password does not meet requirements", "")
+			respondError(w, http.StatusBadRequest, "Password does not meet requirements",
+				"Password must be at least 8 characters with uppercase, lowercase, number, and special character")
+		case service.ErrPasswordSameAsUser:
+			respondError(w, http.StatusBadRequest, "Password cannot be the same as username", "")
 		default:
 			respondError(w, http.StatusInternalServerError, "Password reset failed", err.Error())
 		}
@@ -190,6 +245,42 @@ func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Password reset successful. You can now login with your new password."})
 }
 
+// ChangePassword handles PUT /api/profile/password
+// Other devices are signed out; the caller's own device stays signed in
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	payload := getUserFromContext(r.Context())
+	if payload == nil {
+		respondError(w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	var req model.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	err := h.authService.ChangePassword(payload.UserID, req, refreshTokenFrom(r))
+	if err != nil {
+		switch err {
+		case service.ErrInvalidCredentials:
+			respondError(w, http.StatusBadRequest, "Current password is incorrect", "")
+		case service.ErrWeakPassword:
+			respondError(w, http.StatusBadRequest, "Password does not meet requirements",
+				"Password must be at least 8 characters with uppercase, lowercase, number, and special character")
+		case service.ErrPasswordSameAsUser:
+			respondError(w, http.StatusBadRequest, "Password cannot be the same as username", "")
+		case service.ErrUserNotFound:
+			respondError(w, http.StatusNotFound, "User not found", "")
+		default:
+			respondError(w, http.StatusInternalServerError, "Failed to change password", err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Password changed. Other devices have been signed out."})
+}
+
 // UpdatePlayerAdmin handles PUT /api/admin/users/:id (admin only)
 func (h *AuthHandler) UpdatePlayerAdmin(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from URL
@@ -215,7 +306,7 @@ func (h *AuthHandler) UpdatePlayerAdmin(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err = h.authService.UpdatePlayerAdmin(userID, req)
+	err = h.authService.UpdatePlayerAdmin(ActorFrom(r), getClubID(r.Context()), userID, req)
 	if err != nil {
 		respondError(w, http.StatusBadRequest, err.Error(), "")
 		return
@@ -224,6 +315,78 @@ func (h *AuthHandler) UpdatePlayerAdmin(w http.ResponseWriter, r *http.Request)
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Player updated successfully"})
 }
 
+// UnlockUser handles POST /api/admin/users/{userID}/unlock (platform admin only)
+func (h *AuthHandler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.authService.UnlockAccount(ActorFrom(r), userID)
+	if err == service.ErrUserNotFound {
+		respondError(w, http.StatusNotFound, "User not found", "")
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to unlock user", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "User unlocked successfully"})
+}
+
+// LoginEvents handles GET /api/admin/login-events?user_id=&kind=&limit= (platform admin only)
+func (h *AuthHandler) LoginEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := database.LoginEventFilter{Kind: model.LoginEventKind(query.Get("kind"))}
+	if v := query.Get("user_id"); v != "" {
+		userID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || userID <= 0 {
+			respondError(w, http.StatusBadRequest, "Invalid user ID", "")
+			return
+		}
+		filter.UserID = userID
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			respondError(w, http.StatusBadRequest, "Invalid limit", "")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	events, err := h.authService.LoginEvents(filter)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get login events", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, events)
+}
+
+// clientInfo describes the caller for security records. The RealIP middleware has
+// already put the client address into RemoteAddr when a trusted proxy forwarded it.
+func clientInfo(r *http.Request) model.ClientInfo {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return model.ClientInfo{IP: ip, UserAgent: r.UserAgent()}
+}
+
+// ActorFrom identifies who is making a privileged change for the audit log
+func ActorFrom(r *http.Request) model.Actor {
+	actor := model.Actor{
+		RequestID: middleware.GetReqID(r.Context()),
+		IP:        clientInfo(r).IP,
+	}
+	if payload := getUserFromContext(r.Context()); payload != nil {
+		actor.UserID = payload.UserID
+	}
+	return actor
+}
+
 func splitPath(path string) []string {
 	var parts []string
 	for _, p := range splitString(path, '/') {
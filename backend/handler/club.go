@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"backend/model"
+	"backend/service"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ClubHandler handles club and membership endpoints
+type ClubHandler struct {
+	clubService *service.ClubService
+	userService *service.UserService
+}
+
+// NewClubHandler creates a new club handler
+func NewClubHandler(clubSvc *service.ClubService, userSvc *service.UserService) *ClubHandler {
+	return &ClubHandler{
+		clubService: clubSvc,
+		userService: userSvc,
+	}
+}
+
+// List handles GET /api/clubs
+// Returns the caller's clubs; platform admins can pass ?all=true to list every club
+func (h *ClubHandler) List(w http.ResponseWriter, r *http.Request) {
+	payload := getUserFromContext(r.Context())
+	if payload == nil {
+		respondError(w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	if r.URL.Query().Get("all") == "true" && payload.Role == model.RoleAdmin {
+		clubs, err := h.clubService.List()
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to get clubs", err.Error())
+			return
+		}
+		respondJSON(w, http.StatusOK, clubs)
+		return
+	}
+
+	clubs, err := h.clubService.ListForUser(payload.UserID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get clubs", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, clubs)
+}
+
+// Create handles POST /api/clubs (Platform admin only)
+func (h *ClubHandler) Create(w http.ResponseWriter, r *http.Request) {
+	payload := getUserFromContext(r.Context())
+	if payload == nil {
+		respondError(w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	if payload.Role != model.RoleAdmin {
+		respondError(w, http.StatusForbidden, "Admin access required", "")
+		return
+	}
+
+	var req model.CreateClubRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	club, err := h.clubService.Create(ActorFrom(r), req)
+	if err != nil {
+		respondClubError(w, err, "Failed to create club")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, club)
+}
+
+// GetStats handles GET /api/clubs/{clubID}/stats
+// Returns the caller's record in the club's matches
+func (h *ClubHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	payload := getUserFromContext(r.Context())
+	if payload == nil {
+		respondError(w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	stats, err := h.userService.GetClubStats(getClubID(r.Context()), payload.UserID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get stats", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, stats)
+}
+
+// Members handles GET /api/clubs/{clubID}/members (Organizer only)
+func (h *ClubHandler) Members(w http.ResponseWriter, r *http.Request) {
+	members, err := h.clubService.Members(getClubID(r.Context()))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get members", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, members)
+}
+
+// SetMember handles PUT /api/clubs/{clubID}/members (Club admin only)
+// Changes an existing member's role; new members are invited instead
+func (h *ClubHandler) SetMember(w http.ResponseWriter, r *http.Request) {
+	var req model.ClubMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	member, err := h.clubService.SetMember(ActorFrom(r), getClubID(r.Context()), req)
+	if err != nil {
+		respondClubError(w, err, "Failed to update member")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, member)
+}
+
+// Invite handles POST /api/clubs/{clubID}/invites (Club admin only)
+// The user joins the club once they accept the invite
+func (h *ClubHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	var req model.ClubMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	invite, err := h.clubService.InviteMember(ActorFrom(r), getClubID(r.Context()), req)
+	if err != nil {
+		respondClubError(w, err, "Failed to invite member")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, invite)
+}
+
+// ListInvites handles GET /api/invites
+// Returns the caller's pending club invites
+func (h *ClubHandler) ListInvites(w http.ResponseWriter, r *http.Request) {
+	payload := getUserFromContext(r.Context())
+	if payload == nil {
+		respondError(w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	invites, err := h.clubService.Invites(payload.UserID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get invites", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, invites)
+}
+
+// AcceptInvite handles POST /api/invites/{clubID}/accept
+func (h *ClubHandler) AcceptInvite(w http.ResponseWriter, r *http.Request) {
+	clubID, err := strconv.ParseInt(chi.URLParam(r, "clubID"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid club ID", "")
+		return
+	}
+
+	member, err := h.clubService.AcceptInvite(ActorFrom(r), clubID)
+	if err != nil {
+		respondClubError(w, err, "Failed to accept invite")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, member)
+}
+
+// DeclineInvite handles DELETE /api/invites/{clubID}
+func (h *ClubHandler) DeclineInvite(w http.ResponseWriter, r *http.Request) {
+	clubID, err := strconv.ParseInt(chi.URLParam(r, "clubID"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid club ID", "")
+		return
+	}
+
+	if err := h.clubService.DeclineInvite(ActorFrom(r), clubID); err != nil {
+		respondClubError(w, err, "Failed to decline invite")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Invite declined"})
+}
+
+// RemoveMember handles DELETE /api/clubs/{clubID}/members/{userID} (Club admin only)
+func (h *ClubHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID", "")
+		return
+	}
+
+	if err := h.clubService.RemoveMember(ActorFrom(r), getClubID(r.Context()), userID); err != nil {
+		respondClubError(w, err, "Failed to remove member")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Member removed"})
+}
+
+// respondClubError maps club errors to HTTP responses
+func respondClubError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case service.ErrClubNotFound:
+		respondError(w, http.StatusNotFound, "Club not found", "")
+	case service.ErrClubExists:
+		respondError(w, http.StatusConflict, "Club slug already exists", "")
+	case service.ErrInvalidClub:
+		respondError(w, http.StatusBadRequest, "Invalid club details",
+			"Slug must be 2-50 lowercase letters, digits or dashes and name is required")
+	case service.ErrNotClubMember:
+		respondError(w, http.StatusNotFound, "Not a member of this club", "Invite the user to add them to the club")
+	case service.ErrAlreadyMember:
+		respondError(w, http.StatusConflict, "User is already a member of this club", "")
+	case service.ErrNoInvite:
+		respondError(w, http.StatusNotFound, "No pending invite to this club", "")
+	case service.ErrUserNotFound:
+		respondError(w, http.StatusNotFound, "User not found", "")
+	case service.ErrInvalidRole:
+		respondError(w, http.StatusBadRequest, "Invalid role", "Role must be player, organizer or admin")
+	case service.ErrLastClubAdmin:
+		respondError(w, http.StatusConflict, "Club must keep at least one admin", "")
+	default:
+		respondError(w, http.StatusInternalServerError, fallback, err.Error())
+	}
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"backend/model"
+	"backend/service"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CourtHandler handles court registry endpoints
+type CourtHandler struct {
+	courtService *service.CourtService
+}
+
+// NewCourtHandler creates a new court handler
+func NewCourtHandler(courtSvc *service.CourtService) *CourtHandler {
+	return &CourtHandler{
+		courtService: courtSvc,
+	}
+}
+
+// List handles GET /api/courts
+// Pass ?all=true to include courts under maintenance
+func (h *CourtHandler) List(w http.ResponseWriter, r *http.Request) {
+	activeOnly := r.URL.Query().Get("all") != "true"
+
+	courts, err := h.courtService.List(getClubID(r.Context()), activeOnly)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get courts", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, courts)
+}
+
+// Create handles POST /api/courts (Organizer only)
+func (h *CourtHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateCourtRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	court, err := h.courtService.Create(ActorFrom(r), getClubID(r.Context()), req)
+	if err != nil {
+		respondCourtError(w, err, "Failed to create court")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, court)
+}
+
+// Update handles PUT /api/courts/{courtID} (Organizer only)
+func (h *CourtHandler) Update(w http.ResponseWriter, r *http.Request) {
+	courtID, err := strconv.ParseInt(chi.URLParam(r, "courtID"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid court ID", "")
+		return
+	}
+
+	var req model.UpdateCourtRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	court, err := h.courtService.Update(ActorFrom(r), getClubID(r.Context()), courtID, req)
+	if err != nil {
+		respondCourtError(w, err, "Failed to update court")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, court)
+}
+
+// Delete handles DELETE /api/courts/{courtID} (Organizer only)
+func (h *CourtHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	courtID, err := strconv.ParseInt(chi.URLParam(r, "courtID"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid court ID", "")
+		return
+	}
+
+	if err := h.courtService.Delete(ActorFrom(r), getClubID(r.Context()), courtID); err != nil {
+		respondCourtError(w, err, "Failed to delete court")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Court deleted successfully"})
+}
+
+func respondCourtError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case service.ErrCourtNotFound:
+		respondError(w, http.StatusNotFound, "Court not found", "")
+	case service.ErrCourtExists:
+		respondError(w, http.StatusConflict, "Court name already exists", "")
+	case service.ErrInvalidCourt:
+		respondError(w, http.StatusBadRequest, "Invalid court details",
+			"Name is required; status must be active or maintenance; surface must be synthetic, wood, or concrete")
+	default:
+		respondError(w, http.StatusInternalServerError, fallback, err.Error())
+	}
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"backend/service"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ListDevices handles GET /api/devices
+func (h *AuthHandler) ListDevices(w http.ResponseWriter, r *http.Request) {
+	payload := getUserFromContext(r.Context())
+	if payload == nil {
+		respondError(w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	devices, err := h.authService.ListDevices(payload.UserID, refreshTokenFrom(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get devices", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, devices)
+}
+
+// RevokeDevice handles DELETE /api/devices/{deviceID}
+func (h *AuthHandler) RevokeDevice(w http.ResponseWriter, r *http.Request) {
+	payload := getUserFromContext(r.Context())
+	if payload == nil {
+		respondError(w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	current, err := h.authService.RevokeDevice(payload.UserID, chi.URLParam(r, "deviceID"), refreshTokenFrom(r))
+	if err == service.ErrDeviceNotFound {
+		respondError(w, http.StatusNotFound, "Device not found", "")
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to sign out device", err.Error())
+		return
+	}
+
+	if current {
+		clearRefreshCookie(w)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Device signed out"})
+}
+
+// RevokeDevices handles DELETE /api/devices
+// Signs out every device; ?keep_current=true leaves the caller's own signed in
+func (h *AuthHandler) RevokeDevices(w http.ResponseWriter, r *http.Request) {
+	payload := getUserFromContext(r.Context())
+	if payload == nil {
+		respondError(w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	keepCurrent := r.URL.Query().Get("keep_current") == "true"
+	keep := ""
+	if keepCurrent {
+		keep = refreshTokenFrom(r)
+	}
+
+	if err := h.authService.RevokeDevices(payload.UserID, keep); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to sign out devices", err.Error())
+		return
+	}
+
+	if !keepCurrent {
+		clearRefreshCookie(w)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Devices signed out"})
+}
+
+// ListUserDevices handles GET /api/admin/users/{userID}/devices (platform admin only)
+func (h *AuthHandler) ListUserDevices(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	devices, err := h.authService.ListUserDevices(userID)
+	if err == service.ErrUserNotFound {
+		respondError(w, http.StatusNotFound, "User not found", "")
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get devices", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, devices)
+}
+
+// RevokeUserDevice handles DELETE /api/admin/users/{userID}/devices/{deviceID} (platform admin only)
+func (h *AuthHandler) RevokeUserDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.authService.RevokeUserDevice(ActorFrom(r), userID, chi.URLParam(r, "deviceID"))
+	switch err {
+	case nil:
+		respondJSON(w, http.StatusOK, map[string]string{"message": "Device signed out"})
+	case service.ErrUserNotFound:
+		respondError(w, http.StatusNotFound, "User not found", "")
+	case service.ErrDeviceNotFound:
+		respondError(w, http.StatusNotFound, "Device not found", "")
+	default:
+		respondError(w, http.StatusInternalServerError, "Failed to sign out device", err.Error())
+	}
+}
+
+// RevokeUserDevices handles DELETE /api/admin/users/{userID}/devices (platform admin only)
+func (h *AuthHandler) RevokeUserDevices(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.authService.RevokeUserDevices(ActorFrom(r), userID)
+	if err == service.ErrUserNotFound {
+		respondError(w, http.StatusNotFound, "User not found", "")
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to sign out devices", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Devices signed out"})
+}
+
+// refreshTokenFrom returns the caller's refresh token cookie, or "" without one
+func refreshTokenFrom(r *http.Request) string {
+	if cookie, err := r.Cookie("refresh_token"); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// userIDParam parses the {userID} route parameter, responding with 400 when it is invalid
+func userIDParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil || userID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid user ID", "")
+		return 0, false
+	}
+	return userID, true
+}
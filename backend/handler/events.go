@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"backend/service"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// heartbeatInterval keeps idle connections from being closed by proxies
+const heartbeatInterval = 25 * time.Second
+
+// EventHandler streams real-time updates over Server-Sent Events
+type EventHandler struct {
+	events *service.EventBroker
+}
+
+// NewEventHandler creates a new event handler
+func NewEventHandler(events *service.EventBroker) *EventHandler {
+	return &EventHandler{
+		events: events,
+	}
+}
+
+// Stream handles GET /api/events (club members only)
+// Members receive the club's broadcasts and their personal events, such as being
+// called to a court.
+func (h *EventHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondError(w, http.StatusInternalServerError, "Streaming not supported", "")
+		return
+	}
+
+	var userID int64
+	if payload := getUserFromContext(r.Context()); payload != nil {
+		userID = payload.UserID
+	}
+
+	// The server's WriteTimeout would otherwise cut the stream off
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	ch, unsubscribe := h.events.Subscribe(userID, getClubID(r.Context()))
+	defer unsubscribe()
+
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			flusher.Flush()
+		}
+	}
+}
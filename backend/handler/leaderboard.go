@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"backend/model"
+	"backend/service"
+	"net/http"
+	"strconv"
+)
+
+// LeaderboardHandler handles leaderboard endpoints
+type LeaderboardHandler struct {
+	leaderboardService *service.LeaderboardService
+}
+
+// NewLeaderboardHandler creates a new leaderboard handler
+func NewLeaderboardHandler(leaderboardSvc *service.LeaderboardService) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		leaderboardService: leaderboardSvc,
+	}
+}
+
+// Get handles GET /api/leaderboard?sort=&window=&season=&tier=&hand=&min_matches=&page=&page_size=
+// sort is rating (default), win_rate, wins or streak; window is week, month, season (the club's
+// current season) or all (default). ?season={id} ranks a past or current season by its records
+// and ratings. Win rate only ranks players with at least min_matches matches (default 5).
+func (h *LeaderboardHandler) Get(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	query := model.LeaderboardQuery{
+		Sort:   model.LeaderboardSort(values.Get("sort")),
+		Window: model.LeaderboardWindow(values.Get("window")),
+		Tier:   model.SkillTier(values.Get("tier")),
+		Hand:   model.HandPreference(values.Get("hand")),
+	}
+
+	if v := values.Get("season"); v != "" {
+		seasonID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || seasonID <= 0 {
+			respondError(w, http.StatusBadRequest, "Invalid season ID", "")
+			return
+		}
+		query.Season = seasonID
+	}
+
+	for name, dst := range map[string]*int{"min_matches": &query.MinMatches, "page": &query.Page, "page_size": &query.PageSize} {
+		if v := values.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				respondError(w, http.StatusBadRequest, "Invalid "+name, "Must be a positive number")
+				return
+			}
+			*dst = n
+		}
+	}
+
+	board, err := h.leaderboardService.Leaderboard(getClubID(r.Context()), query)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidSort:
+			respondError(w, http.StatusBadRequest, "Invalid sort", "Use rating, win_rate, wins or streak")
+		case service.ErrInvalidWindow:
+			respondError(w, http.StatusBadRequest, "Invalid window", "Use week, month, season or all")
+		case service.ErrInvalidTier:
+			respondError(w, http.StatusBadRequest, "Invalid skill tier", "")
+		case service.ErrInvalidHand:
+			respondError(w, http.StatusBadRequest, "Invalid hand preference", "Use left or right")
+		case service.ErrSeasonNotFound:
+			respondError(w, http.StatusNotFound, "Season not found", "")
+		case service.ErrNoActiveSeason:
+			respondError(w, http.StatusNotFound, "No active season", "Pass ?season= for a past season")
+		default:
+			respondError(w, http.StatusInternalServerError, "Failed to get leaderboard", err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, board)
+}
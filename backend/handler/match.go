@@ -4,23 +4,30 @@ import (
 	"backend/model"
 	"backend/service"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
 )
 
 // MatchHandler handles match endpoints
 type MatchHandler struct {
-	matchService *service.MatchService
+	matchService   *service.MatchService
+	sessionService *service.SessionService
 }
 
 // NewMatchHandler creates a new match handler
-func NewMatchHandler(matchSvc *service.MatchService) *MatchHandler {
+func NewMatchHandler(matchSvc *service.MatchService, sessionSvc *service.SessionService) *MatchHandler {
 	return &MatchHandler{
-		matchService: matchSvc,
+		matchService:   matchSvc,
+		sessionService: sessionSvc,
 	}
 }
 
 // GetHistory handles GET /api/matches
+// Pass ?session={id} to only include one session's matches
 func (h *MatchHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 	payload := getUserFromContext(r.Context())
 	if payload == nil {
@@ -28,7 +35,12 @@ func (h *MatchHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	history, err := h.matchService.GetHistory(payload.UserID, 20)
+	sessionID, ok := sessionQuery(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := h.matchService.GetHistory(payload.UserID, getClubID(r.Context()), sessionID, 20)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to get match history", err.Error())
 		return
@@ -45,8 +57,8 @@ func (h *MatchHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if user is organizer or admin
-	if payload.Role != "organizer" && payload.Role != "admin" {
+	// Check if user is organizer or admin of the club
+	if !hasClubRole(r.Context(), model.RoleOrganizer, model.RoleAdmin) {
 		respondError(w, http.StatusForbidden, "Organizer access required", "")
 		return
 	}
@@ -57,12 +69,34 @@ func (h *MatchHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	match, err := h.matchService.Create(req.Court, req.Team1, req.Team2)
+	clubID := getClubID(r.Context())
+	sessionID, err := h.sessionService.Resolve(clubID, req.SessionID)
+	if err != nil {
+		respondSessionError(w, err, "Failed to resolve session")
+		return
+	}
+
+	match, err := h.matchService.Create(ActorFrom(r), clubID, sessionID, req.Court, req.Team1, req.Team2, req.Scoring)
 	if err != nil {
 		switch err {
 		case service.ErrInvalidTeam:
 			respondError(w, http.StatusBadRequest, "Invalid team configuration",
 				"Each team must have 1-2 players")
+		case service.ErrCourtNotFound:
+			respondError(w, http.StatusBadRequest, "Unknown court", "Court must be registered in the court list")
+		case service.ErrCourtInactive:
+			respondError(w, http.StatusConflict, "Court is under maintenance", "")
+		case service.ErrCourtBusy:
+			respondError(w, http.StatusConflict, "Court already has a match in progress", "")
+		case service.ErrInvalidScoringRules:
+			respondError(w, http.StatusBadRequest, "Invalid scoring rules",
+				"points_to_win must be positive, point_cap at least points_to_win, and best_of an odd number")
+		case service.ErrCourtNotInSession:
+			respondError(w, http.StatusBadRequest, "Court is not booked for this session", "")
+		case service.ErrNotClubMember:
+			respondError(w, http.StatusBadRequest, "Invalid team configuration", "All players must be members of the club")
+		case service.ErrSessionNotFound, service.ErrSessionNotOpen:
+			respondSessionError(w, err, "")
 		default:
 			respondError(w, http.StatusInternalServerError, "Failed to create match", err.Error())
 		}
@@ -80,8 +114,8 @@ func (h *MatchHandler) RecordResult(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if user is organizer or admin
-	if payload.Role != "organizer" && payload.Role != "admin" {
+	// Check if user is organizer or admin of the club
+	if !hasClubRole(r.Context(), model.RoleOrganizer, model.RoleAdmin) {
 		respondError(w, http.StatusForbidden, "Organizer access required", "")
 		return
 	}
@@ -92,11 +126,19 @@ func (h *MatchHandler) RecordResult(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	match, err := h.matchService.RecordResult(req.MatchID, req.Scores)
+	match, err := h.matchService.RecordResult(ActorFrom(r), getClubID(r.Context()), req.MatchID, req.Scores)
 	if err != nil {
+		var scoreErr *service.ScoreError
+		if errors.As(err, &scoreErr) {
+			respondError(w, http.StatusBadRequest, "Invalid scores", scoreErr.Error())
+			return
+		}
+
 		switch err {
 		case service.ErrMatchNotFound:
 			respondError(w, http.StatusNotFound, "Match not found", "")
+		case service.ErrMatchAlreadyRecorded:
+			respondError(w, http.StatusConflict, "Match result already recorded", "")
 		default:
 			respondError(w, http.StatusInternalServerError, "Failed to record result", err.Error())
 		}
@@ -106,9 +148,109 @@ func (h *MatchHandler) RecordResult(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, match)
 }
 
+// CorrectResult handles PUT /api/matches/{matchID}/scores (Organizer only)
+func (h *MatchHandler) CorrectResult(w http.ResponseWriter, r *http.Request) {
+	matchID, ok := matchIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req model.CorrectResultRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	match, err := h.matchService.CorrectResult(ActorFrom(r), getClubID(r.Context()), matchID, req)
+	if err != nil {
+		respondCorrectionError(w, err, "Failed to correct result")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, match)
+}
+
+// VoidMatch handles POST /api/matches/{matchID}/void (Organizer only)
+func (h *MatchHandler) VoidMatch(w http.ResponseWriter, r *http.Request) {
+	matchID, ok := matchIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req model.VoidMatchRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+			return
+		}
+	}
+
+	match, err := h.matchService.VoidMatch(ActorFrom(r), getClubID(r.Context()), matchID, req.Reason)
+	if err != nil {
+		respondCorrectionError(w, err, "Failed to void match")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, match)
+}
+
+// GetCorrections handles GET /api/matches/{matchID}/corrections (Organizer only)
+func (h *MatchHandler) GetCorrections(w http.ResponseWriter, r *http.Request) {
+	matchID, ok := matchIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	corrections, err := h.matchService.Corrections(getClubID(r.Context()), matchID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get corrections", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, corrections)
+}
+
+// respondCorrectionError maps result correction errors to HTTP responses
+func respondCorrectionError(w http.ResponseWriter, err error, fallback string) {
+	var scoreErr *service.ScoreError
+	if errors.As(err, &scoreErr) {
+		respondError(w, http.StatusBadRequest, "Invalid scores", scoreErr.Error())
+		return
+	}
+
+	switch err {
+	case service.ErrMatchNotFound:
+		respondError(w, http.StatusNotFound, "Match not found", "")
+	case service.ErrMatchNotFinished:
+		respondError(w, http.StatusConflict, "Match has no result yet", "Record the result instead")
+	case service.ErrMatchVoided:
+		respondError(w, http.StatusConflict, "Match has been voided", "")
+	case service.ErrBracketResultLocked:
+		respondError(w, http.StatusConflict, "Tournament match winner cannot change", "The bracket has already moved on; only the scores can be corrected")
+	default:
+		respondError(w, http.StatusInternalServerError, fallback, err.Error())
+	}
+}
+
+// matchIDParam reads the {matchID} path parameter, responding with 400 when it is invalid
+func matchIDParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	matchID, err := strconv.ParseInt(chi.URLParam(r, "matchID"), 10, 64)
+	if err != nil || matchID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid match ID", "")
+		return 0, false
+	}
+	return matchID, true
+}
+
 // GetActive handles GET /api/matches/active
+// Pass ?session={id} to only include one session's matches
 func (h *MatchHandler) GetActive(w http.ResponseWriter, r *http.Request) {
-	matches, err := h.matchService.GetActive()
+	sessionID, ok := sessionQuery(w, r)
+	if !ok {
+		return
+	}
+
+	matches, err := h.matchService.GetActive(getClubID(r.Context()), sessionID)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to get active matches", err.Error())
 		return
@@ -124,8 +266,8 @@ func (h *MatchHandler) GetUserHistory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if user is admin or organizer
-	if payload.Role != "admin" && payload.Role != "organizer" {
+	// Check if user is admin or organizer of the club
+	if !hasClubRole(r.Context(), model.RoleAdmin, model.RoleOrganizer) {
 		respondError(w, http.StatusForbidden, "Admin access required", "")
 		return
 	}
@@ -143,7 +285,12 @@ func (h *MatchHandler) GetUserHistory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	history, err := h.matchService.GetHistory(userID, 50)
+	sessionID, ok := sessionQuery(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := h.matchService.GetHistory(userID, getClubID(r.Context()), sessionID, 50)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to get match history", err.Error())
 		return
@@ -151,3 +298,60 @@ func (h *MatchHandler) GetUserHistory(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, history)
 }
+
+// GetAutoDispatch handles GET /api/matches/auto-dispatch (Organizer only)
+// Reports whether the club refills freed courts automatically
+func (h *MatchHandler) GetAutoDispatch(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string]bool{"enabled": h.matchService.AutoDispatchEnabled(getClubID(r.Context()))})
+}
+
+// SetAutoDispatch handles PUT /api/matches/auto-dispatch (Organizer only)
+func (h *MatchHandler) SetAutoDispatch(w http.ResponseWriter, r *http.Request) {
+	var req model.AutoDispatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := h.matchService.SetAutoDispatch(ActorFrom(r), getClubID(r.Context()), req.Enabled); err != nil {
+		respondClubError(w, err, "Failed to update auto-dispatch")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]bool{"enabled": req.Enabled})
+}
+
+// BalanceTeams handles POST /api/matches/balance (Organizer only)
+func (h *MatchHandler) BalanceTeams(w http.ResponseWriter, r *http.Request) {
+	var req model.BalanceTeamsRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+			return
+		}
+	}
+
+	clubID := getClubID(r.Context())
+	sessionID, err := h.sessionService.Resolve(clubID, req.SessionID)
+	if err != nil {
+		respondSessionError(w, err, "Failed to resolve session")
+		return
+	}
+	req.SessionID = sessionID
+
+	proposal, err := h.matchService.BalanceTeams(clubID, req)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidTeam:
+			respondError(w, http.StatusBadRequest, "Invalid player selection",
+				"Provide 2 or 4 distinct players, or call players from the queue first")
+		case service.ErrUserNotFound:
+			respondError(w, http.StatusNotFound, "Player not found", "")
+		default:
+			respondError(w, http.StatusInternalServerError, "Failed to balance teams", err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, proposal)
+}
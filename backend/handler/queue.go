@@ -3,21 +3,42 @@ package handler
 import (
 	"backend/model"
 	"backend/service"
+	"encoding/json"
 	"net/http"
 )
 
 // QueueHandler handles queue endpoints
 type QueueHandler struct {
-	queueService *service.QueueService
+	queueService   *service.QueueService
+	sessionService *service.SessionService
 }
 
 // NewQueueHandler creates a new queue handler
-func NewQueueHandler(queueSvc *service.QueueService) *QueueHandler {
+func NewQueueHandler(queueSvc *service.QueueService, sessionSvc *service.SessionService) *QueueHandler {
 	return &QueueHandler{
-		queueService: queueSvc,
+		queueService:   queueSvc,
+		sessionService: sessionSvc,
 	}
 }
 
+// resolveSession picks the club queue a request targets: ?session= or the body's session_id,
+// otherwise the club's current open session
+func (h *QueueHandler) resolveSession(w http.ResponseWriter, r *http.Request, requested int64) (int64, bool) {
+	if requested == 0 {
+		var ok bool
+		if requested, ok = sessionQuery(w, r); !ok {
+			return 0, false
+		}
+	}
+
+	sessionID, err := h.sessionService.Resolve(getClubID(r.Context()), requested)
+	if err != nil {
+		respondSessionError(w, err, "Failed to resolve session")
+		return 0, false
+	}
+	return sessionID, true
+}
+
 // GetStatus handles GET /api/queue
 func (h *QueueHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
 	payload := getUserFromContext(r.Context())
@@ -27,7 +48,12 @@ func (h *QueueHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
 		userID = payload.UserID
 	}
 
-	info, err := h.queueService.GetStatus(userID)
+	sessionID, ok := h.resolveSession(w, r, 0)
+	if !ok {
+		return
+	}
+
+	info, err := h.queueService.GetStatus(getClubID(r.Context()), sessionID, userID)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to get queue status", err.Error())
 		return
@@ -43,7 +69,20 @@ func (h *QueueHandler) Join(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	entry, err := h.queueService.Join(payload.UserID)
+	var req model.JoinQueueRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+			return
+		}
+	}
+
+	sessionID, ok := h.resolveSession(w, r, req.SessionID)
+	if !ok {
+		return
+	}
+
+	entry, err := h.queueService.Join(getClubID(r.Context()), sessionID, payload.UserID)
 	if err != nil {
 		switch err {
 		case service.ErrAlreadyInQueue:
@@ -55,7 +94,7 @@ func (h *QueueHandler) Join(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get updated status
-	info, _ := h.queueService.GetStatus(payload.UserID)
+	info, _ := h.queueService.GetStatus(getClubID(r.Context()), sessionID, payload.UserID)
 
 	respondJSON(w, http.StatusOK, model.QueueResponse{
 		Entry:   entry,
@@ -72,7 +111,12 @@ func (h *QueueHandler) Leave(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.queueService.Leave(payload.UserID)
+	sessionID, ok := h.resolveSession(w, r, 0)
+	if !ok {
+		return
+	}
+
+	err := h.queueService.Leave(getClubID(r.Context()), sessionID, payload.UserID)
 	if err != nil {
 		switch err {
 		case service.ErrNotInQueue:
@@ -84,7 +128,7 @@ func (h *QueueHandler) Leave(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get updated status
-	info, _ := h.queueService.GetStatus(payload.UserID)
+	info, _ := h.queueService.GetStatus(getClubID(r.Context()), sessionID, payload.UserID)
 
 	respondJSON(w, http.StatusOK, model.QueueResponse{
 		Info:    *info,
@@ -100,14 +144,19 @@ func (h *QueueHandler) CallNext(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if user is organizer or admin
-	if payload.Role != "organizer" && payload.Role != "admin" {
+	// Check if user is organizer or admin of the club
+	if !hasClubRole(r.Context(), model.RoleOrganizer, model.RoleAdmin) {
 		respondError(w, http.StatusForbidden, "Organizer access required", "")
 		return
 	}
 
+	sessionID, ok := h.resolveSession(w, r, 0)
+	if !ok {
+		return
+	}
+
 	// Call 4 players (for a doubles match)
-	called, err := h.queueService.CallNext(4)
+	called, err := h.queueService.CallNext(ActorFrom(r), getClubID(r.Context()), sessionID, 4)
 	if err != nil {
 		if err == service.ErrQueueEmpty {
 			respondJSON(w, http.StatusOK, map[string]interface{}{
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"backend/model"
+	"backend/service"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SeasonHandler handles season endpoints
+type SeasonHandler struct {
+	seasonService *service.SeasonService
+}
+
+// NewSeasonHandler creates a new season handler
+func NewSeasonHandler(seasonSvc *service.SeasonService) *SeasonHandler {
+	return &SeasonHandler{
+		seasonService: seasonSvc,
+	}
+}
+
+// List handles GET /api/seasons
+func (h *SeasonHandler) List(w http.ResponseWriter, r *http.Request) {
+	seasons, err := h.seasonService.List(getClubID(r.Context()))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get seasons", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, seasons)
+}
+
+// Get handles GET /api/seasons/{seasonID}
+// Returns the season with every player's record and rating in it
+func (h *SeasonHandler) Get(w http.ResponseWriter, r *http.Request) {
+	seasonID, ok := seasonURLParam(w, r)
+	if !ok {
+		return
+	}
+
+	season, err := h.seasonService.Get(getClubID(r.Context()), seasonID)
+	if err != nil {
+		respondSeasonError(w, err, "Failed to get season")
+		return
+	}
+
+	standings, err := h.seasonService.Standings(*season)
+	if err != nil {
+		respondSeasonError(w, err, "Failed to get season standings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, model.SeasonStandings{Season: *season, Standings: standings})
+}
+
+// Start handles POST /api/seasons (Organizer only)
+func (h *SeasonHandler) Start(w http.ResponseWriter, r *http.Request) {
+	var req model.StartSeasonRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	season, err := h.seasonService.Start(ActorFrom(r), getClubID(r.Context()), req)
+	if err != nil {
+		respondSeasonError(w, err, "Failed to start season")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, season)
+}
+
+// End handles POST /api/seasons/{seasonID}/end (Organizer only)
+func (h *SeasonHandler) End(w http.ResponseWriter, r *http.Request) {
+	seasonID, ok := seasonURLParam(w, r)
+	if !ok {
+		return
+	}
+
+	season, err := h.seasonService.End(ActorFrom(r), getClubID(r.Context()), seasonID)
+	if err != nil {
+		respondSeasonError(w, err, "Failed to end season")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, season)
+}
+
+func seasonURLParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	seasonID, err := strconv.ParseInt(chi.URLParam(r, "seasonID"), 10, 64)
+	if err != nil || seasonID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid season ID", "")
+		return 0, false
+	}
+	return seasonID, true
+}
+
+func respondSeasonError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case service.ErrSeasonNotFound:
+		respondError(w, http.StatusNotFound, "Season not found", "")
+	case service.ErrSeasonActive:
+		respondError(w, http.StatusConflict, "Club already has an active season", "End it before starting another")
+	case service.ErrSeasonEnded:
+		respondError(w, http.StatusConflict, "Season has already ended", "")
+	case service.ErrSeasonNameMissing:
+		respondError(w, http.StatusBadRequest, "Season name is required", "")
+	case service.ErrInvalidCarryover:
+		respondError(w, http.StatusBadRequest, "Invalid rating carry-over", "Must be between 0 (reset to the mean) and 1 (keep ratings)")
+	default:
+		respondError(w, http.StatusInternalServerError, fallback, err.Error())
+	}
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"backend/model"
+	"backend/service"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SessionHandler handles play session endpoints
+type SessionHandler struct {
+	sessionService *service.SessionService
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(sessionSvc *service.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionSvc,
+	}
+}
+
+// List handles GET /api/sessions
+// Pass ?status=scheduled|open|closed to filter
+func (h *SessionHandler) List(w http.ResponseWriter, r *http.Request) {
+	sessions, err := h.sessionService.List(getClubID(r.Context()), r.URL.Query().Get("status"), 50)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get sessions", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, sessions)
+}
+
+// Get handles GET /api/sessions/{sessionID}
+func (h *SessionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := sessionURLParam(w, r)
+	if !ok {
+		return
+	}
+
+	session, err := h.sessionService.GetByID(getClubID(r.Context()), sessionID)
+	if err != nil {
+		respondSessionError(w, err, "Failed to get session")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, session)
+}
+
+// Create handles POST /api/sessions (Organizer only)
+func (h *SessionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	payload := getUserFromContext(r.Context())
+	if payload == nil {
+		respondError(w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	var req model.SessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	session, err := h.sessionService.Create(ActorFrom(r), getClubID(r.Context()), req)
+	if err != nil {
+		respondSessionError(w, err, "Failed to create session")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, session)
+}
+
+// Update handles PUT /api/sessions/{sessionID} (Organizer only)
+func (h *SessionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := sessionURLParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req model.SessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	session, err := h.sessionService.Update(ActorFrom(r), getClubID(r.Context()), sessionID, req)
+	if err != nil {
+		respondSessionError(w, err, "Failed to update session")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, session)
+}
+
+// Open handles POST /api/sessions/{sessionID}/open (Organizer only)
+func (h *SessionHandler) Open(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := sessionURLParam(w, r)
+	if !ok {
+		return
+	}
+
+	session, err := h.sessionService.Open(ActorFrom(r), getClubID(r.Context()), sessionID)
+	if err != nil {
+		respondSessionError(w, err, "Failed to open session")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, session)
+}
+
+// Close handles POST /api/sessions/{sessionID}/close (Organizer only)
+func (h *SessionHandler) Close(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := sessionURLParam(w, r)
+	if !ok {
+		return
+	}
+
+	session, err := h.sessionService.Close(ActorFrom(r), getClubID(r.Context()), sessionID)
+	if err != nil {
+		respondSessionError(w, err, "Failed to close session")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, session)
+}
+
+func sessionURLParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	sessionID, err := strconv.ParseInt(chi.URLParam(r, "sessionID"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid session ID", "")
+		return 0, false
+	}
+	return sessionID, true
+}
+
+// sessionQuery reads the optional ?session= filter; 0 means not given
+func sessionQuery(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	raw := r.URL.Query().Get("session")
+	if raw == "" {
+		return 0, true
+	}
+	sessionID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || sessionID < 0 {
+		respondError(w, http.StatusBadRequest, "Invalid session ID", "")
+		return 0, false
+	}
+	return sessionID, true
+}
+
+// respondSessionError maps session errors to HTTP responses
+func respondSessionError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case service.ErrSessionNotFound:
+		respondError(w, http.StatusNotFound, "Session not found", "")
+	case service.ErrSessionNotOpen:
+		respondError(w, http.StatusConflict, "Session is not open", "")
+	case service.ErrSessionClosed:
+		respondError(w, http.StatusConflict, "Session is closed", "")
+	case service.ErrSessionActiveMatches:
+		respondError(w, http.StatusConflict, "Session still has matches in progress",
+			"Record all results before closing the session")
+	case service.ErrInvalidSession:
+		respondError(w, http.StatusBadRequest, "Invalid session details",
+			"Name is required, fee cannot be negative and ends_at must be after starts_at")
+	case service.ErrInvalidScoringRules:
+		respondError(w, http.StatusBadRequest, "Invalid scoring rules", "")
+	case service.ErrCourtNotFound:
+		respondError(w, http.StatusBadRequest, "Unknown court", "Court must be registered in the court list")
+	default:
+		respondError(w, http.StatusInternalServerError, fallback, err.Error())
+	}
+}
@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"backend/model"
+	"backend/service"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TournamentHandler handles tournament endpoints
+type TournamentHandler struct {
+	tournamentService *service.TournamentService
+}
+
+// NewTournamentHandler creates a new tournament handler
+func NewTournamentHandler(tournamentSvc *service.TournamentService) *TournamentHandler {
+	return &TournamentHandler{
+		tournamentService: tournamentSvc,
+	}
+}
+
+// List handles GET /api/tournaments
+func (h *TournamentHandler) List(w http.ResponseWriter, r *http.Request) {
+	tournaments, err := h.tournamentService.List(getClubID(r.Context()))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get tournaments", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tournaments)
+}
+
+// Get handles GET /api/tournaments/{tournamentID}
+// Returns the tournament with its entries and bracket
+func (h *TournamentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tournamentID, ok := tournamentURLParam(w, r)
+	if !ok {
+		return
+	}
+
+	bracket, err := h.tournamentService.Get(getClubID(r.Context()), tournamentID)
+	if err != nil {
+		respondTournamentError(w, err, "Failed to get tournament")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, bracket)
+}
+
+// Create handles POST /api/tournaments (Organizer only)
+func (h *TournamentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req model.TournamentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	tournament, err := h.tournamentService.Create(ActorFrom(r), getClubID(r.Context()), req)
+	if err != nil {
+		respondTournamentError(w, err, "Failed to create tournament")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, tournament)
+}
+
+// AddEntry handles POST /api/tournaments/{tournamentID}/entries (Organizer only)
+func (h *TournamentHandler) AddEntry(w http.ResponseWriter, r *http.Request) {
+	tournamentID, ok := tournamentURLParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req model.TournamentEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	entry, err := h.tournamentService.AddEntry(ActorFrom(r), getClubID(r.Context()), tournamentID, req)
+	if err != nil {
+		respondTournamentError(w, err, "Failed to add entry")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, entry)
+}
+
+// RemoveEntry handles DELETE /api/tournaments/{tournamentID}/entries/{entryID} (Organizer only)
+func (h *TournamentHandler) RemoveEntry(w http.ResponseWriter, r *http.Request) {
+	tournamentID, ok := tournamentURLParam(w, r)
+	if !ok {
+		return
+	}
+	entryID, ok := entryURLParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.tournamentService.RemoveEntry(ActorFrom(r), getClubID(r.Context()), tournamentID, entryID); err != nil {
+		respondTournamentError(w, err, "Failed to remove entry")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Entry removed"})
+}
+
+// Start handles POST /api/tournaments/{tournamentID}/start (Organizer only)
+// Seeds the entries and draws the bracket
+func (h *TournamentHandler) Start(w http.ResponseWriter, r *http.Request) {
+	tournamentID, ok := tournamentURLParam(w, r)
+	if !ok {
+		return
+	}
+
+	bracket, err := h.tournamentService.Start(ActorFrom(r), getClubID(r.Context()), tournamentID)
+	if err != nil {
+		respondTournamentError(w, err, "Failed to start tournament")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, bracket)
+}
+
+// StartMatch handles POST /api/tournaments/{tournamentID}/matches/{bracketMatchID}/start (Organizer only)
+// Puts a ready bracket match on a court; its result is recorded like any other match
+func (h *TournamentHandler) StartMatch(w http.ResponseWriter, r *http.Request) {
+	tournamentID, ok := tournamentURLParam(w, r)
+	if !ok {
+		return
+	}
+	bracketMatchID, ok := bracketMatchURLParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req model.StartBracketMatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	match, err := h.tournamentService.StartMatch(ActorFrom(r), getClubID(r.Context()), tournamentID, bracketMatchID, req.Court)
+	if err != nil {
+		switch err {
+		case service.ErrCourtNotFound:
+			respondError(w, http.StatusBadRequest, "Unknown court", "Court must be registered in the court list")
+		case service.ErrCourtInactive:
+			respondError(w, http.StatusConflict, "Court is under maintenance", "")
+		case service.ErrCourtBusy:
+			respondError(w, http.StatusConflict, "Court already has a match in progress", "")
+		case service.ErrNotClubMember:
+			respondError(w, http.StatusConflict, "Entry has players who left the club", "")
+		default:
+			respondTournamentError(w, err, "Failed to start bracket match")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, match)
+}
+
+// StartFixture handles POST /api/tournaments/{tournamentID}/fixtures/{fixtureID}/start (Organizer only)
+// Puts a round-robin fixture on its scheduled court, or the given one
+func (h *TournamentHandler) StartFixture(w http.ResponseWriter, r *http.Request) {
+	tournamentID, ok := tournamentURLParam(w, r)
+	if !ok {
+		return
+	}
+	fixtureID, err := strconv.ParseInt(chi.URLParam(r, "fixtureID"), 10, 64)
+	if err != nil || fixtureID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid fixture ID", "")
+		return
+	}
+
+	var req model.StartBracketMatchRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+			return
+		}
+	}
+
+	match, err := h.tournamentService.StartFixture(ActorFrom(r), getClubID(r.Context()), tournamentID, fixtureID, req.Court)
+	if err != nil {
+		switch err {
+		case service.ErrCourtNotFound:
+			respondError(w, http.StatusBadRequest, "Unknown court", "Court must be registered in the court list")
+		case service.ErrCourtInactive:
+			respondError(w, http.StatusConflict, "Court is under maintenance", "Pass another court to play the fixture elsewhere")
+		case service.ErrCourtBusy:
+			respondError(w, http.StatusConflict, "Court already has a match in progress", "Pass another court to play the fixture elsewhere")
+		case service.ErrNotClubMember:
+			respondError(w, http.StatusConflict, "Entry has players who left the club", "")
+		default:
+			respondTournamentError(w, err, "Failed to start fixture")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, match)
+}
+
+func tournamentURLParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	tournamentID, err := strconv.ParseInt(chi.URLParam(r, "tournamentID"), 10, 64)
+	if err != nil || tournamentID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid tournament ID", "")
+		return 0, false
+	}
+	return tournamentID, true
+}
+
+func entryURLParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	entryID, err := strconv.ParseInt(chi.URLParam(r, "entryID"), 10, 64)
+	if err != nil || entryID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid entry ID", "")
+		return 0, false
+	}
+	return entryID, true
+}
+
+func bracketMatchURLParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	bracketMatchID, err := strconv.ParseInt(chi.URLParam(r, "bracketMatchID"), 10, 64)
+	if err != nil || bracketMatchID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid bracket match ID", "")
+		return 0, false
+	}
+	return bracketMatchID, true
+}
+
+func respondTournamentError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case service.ErrTournamentNotFound:
+		respondError(w, http.StatusNotFound, "Tournament not found", "")
+	case service.ErrEntryNotFound:
+		respondError(w, http.StatusNotFound, "Entry not found", "")
+	case service.ErrBracketMatchNotFound:
+		respondError(w, http.StatusNotFound, "Bracket match not found", "")
+	case service.ErrFixtureNotFound:
+		respondError(w, http.StatusNotFound, "Fixture not found", "")
+	case service.ErrTournamentNameMissing:
+		respondError(w, http.StatusBadRequest, "Tournament name is required", "")
+	case service.ErrInvalidFormat:
+		respondError(w, http.StatusBadRequest, "Invalid format", "Use single_elimination, double_elimination or round_robin")
+	case service.ErrInvalidEntryType:
+		respondError(w, http.StatusBadRequest, "Invalid entry type", "Use singles or pairs")
+	case service.ErrInvalidSeeding:
+		respondError(w, http.StatusBadRequest, "Invalid seeding", "Use rating or skill_tier")
+	case service.ErrInvalidGroups:
+		respondError(w, http.StatusBadRequest, "Invalid groups", "Must be at least 1")
+	case service.ErrInvalidSlotLength:
+		respondError(w, http.StatusBadRequest, "Invalid slot length", "slot_minutes must be positive")
+	case service.ErrNoCourts:
+		respondError(w, http.StatusConflict, "No courts to schedule on", "Register a court or pass courts when creating the tournament")
+	case service.ErrCourtNotFound:
+		respondError(w, http.StatusBadRequest, "Unknown court", "Court must be registered in the court list")
+	case service.ErrCourtInactive:
+		respondError(w, http.StatusConflict, "Court is under maintenance", "")
+	case service.ErrInvalidScoringRules:
+		respondError(w, http.StatusBadRequest, "Invalid scoring rules",
+			"points_to_win must be positive, point_cap at least points_to_win, and best_of an odd number")
+	case service.ErrInvalidEntry:
+		respondError(w, http.StatusBadRequest, "Invalid entry", "Singles entries have one player and pairs two different players")
+	case service.ErrNotClubMember:
+		respondError(w, http.StatusBadRequest, "Invalid entry", "All players must be members of the club")
+	case service.ErrAlreadyEntered:
+		respondError(w, http.StatusConflict, "Player is already entered", "")
+	case service.ErrTooFewEntries:
+		respondError(w, http.StatusConflict, "Not enough entries", "Single elimination needs 2 entries, double elimination 3 and round robin 2 per group")
+	case service.ErrTournamentStarted:
+		respondError(w, http.StatusConflict, "Tournament has already started", "")
+	case service.ErrTournamentNotRunning:
+		respondError(w, http.StatusConflict, "Tournament is not in progress", "")
+	case service.ErrFixtureStarted:
+		respondError(w, http.StatusConflict, "Fixture has already been started", "")
+	case service.ErrBracketMatchNotReady:
+		respondError(w, http.StatusConflict, "Bracket match is not ready", "Both entries must be known and the match not yet started")
+	default:
+		respondError(w, http.StatusInternalServerError, fallback, err.Error())
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 )
 
 // ContextKey type for context values
@@ -15,21 +16,26 @@ type ContextKey string
 const (
 	// UserContextKey is the key for user info in context
 	UserContextKey ContextKey = "user"
+	// ClubContextKey is the key for the request's club membership in context
+	ClubContextKey ContextKey = "club"
 )
 
 // UserHandler handles user profile endpoints
 type UserHandler struct {
-	userService *service.UserService
+	userService   *service.UserService
+	seasonService *service.SeasonService
 }
 
 // NewUserHandler creates a new user handler
-func NewUserHandler(userSvc *service.UserService) *UserHandler {
+func NewUserHandler(userSvc *service.UserService, seasonSvc *service.SeasonService) *UserHandler {
 	return &UserHandler{
-		userService: userSvc,
+		userService:   userSvc,
+		seasonService: seasonSvc,
 	}
 }
 
 // GetProfile handles GET /api/profile
+// Pass ?season={id} for the season's record and rating instead of the all-time stats
 func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	payload := getUserFromContext(r.Context())
 	if payload == nil {
@@ -43,9 +49,48 @@ func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.applySeason(w, r, 0, profile) {
+		return
+	}
+
 	respondJSON(w, http.StatusOK, profile)
 }
 
+// applySeason swaps a profile's stats for its season record when ?season= is given,
+// responding with an error and returning false when that fails. Club 0 allows any club's season.
+func (h *UserHandler) applySeason(w http.ResponseWriter, r *http.Request, clubID int64, profile *model.UserProfile) bool {
+	v := r.URL.Query().Get("season")
+	if v == "" {
+		return true
+	}
+
+	seasonID, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || seasonID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid season ID", "")
+		return false
+	}
+
+	season, err := h.seasonService.Get(clubID, seasonID)
+	if err == service.ErrSeasonNotFound {
+		respondError(w, http.StatusNotFound, "Season not found", "")
+		return false
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get season", err.Error())
+		return false
+	}
+
+	record, err := h.seasonService.PlayerStats(*season, profile.User.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get season stats", err.Error())
+		return false
+	}
+
+	profile.Stats = record.UserStats
+	profile.Season = season
+	return true
+}
+
 // UpdateProfile handles PUT /api/profile
 func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	payload := getUserFromContext(r.Context())
@@ -86,7 +131,25 @@ func (h *UserHandler) GetStats(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, stats)
 }
 
+// GetRatingHistory handles GET /api/profile/ratings
+func (h *UserHandler) GetRatingHistory(w http.ResponseWriter, r *http.Request) {
+	payload := getUserFromContext(r.Context())
+	if payload == nil {
+		respondError(w, http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+
+	history, err := h.userService.GetRatingHistory(payload.UserID, 50)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get rating history", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, history)
+}
+
 // GetUserProfile handles GET /api/users/{userID}/profile (Admin only)
+// Pass ?season={id} for one of the club's seasons
 func (h *UserHandler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
 	payload := getUserFromContext(r.Context())
 	if payload == nil {
@@ -94,8 +157,8 @@ func (h *UserHandler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if user is admin or organizer
-	if payload.Role != "admin" && payload.Role != "organizer" {
+	// Check if user is admin or organizer of the club
+	if !hasClubRole(r.Context(), model.RoleAdmin, model.RoleOrganizer) {
 		respondError(w, http.StatusForbidden, "Admin access required", "")
 		return
 	}
@@ -113,15 +176,57 @@ func (h *UserHandler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	profile, err := h.userService.GetProfile(userID)
-	if err != nil {
+	clubID := getClubID(r.Context())
+	profile, err := h.userService.MemberProfile(clubID, userID)
+	if err == service.ErrUserNotFound {
 		respondError(w, http.StatusNotFound, "User not found", "")
 		return
 	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get profile", err.Error())
+		return
+	}
+
+	if !h.applySeason(w, r, clubID, profile) {
+		return
+	}
 
 	respondJSON(w, http.StatusOK, profile)
 }
 
+// RecomputeStats handles POST /api/admin/stats/recompute (platform admin only)
+// Rebuilds every player's stats from match history, or one player's with ?user_id=
+func (h *UserHandler) RecomputeStats(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query().Get("user_id")
+	if v == "" {
+		result, err := h.userService.RecomputeAllStats(ActorFrom(r))
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to recompute stats", err.Error())
+			return
+		}
+		respondJSON(w, http.StatusOK, result)
+		return
+	}
+
+	userID, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || userID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid user ID", "")
+		return
+	}
+
+	stats, err := h.userService.RecomputeStats(ActorFrom(r), userID)
+	if err == service.ErrUserNotFound {
+		respondError(w, http.StatusNotFound, "User not found", "")
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to recompute stats", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, stats)
+}
+
 // getUserFromContext extracts user payload from request context
 func getUserFromContext(ctx context.Context) *model.TokenPayload {
 	payload, ok := ctx.Value(UserContextKey).(*model.TokenPayload)
@@ -130,3 +235,35 @@ func getUserFromContext(ctx context.Context) *model.TokenPayload {
 	}
 	return payload
 }
+
+// getClubFromContext returns the caller's membership in the request's club.
+// Role is empty when the caller is not a member.
+func getClubFromContext(ctx context.Context) *model.ClubMember {
+	member, ok := ctx.Value(ClubContextKey).(*model.ClubMember)
+	if !ok {
+		return nil
+	}
+	return member
+}
+
+// getClubID returns the ID of the request's club, or 0 outside a club scope
+func getClubID(ctx context.Context) int64 {
+	if member := getClubFromContext(ctx); member != nil {
+		return member.ClubID
+	}
+	return 0
+}
+
+// hasClubRole reports whether the caller holds one of the roles in the request's club
+func hasClubRole(ctx context.Context, roles ...model.Role) bool {
+	member := getClubFromContext(ctx)
+	if member == nil {
+		return false
+	}
+	for _, role := range roles {
+		if member.Role == role {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"backend/config"
+	"backend/database"
+	"backend/database/migrations"
 	"backend/handler"
 	"backend/middleware"
 	"backend/model"
@@ -20,7 +22,6 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	chiMiddleware "github.com/go-chi/chi/v5/middleware"
-	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
 func main() {
@@ -28,24 +29,68 @@ func main() {
 	cfg := config.Load()
 
 	// Connect to database
-	db, err := connectDB(cfg)
+	db, err := database.Open(cfg)
 	if err != nil {
 		log.Fatalf("❌ Failed to connect to database: %v", err)
 	}
 	defer db.Close()
 	log.Println("✓ Connected to PostgreSQL database")
 
+	// `migrate` subcommand manages the schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(db, os.Args[2:]); err != nil {
+			log.Fatalf("❌ Migration failed: %v", err)
+		}
+		return
+	}
+
+	// `recompute-stats` subcommand rebuilds stats from match history and exits
+	if len(os.Args) > 1 && os.Args[1] == "recompute-stats" {
+		if err := runRecompute(db, os.Args[2:]); err != nil {
+			log.Fatalf("❌ Recompute failed: %v", err)
+		}
+		return
+	}
+
+	if cfg.Database.AutoMigrate {
+		if err := runMigrate(db, []string{"up"}); err != nil {
+			log.Fatalf("❌ Migration failed: %v", err)
+		}
+	}
+
 	// Initialize services with database
-	authService := service.NewAuthService(cfg, db)
-	userService := service.NewUserService(authService, db)
-	queueService := service.NewQueueService(db)
-	matchService := service.NewMatchService(userService, db)
+	store := database.NewPostgresStore(db)
+	notifier, err := service.NewNotifier(cfg.Notify.Channel)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	audit := service.NewAuditLog(store)
+	authService := service.NewAuthService(cfg, store, notifier, audit)
+	userService := service.NewUserService(authService, audit, store)
+	seasonService := service.NewSeasonService(cfg, audit, store)
+	clubService := service.NewClubService(audit, store)
+	events := service.NewEventBroker()
+	courtService := service.NewCourtService(events, audit, store)
+	sessionService := service.NewSessionService(courtService, events, audit, store)
+	queueService := service.NewQueueService(courtService, sessionService, events, audit, store)
+	matchService := service.NewMatchService(userService, queueService, courtService, sessionService, events, audit, store)
+	matchService.SetDefaultAutoDispatch(cfg.Queue.AutoDispatch)
+	leaderboardService := service.NewLeaderboardService(seasonService, store)
+	tournamentService := service.NewTournamentService(matchService, audit, store)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService)
-	userHandler := handler.NewUserHandler(userService)
-	queueHandler := handler.NewQueueHandler(queueService)
-	matchHandler := handler.NewMatchHandler(matchService)
+	userHandler := handler.NewUserHandler(userService, seasonService)
+	queueHandler := handler.NewQueueHandler(queueService, sessionService)
+	matchHandler := handler.NewMatchHandler(matchService, sessionService)
+	courtHandler := handler.NewCourtHandler(courtService)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+	auditHandler := handler.NewAuditHandler(audit)
+	clubHandler := handler.NewClubHandler(clubService, userService)
+	eventHandler := handler.NewEventHandler(events)
+	leaderboardHandler := handler.NewLeaderboardHandler(leaderboardService)
+	seasonHandler := handler.NewSeasonHandler(seasonService)
+	tournamentHandler := handler.NewTournamentHandler(tournamentService)
 
 	// Initialize rate limiters
 	authRateLimiter := middleware.StrictRateLimit()
@@ -58,7 +103,7 @@ func main() {
 	r.Use(chiMiddleware.Logger)
 	r.Use(chiMiddleware.Recoverer)
 	r.Use(chiMiddleware.RequestID)
-	r.Use(chiMiddleware.RealIP)
+	r.Use(middleware.RealIP(cfg))
 	r.Use(middleware.NewCORS(cfg))
 
 	// Health check
@@ -96,11 +141,45 @@ func main() {
 
 		// Auth
 		r.Post("/api/logout", authHandler.Logout)
+		r.Post("/api/events/ticket", authHandler.StreamTicket)
 
 		// Profile
 		r.Get("/api/profile", userHandler.GetProfile)
 		r.Put("/api/profile", userHandler.UpdateProfile)
 		r.Get("/api/profile/stats", userHandler.GetStats)
+		r.Get("/api/profile/ratings", userHandler.GetRatingHistory)
+		r.Put("/api/profile/password", authHandler.ChangePassword)
+
+		// Signed-in devices
+		r.Get("/api/devices", authHandler.ListDevices)
+		r.Delete("/api/devices", authHandler.RevokeDevices)
+		r.Delete("/api/devices/{deviceID}", authHandler.RevokeDevice)
+
+		// Clubs
+		r.Get("/api/clubs", clubHandler.List)
+		r.Post("/api/clubs", clubHandler.Create)
+		r.Get("/api/invites", clubHandler.ListInvites)
+		r.Post("/api/invites/{clubID}/accept", clubHandler.AcceptInvite)
+		r.Delete("/api/invites/{clubID}", clubHandler.DeclineInvite)
+	})
+
+	// Club member routes. The club comes from X-Club-ID or ?club=, defaulting to the default club.
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RateLimit(apiRateLimiter))
+		r.Use(middleware.Auth(authService))
+		r.Use(middleware.ClubScope(clubService))
+		r.Use(middleware.RequireRole(model.RolePlayer, model.RoleOrganizer, model.RoleAdmin))
+
+		r.Get("/api/clubs/{clubID}/stats", clubHandler.GetStats)
+		r.Get("/api/leaderboard", leaderboardHandler.Get)
+
+		// Seasons
+		r.Get("/api/seasons", seasonHandler.List)
+		r.Get("/api/seasons/{seasonID}", seasonHandler.Get)
+
+		// Tournaments
+		r.Get("/api/tournaments", tournamentHandler.List)
+		r.Get("/api/tournaments/{tournamentID}", tournamentHandler.Get)
 
 		// Queue
 		r.Get("/api/queue", queueHandler.GetStatus)
@@ -110,17 +189,61 @@ func main() {
 		// Matches
 		r.Get("/api/matches", matchHandler.GetHistory)
 		r.Get("/api/matches/active", matchHandler.GetActive)
+
+		// Analytics
+		r.Get("/api/analytics", matchHandler.GetAnalytics)
+		r.Get("/api/analytics/head-to-head", matchHandler.GetHeadToHead)
+
+		// Courts
+		r.Get("/api/courts", courtHandler.List)
+
+		// Sessions
+		r.Get("/api/sessions", sessionHandler.List)
+		r.Get("/api/sessions/{sessionID}", sessionHandler.Get)
 	})
 
-	// Organizer/Admin routes
+	// Organizer/Admin routes (role within the request's club)
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.RateLimit(apiRateLimiter))
 		r.Use(middleware.Auth(authService))
+		r.Use(middleware.ClubScope(clubService))
 		r.Use(middleware.RequireRole(model.RoleOrganizer, model.RoleAdmin))
 
 		r.Post("/api/queue/call", queueHandler.CallNext)
 		r.Post("/api/matches", matchHandler.Create)
 		r.Put("/api/matches/result", matchHandler.RecordResult)
+		r.Put("/api/matches/{matchID}/scores", matchHandler.CorrectResult)
+		r.Post("/api/matches/{matchID}/void", matchHandler.VoidMatch)
+		r.Get("/api/matches/{matchID}/corrections", matchHandler.GetCorrections)
+		r.Post("/api/matches/balance", matchHandler.BalanceTeams)
+		r.Get("/api/matches/auto-dispatch", matchHandler.GetAutoDispatch)
+		r.Put("/api/matches/auto-dispatch", matchHandler.SetAutoDispatch)
+
+		// Court registry
+		r.Post("/api/courts", courtHandler.Create)
+		r.Put("/api/courts/{courtID}", courtHandler.Update)
+		r.Delete("/api/courts/{courtID}", courtHandler.Delete)
+
+		// Play sessions
+		r.Post("/api/sessions", sessionHandler.Create)
+		r.Put("/api/sessions/{sessionID}", sessionHandler.Update)
+		r.Post("/api/sessions/{sessionID}/open", sessionHandler.Open)
+		r.Post("/api/sessions/{sessionID}/close", sessionHandler.Close)
+
+		// Seasons
+		r.Post("/api/seasons", seasonHandler.Start)
+		r.Post("/api/seasons/{seasonID}/end", seasonHandler.End)
+
+		// Tournaments
+		r.Post("/api/tournaments", tournamentHandler.Create)
+		r.Post("/api/tournaments/{tournamentID}/entries", tournamentHandler.AddEntry)
+		r.Delete("/api/tournaments/{tournamentID}/entries/{entryID}", tournamentHandler.RemoveEntry)
+		r.Post("/api/tournaments/{tournamentID}/start", tournamentHandler.Start)
+		r.Post("/api/tournaments/{tournamentID}/matches/{bracketMatchID}/start", tournamentHandler.StartMatch)
+		r.Post("/api/tournaments/{tournamentID}/fixtures/{fixtureID}/start", tournamentHandler.StartFixture)
+
+		// Club members
+		r.Get("/api/clubs/{clubID}/members", clubHandler.Members)
 
 		// Admin: View other users' profile and match history
 		r.Get("/api/users/profile", userHandler.GetUserProfile)
@@ -128,7 +251,8 @@ func main() {
 
 		// Admin: Get all completed matches
 		r.Get("/api/admin/matches/completed", func(w http.ResponseWriter, r *http.Request) {
-			matches, err := matchService.GetAllCompleted(100)
+			sessionID, _ := strconv.ParseInt(r.URL.Query().Get("session"), 10, 64)
+			matches, err := matchService.GetAllCompleted(clubFromContext(r), sessionID, 100)
 			if err != nil {
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusInternalServerError)
@@ -141,7 +265,7 @@ func main() {
 
 		// Admin: Get all users
 		r.Get("/api/admin/users", func(w http.ResponseWriter, r *http.Request) {
-			users, err := authService.GetAllUsers()
+			users, err := authService.GetAllUsers(clubFromContext(r))
 			if err != nil {
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusInternalServerError)
@@ -171,7 +295,7 @@ func main() {
 				return
 			}
 
-			if err := authService.UpdatePlayerAdmin(userID, req); err != nil {
+			if err := authService.UpdatePlayerAdmin(handler.ActorFrom(r), clubFromContext(r), userID, req); err != nil {
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusBadRequest)
 				json.NewEncoder(w).Encode(model.NewErrorResponse(400, "Failed to update player", err.Error()))
@@ -183,12 +307,52 @@ func main() {
 		})
 	})
 
+	// Club admin routes
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RateLimit(apiRateLimiter))
+		r.Use(middleware.Auth(authService))
+		r.Use(middleware.ClubScope(clubService))
+		r.Use(middleware.RequireRole(model.RoleAdmin))
+
+		r.Put("/api/clubs/{clubID}/members", clubHandler.SetMember)
+		r.Post("/api/clubs/{clubID}/invites", clubHandler.Invite)
+		r.Delete("/api/clubs/{clubID}/members/{userID}", clubHandler.RemoveMember)
+
+		r.Get("/api/admin/audit", auditHandler.List)
+	})
+
+	// Platform admin routes. Accounts span clubs, so the account role is checked.
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RateLimit(apiRateLimiter))
+		r.Use(middleware.Auth(authService))
+		r.Use(middleware.RequireRole(model.RoleAdmin))
+
+		r.Put("/api/admin/users/{userID}/role", authHandler.SetUserRole)
+		r.Put("/api/admin/users/{userID}/status", authHandler.SetUserStatus)
+		r.Delete("/api/admin/users/{userID}", authHandler.DeleteUser)
+		r.Post("/api/admin/users/{userID}/unlock", authHandler.UnlockUser)
+		r.Get("/api/admin/login-events", authHandler.LoginEvents)
+		r.Get("/api/admin/users/{userID}/devices", authHandler.ListUserDevices)
+		r.Delete("/api/admin/users/{userID}/devices", authHandler.RevokeUserDevices)
+		r.Delete("/api/admin/users/{userID}/devices/{deviceID}", authHandler.RevokeUserDevice)
+		r.Post("/api/admin/stats/recompute", userHandler.RecomputeStats)
+	})
+
 	// Queue status (optional auth for personalized info)
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.OptionalAuth(authService))
+		r.Use(middleware.ClubScope(clubService))
 		r.Get("/api/queue/status", queueHandler.GetStatus)
 	})
 
+	// Real-time updates (Server-Sent Events) for club members
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.StreamAuth(authService))
+		r.Use(middleware.ClubScope(clubService))
+		r.Use(middleware.RequireRole(model.RolePlayer, model.RoleOrganizer, model.RoleAdmin))
+		r.Get("/api/events", eventHandler.Stream)
+	})
+
 	// Create server
 	server := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
@@ -234,36 +398,93 @@ func main() {
 	log.Println("Server exited")
 }
 
-func connectDB(cfg *config.Config) (*sql.DB, error) {
-	// Use PostgreSQL URI format instead
-	connStr := fmt.Sprintf(
-		"postgresql://%s:%s@%s:%s/%s?sslmode=%s",
-		cfg.Database.User,
-		cfg.Database.Password,
-		cfg.Database.Host,
-		cfg.Database.Port,
-		cfg.Database.Name,
-		cfg.Database.SSLMode,
-	)
-
-	db, err := sql.Open("pgx", connStr)
-	if err != nil {
-		return nil, err
+// clubFromContext returns the club ID resolved by middleware.ClubScope
+func clubFromContext(r *http.Request) int64 {
+	if member, ok := r.Context().Value(handler.ClubContextKey).(*model.ClubMember); ok && member != nil {
+		return member.ClubID
 	}
+	return 0
+}
 
-	// Configure connection pool
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(time.Hour)
-	db.SetConnMaxIdleTime(30 * time.Minute)
+// runRecompute rebuilds player stats, ratings and rating history from match history.
+// Usage: recompute-stats [userID]
+func runRecompute(db *sql.DB, args []string) error {
+	users := service.NewUserService(nil, nil, database.NewPostgresStore(db))
 
-	// Test connection
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	if len(args) == 0 {
+		result, err := users.RecomputeAllStats(model.Actor{})
+		if err != nil {
+			return err
+		}
+		log.Printf("✓ Recomputed stats for %d players from %d matches", result.Players, result.Matches)
+		return nil
+	}
 
-	if err := db.PingContext(ctx); err != nil {
-		return nil, err
+	userID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || userID <= 0 {
+		return fmt.Errorf("invalid user ID %q", args[0])
+	}
+	stats, err := users.RecomputeStats(model.Actor{}, userID)
+	if err != nil {
+		return err
 	}
+	log.Printf("✓ Recomputed stats for user %d: %d matches, rating %.0f", userID, stats.TotalMatches, stats.Rating)
+	return nil
+}
 
-	return db, nil
+// runMigrate applies or rolls back schema migrations.
+// Usage: migrate [up | down [steps] | status]
+func runMigrate(db *sql.DB, args []string) error {
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			log.Printf("✓ Applied migration %04d_%s", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			log.Println("✓ Schema is up to date")
+		}
+		return err
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid step count %q", args[1])
+			}
+		}
+		rolledBack, err := migrator.Down(ctx, steps)
+		for _, m := range rolledBack {
+			log.Printf("✓ Rolled back migration %04d_%s", m.Version, m.Name)
+		}
+		return err
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, st := range statuses {
+			state := "pending"
+			if st.Applied {
+				state = "applied " + st.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%-20s %s\n", st.Version, st.Name, state)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown migrate command %q (want up, down or status)", command)
+	}
 }
@@ -7,7 +7,10 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
+
+	"github.com/go-chi/chi/v5"
 )
 
 // Auth creates an authentication middleware
@@ -33,7 +36,7 @@ func Auth(authService *service.AuthService) func(next http.Handler) http.Handler
 			// Validate token
 			payload, err := authService.ValidateToken(token)
 			if err != nil {
-				respondUnauthorized(w, "Invalid or expired token")
+				respondTokenError(w, err)
 				return
 			}
 
@@ -73,7 +76,92 @@ func OptionalAuth(authService *service.AuthService) func(next http.Handler) http
 	}
 }
 
-// RequireRole creates a middleware that requires a specific role
+// StreamAuth authenticates the event stream. Browsers cannot set headers on an
+// EventSource, so besides a bearer token it accepts a single-use ticket from
+// POST /api/events/ticket in the ticket query parameter. Access tokens are never read
+// from the URL, where request logs would keep them.
+func StreamAuth(authService *service.AuthService) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload *model.TokenPayload
+			var err error
+
+			if ticket := r.URL.Query().Get("ticket"); ticket != "" {
+				payload, err = authService.RedeemStreamTicket(ticket)
+			} else if parts := strings.Split(r.Header.Get("Authorization"), " "); len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
+				payload, err = authService.ValidateToken(parts[1])
+			} else {
+				respondUnauthorized(w, "Missing stream ticket")
+				return
+			}
+			if err != nil {
+				respondTokenError(w, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), handler.UserContextKey, payload)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ClubScope resolves the club a request acts on from the {clubID} route parameter,
+// the X-Club-ID header or the club query parameter, falling back to the default club.
+// The caller's membership, with their role in that club, is added to the context;
+// platform admins act as club admins everywhere. Use after Auth or OptionalAuth.
+func ClubScope(clubService *service.ClubService) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clubID := clubService.DefaultID()
+
+			raw := chi.URLParam(r, "clubID")
+			if raw == "" {
+				raw = r.Header.Get("X-Club-ID")
+			}
+			if raw == "" {
+				raw = r.URL.Query().Get("club")
+			}
+			if raw != "" {
+				id, err := strconv.ParseInt(raw, 10, 64)
+				if err != nil || id <= 0 {
+					respondStatus(w, http.StatusBadRequest, "Invalid club ID")
+					return
+				}
+				clubID = id
+			}
+
+			if _, err := clubService.GetByID(clubID); err != nil {
+				respondStatus(w, http.StatusNotFound, "Club not found")
+				return
+			}
+
+			member := &model.ClubMember{ClubID: clubID}
+			if payload, ok := r.Context().Value(handler.UserContextKey).(*model.TokenPayload); ok && payload != nil {
+				m, err := clubService.Membership(clubID, payload.UserID)
+				switch err {
+				case nil:
+					member = m
+				case service.ErrNotClubMember:
+					member.UserID = payload.UserID
+				default:
+					respondStatus(w, http.StatusInternalServerError, "Failed to load club membership")
+					return
+				}
+				if payload.Role == model.RoleAdmin {
+					member.Role = model.RoleAdmin
+				}
+			}
+
+			ctx := context.WithValue(r.Context(), handler.ClubContextKey, member)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireRole creates a middleware that requires one of the given roles.
+// Inside a ClubScope the caller's role in that club is checked, so someone can
+// organize one club while only playing in another; otherwise the account role is used.
+// Platform admins always pass.
 func RequireRole(roles ...model.Role) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -83,8 +171,16 @@ func RequireRole(roles ...model.Role) func(next http.Handler) http.Handler {
 				return
 			}
 
-			for _, role := range roles {
-				if payload.Role == role {
+			role := payload.Role
+			if member, ok := r.Context().Value(handler.ClubContextKey).(*model.ClubMember); ok && member != nil {
+				role = member.Role
+			}
+			if payload.Role == model.RoleAdmin {
+				role = model.RoleAdmin
+			}
+
+			for _, allowed := range roles {
+				if role == allowed {
 					next.ServeHTTP(w, r)
 					return
 				}
@@ -95,6 +191,18 @@ func RequireRole(roles ...model.Role) func(next http.Handler) http.Handler {
 	}
 }
 
+// respondTokenError reports why an access token was rejected
+func respondTokenError(w http.ResponseWriter, err error) {
+	switch err {
+	case service.ErrInvalidToken:
+		respondUnauthorized(w, "Invalid or expired token")
+	case service.ErrAccountDisabled:
+		respondUnauthorized(w, "Account is deactivated")
+	default:
+		respondStatus(w, http.StatusInternalServerError, "Failed to validate token")
+	}
+}
+
 func respondUnauthorized(w http.ResponseWriter, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusUnauthorized)
@@ -106,3 +214,9 @@ func respondForbidden(w http.ResponseWriter, message string) {
 	w.WriteHeader(http.StatusForbidden)
 	json.NewEncoder(w).Encode(model.NewErrorResponse(http.StatusForbidden, message, ""))
 }
+
+func respondStatus(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(model.NewErrorResponse(status, message, ""))
+}
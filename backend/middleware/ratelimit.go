@@ -3,6 +3,7 @@ package middleware
 import (
 	"backend/model"
 	"encoding/json"
+	"net"
 	"net/http"
 	"sync"
 	"time"
@@ -96,20 +97,11 @@ func DefaultRateLimit() *RateLimiter {
 	return NewRateLimiter(rate.Every(600*time.Millisecond), 20)
 }
 
-// getIP extracts the client IP from the request
+// getIP extracts the client IP from the request. RealIP has already resolved it when a
+// trusted proxy forwarded the request; forwarding headers from anyone else are ignored.
 func getIP(r *http.Request) string {
-	// Check X-Forwarded-For header first (for proxies)
-	forwarded := r.Header.Get("X-Forwarded-For")
-	if forwarded != "" {
-		return forwarded
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
 	}
-
-	// Check X-Real-IP header
-	realIP := r.Header.Get("X-Real-IP")
-	if realIP != "" {
-		return realIP
-	}
-
-	// Fall back to RemoteAddr
 	return r.RemoteAddr
 }
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"backend/config"
+)
+
+// RealIP sets RemoteAddr to the client's address when the request came through one of
+// the configured trusted proxies. Forwarding headers from anyone else are ignored, so a
+// caller cannot pick the address recorded in login events, the audit log or rate limits.
+func RealIP(cfg *config.Config) func(next http.Handler) http.Handler {
+	trusted := parseTrustedProxies(cfg.Server.TrustedProxies)
+
+	isTrusted := func(ip net.IP) bool {
+		for _, network := range trusted {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			host := r.RemoteAddr
+			if h, _, err := net.SplitHostPort(host); err == nil {
+				host = h
+			}
+
+			if peer := net.ParseIP(host); peer != nil && isTrusted(peer) {
+				if ip := forwardedFor(r, isTrusted); ip != "" {
+					r.RemoteAddr = ip
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedFor returns the client address reported by trusted proxies: the right-most
+// X-Forwarded-For entry that is not itself a trusted proxy, or X-Real-IP without one
+func forwardedFor(r *http.Request, isTrusted func(net.IP) bool) string {
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		hops := strings.Split(strings.Join(xff, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				// Entries left of a malformed hop were not written by our proxies
+				return ""
+			}
+			if !isTrusted(ip) {
+				return ip.String()
+			}
+		}
+		return ""
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
+
+// parseTrustedProxies turns addresses and CIDR ranges into networks, skipping invalid entries
+func parseTrustedProxies(entries []string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, entry := range entries {
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil {
+				bits := 8 * net.IPv6len
+				if ip.To4() != nil {
+					ip, bits = ip.To4(), 8*net.IPv4len
+				}
+				networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+				continue
+			}
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			log.Printf("ignoring invalid trusted proxy %q", entry)
+			continue
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
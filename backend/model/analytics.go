@@ -0,0 +1,41 @@
+package model
+
+// Record is a win/loss record with points, counted from one player's side
+type Record struct {
+	Matches       int     `json:"matches"`
+	Wins          int     `json:"wins"`
+	Losses        int     `json:"losses"`
+	WinRate       float64 `json:"win_rate"`
+	PointsFor     int     `json:"points_for"`
+	PointsAgainst int     `json:"points_against"`
+	AvgPointDiff  float64 `json:"avg_point_diff"` // Per match
+}
+
+// PairRecord is a player's record with one partner or against one opponent
+type PairRecord struct {
+	UserID int64  `json:"user_id"`
+	Name   string `json:"name"`
+	Record
+}
+
+// PlayerAnalytics breaks a player's club record down by partner and opponent
+type PlayerAnalytics struct {
+	UserID       int64        `json:"user_id"`
+	Overall      Record       `json:"overall"`
+	Partners     []PairRecord `json:"partners"`  // Most matches first
+	Opponents    []PairRecord `json:"opponents"` // Most matches first
+	BestPartner  *PairRecord  `json:"best_partner,omitempty"`
+	WorstPartner *PairRecord  `json:"worst_partner,omitempty"`
+	Nemesis      *PairRecord  `json:"nemesis,omitempty"` // Opponent the player has the worst losing record against
+	MinMatches   int          `json:"min_matches"`       // Needed to be best, worst or nemesis
+}
+
+// HeadToHead compares two players: their record against each other and as partners
+type HeadToHead struct {
+	UserID    int64          `json:"user_id"`
+	OtherID   int64          `json:"other_id"`
+	OtherName string         `json:"other_name"`
+	Against   Record         `json:"against"`  // From the player's side
+	Together  Record         `json:"together"` // As partners
+	Recent    []MatchHistory `json:"recent"`   // Matches they both played, newest first
+}
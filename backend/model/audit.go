@@ -0,0 +1,66 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditAction identifies a privileged change recorded in the audit log
+type AuditAction string
+
+const (
+	AuditMatchCreated      AuditAction = "match.created"
+	AuditMatchResult       AuditAction = "match.result"
+	AuditMatchCorrected    AuditAction = "match.corrected"
+	AuditMatchVoided       AuditAction = "match.voided"
+	AuditStatsRecomputed   AuditAction = "stats.recomputed"
+	AuditAutoDispatch      AuditAction = "match.auto_dispatch"
+	AuditQueueCalled       AuditAction = "queue.called"
+	AuditCourtCreated      AuditAction = "court.created"
+	AuditCourtUpdated      AuditAction = "court.updated"
+	AuditCourtDeleted      AuditAction = "court.deleted"
+	AuditSessionCreated    AuditAction = "session.created"
+	AuditSessionUpdated    AuditAction = "session.updated"
+	AuditSessionOpened     AuditAction = "session.opened"
+	AuditSessionClosed     AuditAction = "session.closed"
+	AuditSeasonStarted     AuditAction = "season.started"
+	AuditSeasonEnded       AuditAction = "season.ended"
+	AuditTournamentCreated AuditAction = "tournament.created"
+	AuditTournamentStarted AuditAction = "tournament.started"
+	AuditEntryAdded        AuditAction = "tournament.entry_added"
+	AuditEntryRemoved      AuditAction = "tournament.entry_removed"
+	AuditClubCreated       AuditAction = "club.created"
+	AuditMemberInvited     AuditAction = "club.member_invited"
+	AuditMemberJoined      AuditAction = "club.member_joined"
+	AuditMemberSet         AuditAction = "club.member_set"
+	AuditMemberRemoved     AuditAction = "club.member_removed"
+	AuditPlayerUpdated     AuditAction = "user.player_updated" // Hand preference and skill tier
+	AuditUserRole          AuditAction = "user.role"
+	AuditUserStatus        AuditAction = "user.status"
+	AuditUserDeleted       AuditAction = "user.deleted"
+	AuditUserUnlocked      AuditAction = "user.unlocked"
+	AuditUserDevicesRevoke AuditAction = "user.devices_revoked"
+)
+
+// Actor identifies who made a privileged change and the request that carried it
+type Actor struct {
+	UserID    int64 // 0 for changes the system makes itself, such as auto-dispatch
+	RequestID string
+	IP        string
+}
+
+// AuditEvent is one recorded privileged change. Before and After hold the target's
+// state as JSON; either is empty when the target did not exist on that side.
+type AuditEvent struct {
+	ID         int64           `json:"id"`
+	ActorID    *int64          `json:"actor_id,omitempty"`
+	ClubID     *int64          `json:"club_id,omitempty"` // Empty for account-wide changes
+	Action     AuditAction     `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   string          `json:"target_id"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	RequestID  string          `json:"request_id"`
+	IP         string          `json:"ip"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"time"
+)
+
+// DefaultClubSlug identifies the club existing single-club data was moved into
+const DefaultClubSlug = "default"
+
+// Club is a tenant that owns its own members, courts, sessions, queue and matches
+type Club struct {
+	ID           int64     `json:"id"`
+	Slug         string    `json:"slug"`
+	Name         string    `json:"name"`
+	AutoDispatch *bool     `json:"auto_dispatch,omitempty"` // Unset follows the server default
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ClubMember is a user's membership in a club. Role applies only within that club.
+type ClubMember struct {
+	ClubID   int64     `json:"club_id"`
+	UserID   int64     `json:"user_id"`
+	Name     string    `json:"name,omitempty"`
+	Username string    `json:"username,omitempty"`
+	Role     Role      `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// ClubMembership is a club as seen by one of its members
+type ClubMembership struct {
+	Club
+	Role Role `json:"role"`
+}
+
+// ClubInvite asks a user to join a club with a role; they become a member only once
+// they accept it
+type ClubInvite struct {
+	ClubID    int64     `json:"club_id"`
+	ClubName  string    `json:"club_name,omitempty"`
+	UserID    int64     `json:"user_id"`
+	Role      Role      `json:"role"`
+	InvitedBy *int64    `json:"invited_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateClubRequest is the payload for creating a club
+type CreateClubRequest struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// ClubMemberRequest invites a user to a club or changes a member's club role
+type ClubMemberRequest struct {
+	UserID int64 `json:"user_id"`
+	Role   Role  `json:"role"`
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+)
+
+// CourtStatus represents whether a court can take matches
+type CourtStatus string
+
+const (
+	CourtStatusActive      CourtStatus = "active"
+	CourtStatusMaintenance CourtStatus = "maintenance"
+)
+
+// CourtSurface represents the playing surface of a court
+type CourtSurface string
+
+const (
+	SurfaceSynthetic CourtSurface = "synthetic" // PU / PVC mat
+	SurfaceWood      CourtSurface = "wood"
+	SurfaceConcrete  CourtSurface = "concrete"
+)
+
+// Court represents a badminton court in the registry
+type Court struct {
+	ID        int64        `json:"id"`
+	Name      string       `json:"name"`
+	Venue     string       `json:"venue"`
+	Status    CourtStatus  `json:"status"`
+	Surface   CourtSurface `json:"surface"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// IsActive checks if the court can be assigned to matches
+func (c *Court) IsActive() bool {
+	return c.Status == CourtStatusActive
+}
+
+// CreateCourtRequest is the payload for registering a court
+type CreateCourtRequest struct {
+	Name    string `json:"name"`
+	Venue   string `json:"venue"`
+	Status  string `json:"status,omitempty"`
+	Surface string `json:"surface,omitempty"`
+}
+
+// UpdateCourtRequest is the payload for updating a court
+type UpdateCourtRequest struct {
+	Name    string `json:"name"`
+	Venue   string `json:"venue"`
+	Status  string `json:"status"`
+	Surface string `json:"surface"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+)
+
+// EventType identifies a real-time update pushed to clients
+type EventType string
+
+const (
+	EventQueueJoined    EventType = "queue.joined"
+	EventQueueLeft      EventType = "queue.left"
+	EventQueueCalled    EventType = "queue.called"
+	EventQueueRequeued  EventType = "queue.requeued"
+	EventMatchCreated   EventType = "match.created"
+	EventMatchCompleted EventType = "match.completed"
+	EventMatchCorrected EventType = "match.corrected" // Scores edited or match voided after completion
+	EventCourtCreated   EventType = "court.created"
+	EventCourtUpdated   EventType = "court.updated"
+	EventCourtDeleted   EventType = "court.deleted"
+	EventSessionOpened  EventType = "session.opened"
+	EventSessionClosed  EventType = "session.closed"
+	EventPlayerCalled   EventType = "player.called"   // Sent only to the called player
+	EventPlayerAssigned EventType = "player.assigned" // Sent only to players put on a court
+)
+
+// Event is a server-push message
+type Event struct {
+	Type   EventType   `json:"type"`
+	Data   interface{} `json:"data"`
+	Time   time.Time   `json:"time"`
+	ClubID int64       `json:"club_id,omitempty"`
+	UserID int64       `json:"-"` // Recipient for personal events, 0 for broadcast
+}
+
+// PlayerNotice is the payload for personal events
+type PlayerNotice struct {
+	Message string  `json:"message"`
+	Court   *string `json:"court,omitempty"`
+	MatchID *int64  `json:"match_id,omitempty"`
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"time"
+)
+
+// LeaderboardSort is the stat players are ranked by
+type LeaderboardSort string
+
+const (
+	SortRating  LeaderboardSort = "rating"
+	SortWinRate LeaderboardSort = "win_rate" // Only players with enough matches are ranked
+	SortWins    LeaderboardSort = "wins"
+	SortStreak  LeaderboardSort = "streak" // Current winning streak
+)
+
+// LeaderboardWindow limits a leaderboard to recent matches
+type LeaderboardWindow string
+
+const (
+	WindowWeek   LeaderboardWindow = "week"   // Last 7 days
+	WindowMonth  LeaderboardWindow = "month"  // Last 30 days
+	WindowSeason LeaderboardWindow = "season" // The club's current season
+	WindowAll    LeaderboardWindow = "all"
+)
+
+// LeaderboardQuery selects and pages a club leaderboard. Empty fields use the defaults.
+type LeaderboardQuery struct {
+	Sort       LeaderboardSort
+	Window     LeaderboardWindow
+	Season     int64 // A past or current season; implies the season window
+	Tier       SkillTier
+	Hand       HandPreference
+	MinMatches int // For win rate; defaults to 5
+	Page       int // 1-based
+	PageSize   int
+}
+
+// LeaderboardEntry is one ranked player with their record over the window
+type LeaderboardEntry struct {
+	Rank           int            `json:"rank"`
+	UserID         int64          `json:"user_id"`
+	Name           string         `json:"name"`
+	HandPreference HandPreference `json:"hand_preference"`
+	SkillTier      SkillTier      `json:"skill_tier"`
+	Matches        int            `json:"matches"`
+	Wins           int            `json:"wins"`
+	Losses         int            `json:"losses"`
+	WinRate        float64        `json:"win_rate"`
+	CurrentStreak  int            `json:"current_streak"` // Positive for wins, negative for losses
+	BestStreak     int            `json:"best_streak"`
+	Rating         float64        `json:"rating"` // After the player's last match in the window, or their season rating
+}
+
+// Leaderboard is one page of ranked players
+type Leaderboard struct {
+	Sort       LeaderboardSort    `json:"sort"`
+	Window     LeaderboardWindow  `json:"window"`
+	Season     *Season            `json:"season,omitempty"`
+	Since      *time.Time         `json:"since,omitempty"`
+	MinMatches int                `json:"min_matches,omitempty"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"page_size"`
+	Total      int                `json:"total"`
+	Entries    []LeaderboardEntry `json:"entries"`
+}
@@ -12,19 +12,25 @@ const (
 	MatchResultTeam1   MatchResult = "team1"
 	MatchResultTeam2   MatchResult = "team2"
 	MatchResultDraw    MatchResult = "draw"
+	MatchResultVoid    MatchResult = "void" // Struck from the record; counts for nobody
 )
 
 // Match represents a badminton game
 type Match struct {
-	ID        int64       `json:"id"`
-	Court     string      `json:"court"`
-	Team1     []int64     `json:"team1"`  // Player IDs
-	Team2     []int64     `json:"team2"`  // Player IDs
-	Scores    []GameScore `json:"scores"` // Score per game
-	Result    string      `json:"result"`
-	StartedAt time.Time   `json:"started_at"`
-	EndedAt   *time.Time  `json:"ended_at,omitempty"`
-	CreatedAt time.Time   `json:"created_at"`
+	ID        int64        `json:"id"`
+	ClubID    int64        `json:"club_id"`
+	SessionID *int64       `json:"session_id,omitempty"`
+	CourtID   *int64       `json:"court_id,omitempty"`
+	Court     string       `json:"court"`  // Court name when the match started; pending matches follow renames
+	Team1     []int64      `json:"team1"`  // Player IDs
+	Team2     []int64      `json:"team2"`  // Player IDs
+	Scores    []GameScore  `json:"scores"` // Score per game
+	Scoring   ScoringRules `json:"scoring"`
+	Result    string       `json:"result"`
+	StartedAt time.Time    `json:"started_at"`
+	EndedAt   *time.Time   `json:"ended_at,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	NextMatch *Match       `json:"next_match,omitempty"` // Set when auto-dispatch refilled the court
 }
 
 // GameScore represents the score of a single game within a match
@@ -42,9 +48,11 @@ type MatchHistory struct {
 
 // CreateMatchRequest is the payload for creating a new match
 type CreateMatchRequest struct {
-	Court string  `json:"court"`
-	Team1 []int64 `json:"team1"`
-	Team2 []int64 `json:"team2"`
+	SessionID int64         `json:"session_id,omitempty"` // Defaults to the open session
+	Court     string        `json:"court"`
+	Team1     []int64       `json:"team1"`
+	Team2     []int64       `json:"team2"`
+	Scoring   *ScoringRules `json:"scoring,omitempty"` // Defaults to BWF rules
 }
 
 // RecordResultRequest is the payload for recording match results
@@ -52,3 +60,75 @@ type RecordResultRequest struct {
 	MatchID int64       `json:"match_id"`
 	Scores  []GameScore `json:"scores"`
 }
+
+// CorrectResultRequest is the payload for editing the scores of a finished match
+type CorrectResultRequest struct {
+	Scores []GameScore `json:"scores"`
+	Reason string      `json:"reason"`
+}
+
+// VoidMatchRequest is the payload for voiding a finished match
+type VoidMatchRequest struct {
+	Reason string `json:"reason"`
+}
+
+// CorrectionKind says how a finished match was corrected
+type CorrectionKind string
+
+const (
+	CorrectionEdit CorrectionKind = "edit"
+	CorrectionVoid CorrectionKind = "void"
+)
+
+// MatchCorrection is one change made to a finished match's result
+type MatchCorrection struct {
+	ID           int64          `json:"id"`
+	MatchID      int64          `json:"match_id"`
+	CorrectedBy  *int64         `json:"corrected_by,omitempty"`
+	Kind         CorrectionKind `json:"kind"`
+	Reason       string         `json:"reason"`
+	ResultBefore string         `json:"result_before"`
+	ResultAfter  string         `json:"result_after"`
+	ScoresBefore []GameScore    `json:"scores_before"`
+	ScoresAfter  []GameScore    `json:"scores_after"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+// AutoDispatchRequest is the payload for toggling auto-dispatch
+type AutoDispatchRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// BalanceTeamsRequest is the payload for proposing balanced teams.
+// When UserIDs is empty the players currently called from the queue are used.
+type BalanceTeamsRequest struct {
+	SessionID int64   `json:"session_id,omitempty"`
+	Court     string  `json:"court,omitempty"`
+	UserIDs   []int64 `json:"user_ids,omitempty"`
+}
+
+// TeamProposal is a balanced pairing that can be submitted as-is to create a match
+type TeamProposal struct {
+	CreateMatchRequest
+	Team1Strength  float64 `json:"team1_strength"`
+	Team2Strength  float64 `json:"team2_strength"`
+	StrengthGap    float64 `json:"strength_gap"`
+	RepeatPartners int     `json:"repeat_partners"` // Recent partnerships kept together
+}
+
+// ScoringRules describes the scoring format a match is played under
+type ScoringRules struct {
+	PointsToWin int `json:"points_to_win"` // Rally point target, 21 under BWF rules
+	PointCap    int `json:"point_cap"`     // Golden point, 30 under BWF rules
+	BestOf      int `json:"best_of"`       // Number of games, 3 under BWF rules
+}
+
+// DefaultScoringRules returns the BWF rally-to-21, best-of-3 format
+func DefaultScoringRules() ScoringRules {
+	return ScoringRules{PointsToWin: 21, PointCap: 30, BestOf: 3}
+}
+
+// GamesToWin returns how many games decide the match
+func (r ScoringRules) GamesToWin() int {
+	return r.BestOf/2 + 1
+}
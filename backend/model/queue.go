@@ -16,26 +16,40 @@ const (
 
 // QueueEntry represents a player's position in the queue
 type QueueEntry struct {
-	ID       int64      `json:"id"`
-	UserID   int64      `json:"user_id"`
-	Position int        `json:"position"`
-	Status   string     `json:"status"`
-	JoinedAt time.Time  `json:"joined_at"`
-	CalledAt *time.Time `json:"called_at,omitempty"`
+	ID        int64      `json:"id"`
+	SessionID *int64     `json:"session_id,omitempty"`
+	UserID    int64      `json:"user_id"`
+	Position  int        `json:"position"`
+	Status    string     `json:"status"`
+	JoinedAt  time.Time  `json:"joined_at"`
+	CalledAt  *time.Time `json:"called_at,omitempty"`
 }
 
 // QueueInfo provides queue status summary
 type QueueInfo struct {
-	TotalInQueue     int          `json:"total_in_queue"`
-	YourPosition     *int         `json:"your_position,omitempty"`
-	EstimatedWait    *string      `json:"estimated_wait,omitempty"`
-	NextCourt        *string      `json:"next_court,omitempty"`
-	CurrentlyPlaying []QueueEntry `json:"currently_playing"`
+	SessionID        *int64        `json:"session_id,omitempty"`
+	TotalInQueue     int           `json:"total_in_queue"`
+	YourPosition     *int          `json:"your_position,omitempty"`
+	EstimatedWait    *WaitEstimate `json:"estimated_wait,omitempty"`
+	NextCourt        *string       `json:"next_court,omitempty"`
+	CurrentlyPlaying []QueueEntry  `json:"currently_playing"`
+}
+
+// WaitEstimate predicts how long until a queued player is put on a court.
+// Low and high bound an 80% range around the estimate.
+type WaitEstimate struct {
+	Minutes      int `json:"minutes"`
+	LowMinutes   int `json:"low_minutes"`
+	HighMinutes  int `json:"high_minutes"`
+	MatchesAhead int `json:"matches_ahead"` // Matches that must start before yours
+	Courts       int `json:"courts"`
+	SampleSize   int `json:"sample_size"` // Completed matches the duration is based on
 }
 
 // JoinQueueRequest is the payload for joining the queue
 type JoinQueueRequest struct {
-	UserID int64 `json:"user_id"`
+	UserID    int64 `json:"user_id"`
+	SessionID int64 `json:"session_id,omitempty"`
 }
 
 // QueueResponse is the response after queue operations
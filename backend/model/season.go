@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+)
+
+// SeasonStatus represents the lifecycle of a season
+type SeasonStatus string
+
+const (
+	SeasonActive SeasonStatus = "active"
+	SeasonEnded  SeasonStatus = "ended"
+)
+
+// Season is a stretch of a club's play with its own records and ratings. Each player
+// starts from their previous rating pulled toward the club mean by the carry-over.
+type Season struct {
+	ID              int64        `json:"id"`
+	ClubID          int64        `json:"club_id"`
+	Name            string       `json:"name"`
+	Status          SeasonStatus `json:"status"`
+	RatingCarryover float64      `json:"rating_carryover"` // 1 keeps ratings, 0 resets everyone to the mean
+	StartedAt       time.Time    `json:"started_at"`
+	EndedAt         *time.Time   `json:"ended_at,omitempty"`
+	StartedBy       *int64       `json:"started_by,omitempty"`
+	EndedBy         *int64       `json:"ended_by,omitempty"`
+}
+
+// StartSeasonRequest is the payload for starting a season
+type StartSeasonRequest struct {
+	Name            string   `json:"name"`
+	RatingCarryover *float64 `json:"rating_carryover,omitempty"` // Defaults to SEASON_RATING_CARRYOVER
+}
+
+// SeasonStats is a player's record and rating within one season. The starting rating is
+// snapshotted when the season starts and the record when it ends.
+type SeasonStats struct {
+	SeasonID        int64   `json:"season_id"`
+	StartRating     float64 `json:"start_rating"`
+	StartDeviation  float64 `json:"start_deviation"`
+	StartVolatility float64 `json:"-"`
+	UserStats
+}
+
+// SeasonStandings is a season with every player's record, best rated first
+type SeasonStandings struct {
+	Season    Season        `json:"season"`
+	Standings []SeasonStats `json:"standings"`
+}
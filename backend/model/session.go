@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+)
+
+// SessionStatus represents the lifecycle of a play session
+type SessionStatus string
+
+const (
+	SessionScheduled SessionStatus = "scheduled"
+	SessionOpen      SessionStatus = "open"
+	SessionClosed    SessionStatus = "closed"
+)
+
+// Session is a play night (e.g. Tuesday evening at a venue) that scopes a queue and its matches
+type Session struct {
+	ID        int64         `json:"id"`
+	ClubID    int64         `json:"club_id"`
+	Name      string        `json:"name"`
+	Venue     string        `json:"venue"`
+	CourtIDs  []int64       `json:"court_ids"` // Courts booked for the session; empty means all active courts
+	Fee       float64       `json:"fee"`       // Per-player fee in THB
+	Scoring   ScoringRules  `json:"scoring"`   // Default format for the session's matches
+	Status    SessionStatus `json:"status"`
+	StartsAt  time.Time     `json:"starts_at"`
+	EndsAt    time.Time     `json:"ends_at"`
+	OpenedAt  *time.Time    `json:"opened_at,omitempty"`
+	ClosedAt  *time.Time    `json:"closed_at,omitempty"`
+	CreatedBy int64         `json:"created_by"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// SessionRequest is the payload for creating or updating a session
+type SessionRequest struct {
+	Name     string        `json:"name"`
+	Venue    string        `json:"venue"`
+	CourtIDs []int64       `json:"court_ids"`
+	Fee      float64       `json:"fee"`
+	Scoring  *ScoringRules `json:"scoring,omitempty"`
+	StartsAt time.Time     `json:"starts_at"`
+	EndsAt   time.Time     `json:"ends_at"`
+}
@@ -18,22 +18,90 @@ func (p *TokenPayload) IsExpired() bool {
 	return time.Now().After(p.ExpiresAt)
 }
 
-// RefreshToken represents a stored refresh token
+// RefreshToken represents a stored refresh token. Only the hash of the token is kept;
+// each refresh replaces the token with a new one in the same family.
 type RefreshToken struct {
-	ID        int64      `json:"id"`
-	UserID    int64      `json:"user_id"`
-	Token     string     `json:"token"`
-	ExpiresAt time.Time  `json:"expires_at"`
-	CreatedAt time.Time  `json:"created_at"`
-	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	TokenHash  string     `json:"-"`
+	FamilyID   string     `json:"family_id"`
+	UserAgent  string     `json:"user_agent"` // Client that last used the family
+	IP         string     `json:"ip"`
+	SignedInAt time.Time  `json:"signed_in_at"` // When the family's first token was issued
+	ExpiresAt  time.Time  `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RotatedAt  *time.Time `json:"rotated_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
 }
 
 // IsValid checks if the refresh token is still valid
 func (r *RefreshToken) IsValid() bool {
-	return r.RevokedAt == nil && time.Now().Before(r.ExpiresAt)
+	return r.RevokedAt == nil && r.RotatedAt == nil && time.Now().Before(r.ExpiresAt)
+}
+
+// Device is one signed-in client, identified by its refresh token family
+type Device struct {
+	ID         string    `json:"id"`
+	SignedInAt time.Time `json:"signed_in_at"`
+	LastUsedAt time.Time `json:"last_used_at"` // Last sign-in or token refresh
+	ExpiresAt  time.Time `json:"expires_at"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	Current    bool      `json:"current"` // The device making the request
+}
+
+// StreamTicket lets a browser open the event stream, since an EventSource cannot send an
+// Authorization header. It is short-lived and works only once, so it is harmless in logs.
+type StreamTicket struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PasswordReset is a single-use code that lets a user set a new password.
+// Only the code's hash is kept, and it dies after a few wrong guesses.
+type PasswordReset struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"user_id"`
+	CodeHash  string     `json:"-"`
+	Attempts  int        `json:"attempts"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
 }
 
 // RefreshTokenRequest is the payload for token refresh
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
+
+// LoginEventKind classifies a recorded login event
+type LoginEventKind string
+
+const (
+	LoginFailed        LoginEventKind = "failed_password"
+	LoginUnknownUser   LoginEventKind = "unknown_user"
+	LoginThrottled     LoginEventKind = "throttled"
+	LoginLocked        LoginEventKind = "account_locked"
+	LoginWhileLocked   LoginEventKind = "locked_attempt"
+	LoginAfterFailures LoginEventKind = "login_after_failures"
+	LoginUnlocked      LoginEventKind = "unlocked"
+)
+
+// LoginEvent records a suspicious or security-relevant login attempt.
+// UserID is nil when the login did not match an account.
+type LoginEvent struct {
+	ID        int64          `json:"id"`
+	UserID    *int64         `json:"user_id,omitempty"`
+	Login     string         `json:"login"`
+	Kind      LoginEventKind `json:"kind"`
+	IP        string         `json:"ip"`
+	UserAgent string         `json:"user_agent"`
+	Detail    string         `json:"detail,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// ClientInfo identifies where a request came from
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
@@ -0,0 +1,194 @@
+package model
+
+import (
+	"time"
+)
+
+// TournamentFormat is how a tournament's bracket eliminates entries
+type TournamentFormat string
+
+const (
+	FormatSingleElimination TournamentFormat = "single_elimination"
+	FormatDoubleElimination TournamentFormat = "double_elimination" // Out after a second loss
+	FormatRoundRobin        TournamentFormat = "round_robin"        // Every entry plays every other in its group
+)
+
+// EntryType is whether tournament entries are individual players or pairs
+type EntryType string
+
+const (
+	EntrySingles EntryType = "singles"
+	EntryPairs   EntryType = "pairs"
+)
+
+// SeedingMethod is how entries are ranked for the bracket
+type SeedingMethod string
+
+const (
+	SeedByRating    SeedingMethod = "rating"
+	SeedBySkillTier SeedingMethod = "skill_tier" // Ties broken by rating
+)
+
+// TournamentStatus represents the lifecycle of a tournament
+type TournamentStatus string
+
+const (
+	TournamentRegistration TournamentStatus = "registration"
+	TournamentInProgress   TournamentStatus = "in_progress"
+	TournamentCompleted    TournamentStatus = "completed"
+)
+
+// Tournament is a club competition played out in an elimination bracket or round-robin groups
+type Tournament struct {
+	ID            int64            `json:"id"`
+	ClubID        int64            `json:"club_id"`
+	Name          string           `json:"name"`
+	Format        TournamentFormat `json:"format"`
+	EntryType     EntryType        `json:"entry_type"`
+	Seeding       SeedingMethod    `json:"seeding"`
+	Scoring       ScoringRules     `json:"scoring"` // Format of every bracket match
+	Status        TournamentStatus `json:"status"`
+	Groups        int              `json:"groups,omitempty"`          // Round robin only
+	Courts        []string         `json:"courts,omitempty"`          // Round robin only; fixtures are spread over these
+	StartsAt      *time.Time       `json:"starts_at,omitempty"`       // Round robin only; when the first slot starts
+	SlotMinutes   int              `json:"slot_minutes,omitempty"`    // Round robin only; length of each time slot
+	WinnerEntryID *int64           `json:"winner_entry_id,omitempty"` // Not set for round robins with several groups
+	CreatedBy     *int64           `json:"created_by,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	StartedAt     *time.Time       `json:"started_at,omitempty"`
+	CompletedAt   *time.Time       `json:"completed_at,omitempty"`
+}
+
+// TournamentEntry is a player or pair registered for a tournament
+type TournamentEntry struct {
+	ID           int64     `json:"id"`
+	TournamentID int64     `json:"tournament_id"`
+	Players      []int64   `json:"players"`
+	Names        []string  `json:"names"`
+	Seed         int       `json:"seed,omitempty"`     // Set when the bracket is drawn, 1 is strongest
+	Strength     float64   `json:"strength,omitempty"` // What the entry was seeded by
+	Group        int       `json:"group,omitempty"`    // Round robin group, from 1
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// BracketSide is the part of a bracket a match belongs to
+type BracketSide string
+
+const (
+	BracketWinners BracketSide = "winners"
+	BracketLosers  BracketSide = "losers" // Double elimination only
+	BracketFinal   BracketSide = "final"  // Double elimination grand final; round 2 is the reset
+)
+
+// BracketMatchStatus represents the progress of a bracket match
+type BracketMatchStatus string
+
+const (
+	BracketPending   BracketMatchStatus = "pending" // Waiting on earlier matches
+	BracketReady     BracketMatchStatus = "ready"   // Both entries known, not on a court yet
+	BracketPlaying   BracketMatchStatus = "playing"
+	BracketCompleted BracketMatchStatus = "completed" // Includes byes, which have no match
+	BracketSkipped   BracketMatchStatus = "skipped"   // Never played, e.g. an unneeded grand final reset
+)
+
+// BracketMatch is one slot in a tournament bracket. The winner moves to NextMatchID and,
+// in double elimination, the loser to LoserNextMatchID; slots are 1 (entry1) or 2 (entry2).
+type BracketMatch struct {
+	ID               int64              `json:"id"`
+	TournamentID     int64              `json:"tournament_id"`
+	Bracket          BracketSide        `json:"bracket"`
+	Round            int                `json:"round"`
+	Position         int                `json:"position"`
+	Entry1ID         *int64             `json:"entry1_id,omitempty"`
+	Entry2ID         *int64             `json:"entry2_id,omitempty"`
+	WinnerEntryID    *int64             `json:"winner_entry_id,omitempty"`
+	LoserEntryID     *int64             `json:"loser_entry_id,omitempty"`
+	MatchID          *int64             `json:"match_id,omitempty"` // Set once the match is put on a court
+	NextMatchID      *int64             `json:"next_match_id,omitempty"`
+	NextSlot         int                `json:"next_slot,omitempty"`
+	LoserNextMatchID *int64             `json:"loser_next_match_id,omitempty"`
+	LoserNextSlot    int                `json:"loser_next_slot,omitempty"`
+	Status           BracketMatchStatus `json:"status"`
+}
+
+// TournamentRequest is the payload for creating a tournament
+type TournamentRequest struct {
+	Name      string           `json:"name"`
+	Format    TournamentFormat `json:"format"`     // Defaults to single elimination
+	EntryType EntryType        `json:"entry_type"` // Defaults to pairs
+	Seeding   SeedingMethod    `json:"seeding"`    // Defaults to rating
+	Scoring   *ScoringRules    `json:"scoring,omitempty"`
+
+	// Round robin scheduling
+	Groups      int        `json:"groups,omitempty"`       // Defaults to 1
+	Courts      []string   `json:"courts,omitempty"`       // Defaults to every active court
+	StartsAt    *time.Time `json:"starts_at,omitempty"`    // Fixtures get times when set
+	SlotMinutes int        `json:"slot_minutes,omitempty"` // Defaults to 30
+}
+
+// TournamentEntryRequest is the payload for registering a player or pair
+type TournamentEntryRequest struct {
+	Players []int64 `json:"players"`
+}
+
+// StartBracketMatchRequest is the payload for putting a ready bracket match or a fixture on a court
+type StartBracketMatchRequest struct {
+	Court string `json:"court"` // Optional for fixtures, which default to their scheduled court
+}
+
+// FixtureStatus represents the progress of a round-robin fixture
+type FixtureStatus string
+
+const (
+	FixtureScheduled FixtureStatus = "scheduled"
+	FixturePlaying   FixtureStatus = "playing"
+	FixtureCompleted FixtureStatus = "completed"
+)
+
+// Fixture is one round-robin game between two entries of a group, scheduled on a court
+// in a time slot. Slots are numbered from 1 across the whole tournament.
+type Fixture struct {
+	ID           int64         `json:"id"`
+	TournamentID int64         `json:"tournament_id"`
+	Group        int           `json:"group"`
+	Round        int           `json:"round"` // Circle method round within the group
+	Slot         int           `json:"slot"`
+	Court        string        `json:"court"`
+	ScheduledAt  *time.Time    `json:"scheduled_at,omitempty"`
+	Entry1ID     int64         `json:"entry1_id"`
+	Entry2ID     int64         `json:"entry2_id"`
+	MatchID      *int64        `json:"match_id,omitempty"` // Set once the fixture is put on a court
+	Status       FixtureStatus `json:"status"`
+}
+
+// Standing is an entry's record in its round-robin group
+type Standing struct {
+	Rank          int      `json:"rank"`
+	EntryID       int64    `json:"entry_id"`
+	Names         []string `json:"names"`
+	Played        int      `json:"played"`
+	Wins          int      `json:"wins"`
+	Losses        int      `json:"losses"`
+	GamesFor      int      `json:"games_for"`
+	GamesAgainst  int      `json:"games_against"`
+	GameDiff      int      `json:"game_diff"`
+	PointsFor     int      `json:"points_for"`
+	PointsAgainst int      `json:"points_against"`
+	PointDiff     int      `json:"point_diff"`
+}
+
+// GroupStandings is a round-robin group ranked by match wins, then game difference,
+// point difference and finally the results between the entries still level
+type GroupStandings struct {
+	Group     int        `json:"group"`
+	Standings []Standing `json:"standings"`
+}
+
+// TournamentBracket is a tournament with its entries and bracket, or fixtures and standings
+type TournamentBracket struct {
+	Tournament Tournament        `json:"tournament"`
+	Entries    []TournamentEntry `json:"entries"`
+	Matches    []BracketMatch    `json:"matches"`             // In order of play: winners, losers, then final rounds
+	Fixtures   []Fixture         `json:"fixtures,omitempty"`  // Round robin only, by slot then court
+	Standings  []GroupStandings  `json:"standings,omitempty"` // Round robin only
+}
@@ -51,6 +51,9 @@ type User struct {
 	AvatarURL      string         `json:"avatar_url,omitempty"`
 	IsActive       bool           `json:"is_active"`
 	LastLoginAt    *time.Time     `json:"last_login_at,omitempty"`
+	FailedLogins   int            `json:"-"` // Admins see lockouts through UserListItem
+	LastFailedAt   *time.Time     `json:"-"`
+	LockedUntil    *time.Time     `json:"-"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 }
@@ -66,12 +69,34 @@ type UserStats struct {
 	BestStreak    int     `json:"best_streak"`
 	SkillLevel    string  `json:"skill_level"` // Beginner, Intermediate, Advanced, Expert
 	SkillPoints   int     `json:"skill_points"`
+	Rating        float64 `json:"rating"`           // Glicko-2 rating, 1500 = unrated
+	RatingDev     float64 `json:"rating_deviation"` // Uncertainty; lower is more certain
+	Volatility    float64 `json:"-"`
+}
+
+// RatingChange records how a match moved a player's rating
+type RatingChange struct {
+	MatchID         int64     `json:"match_id"`
+	UserID          int64     `json:"user_id"`
+	RatingBefore    float64   `json:"rating_before"`
+	RatingAfter     float64   `json:"rating_after"`
+	DeviationBefore float64   `json:"deviation_before"`
+	DeviationAfter  float64   `json:"deviation_after"`
+	Volatility      float64   `json:"-"` // After the match
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// RecomputeResult summarizes a rebuild of every player's stats from match history
+type RecomputeResult struct {
+	Players int `json:"players"`
+	Matches int `json:"matches"`
 }
 
 // UserProfile combines user info with stats
 type UserProfile struct {
-	User  User      `json:"user"`
-	Stats UserStats `json:"stats"`
+	User   User      `json:"user"`
+	Stats  UserStats `json:"stats"`
+	Season *Season   `json:"season,omitempty"` // Set when Stats are for one season
 }
 
 // RegisterRequest is the payload for user registration
@@ -96,6 +121,22 @@ type UpdateProfileRequest struct {
 	Phone string `json:"phone,omitempty"`
 }
 
+// ChangePasswordRequest is the payload for changing a signed-in user's password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// UpdateRoleRequest is for admin to change an account's role
+type UpdateRoleRequest struct {
+	Role Role `json:"role"`
+}
+
+// UpdateStatusRequest is for admin to activate or deactivate an account
+type UpdateStatusRequest struct {
+	IsActive *bool `json:"is_active"`
+}
+
 // UpdatePlayerAdminRequest is for admin to update player settings
 type UpdatePlayerAdminRequest struct {
 	HandPreference string `json:"hand_preference"`
@@ -110,29 +151,29 @@ type AuthResponse struct {
 
 // UserListItem is a simplified user for admin views
 type UserListItem struct {
-	ID             int64   `json:"id"`
-	Username       string  `json:"username"`
-	Name           string  `json:"name"`
-	Phone          string  `json:"phone"`
-	Role           Role    `json:"role"`
-	HandPreference string  `json:"hand_preference"`
-	SkillTier      string  `json:"skill_tier"`
-	IsActive       bool    `json:"is_active"`
-	SkillLevel     string  `json:"skill_level"`
-	WinRate        float64 `json:"win_rate"`
-	TotalMatches   int     `json:"total_matches"`
-	Wins           int     `json:"wins"`
-}
-
-// ForgotPasswordRequest is the payload for password reset
+	ID             int64      `json:"id"`
+	Username       string     `json:"username"`
+	Name           string     `json:"name"`
+	Phone          string     `json:"phone"`
+	Role           Role       `json:"role"`
+	HandPreference string     `json:"hand_preference"`
+	SkillTier      string     `json:"skill_tier"`
+	IsActive       bool       `json:"is_active"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`
+	SkillLevel     string     `json:"skill_level"`
+	WinRate        float64    `json:"win_rate"`
+	TotalMatches   int        `json:"total_matches"`
+	Wins           int        `json:"wins"`
+}
+
+// ForgotPasswordRequest is the payload for requesting a password reset code
 type ForgotPasswordRequest struct {
-	Username string `json:"username"`
-	Phone    string `json:"phone"`
+	Username string `json:"username"` // Username or phone number
 }
 
-// ResetPasswordRequest is the payload for setting new password
+// ResetPasswordRequest is the payload for setting a new password with a reset code
 type ResetPasswordRequest struct {
-	Username    string `json:"username"`
-	Phone       string `json:"phone"`
+	Username    string `json:"username"` // Username or phone number
+	Code        string `json:"code"`
 	NewPassword string `json:"new_password"`
 }
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"backend/database"
+	"backend/model"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrAccountDisabled = errors.New("account is deactivated")
+	ErrLastAdmin       = errors.New("at least one active admin must remain")
+	ErrOwnAccount      = errors.New("admins cannot change their own account this way")
+	ErrUserInMatch     = errors.New("user is playing a match that has not finished")
+	ErrUserHasMatches  = errors.New("user has match history and can only be anonymized")
+)
+
+// SetUserRole changes a user's account role (platform admin only). Club roles are
+// managed per club through club membership.
+func (s *AuthService) SetUserRole(actor model.Actor, userID int64, role model.Role) error {
+	switch role {
+	case model.RolePlayer, model.RoleOrganizer, model.RoleAdmin:
+	default:
+		return ErrInvalidRole
+	}
+	if actor.UserID == userID {
+		return ErrOwnAccount
+	}
+
+	ctx := context.Background()
+	var before model.User
+	err := s.store.WithTx(ctx, func(tx database.Store) error {
+		user, err := lifecycleTarget(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		before = *user
+		if role != model.RoleAdmin {
+			if err := checkNotLastAdmin(ctx, tx, user); err != nil {
+				return err
+			}
+		}
+		return tx.Users().SetRole(ctx, userID, role)
+	})
+	if err != nil {
+		return err
+	}
+
+	after := before
+	after.Role = role
+	s.audit.Record(actor, 0, model.AuditUserRole, "user", userID, accountState(&before), accountState(&after))
+	return nil
+}
+
+// SetUserActive enables or disables an account (platform admin only). Disabled accounts
+// cannot log in, their tokens stop working and they leave every queue they are waiting in.
+func (s *AuthService) SetUserActive(actor model.Actor, userID int64, active bool) error {
+	if actor.UserID == userID {
+		return ErrOwnAccount
+	}
+
+	ctx := context.Background()
+	var before model.User
+	err := s.store.WithTx(ctx, func(tx database.Store) error {
+		user, err := lifecycleTarget(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		before = *user
+		if active {
+			return tx.Users().SetActive(ctx, userID, true)
+		}
+
+		if err := checkNotLastAdmin(ctx, tx, user); err != nil {
+			return err
+		}
+		if err := tx.Users().SetActive(ctx, userID, false); err != nil {
+			return err
+		}
+		if err := tx.Tokens().RevokeAllForUser(ctx, userID); err != nil {
+			return err
+		}
+		return tx.Queue().Withdraw(ctx, userID)
+	})
+	if err != nil {
+		return err
+	}
+
+	after := before
+	after.IsActive = active
+	s.audit.Record(actor, 0, model.AuditUserStatus, "user", userID, accountState(&before), accountState(&after))
+	return nil
+}
+
+// DeleteUser removes an account (platform admin only). Anonymizing keeps the account's
+// matches and stats under a placeholder name; otherwise the account and its stats are
+// deleted outright, which is only allowed for accounts that never played a match since
+// replaying ratings needs every player in the history. Either way the person's details,
+// login history and club memberships are gone, so the last admin of a club cannot be
+// removed.
+func (s *AuthService) DeleteUser(actor model.Actor, userID int64, anonymize bool) error {
+	if actor.UserID == userID {
+		return ErrOwnAccount
+	}
+
+	ctx := context.Background()
+	var before model.User
+	err := s.store.WithTx(ctx, func(tx database.Store) error {
+		user, err := lifecycleTarget(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		before = *user
+		if err := checkNotLastAdmin(ctx, tx, user); err != nil {
+			return err
+		}
+
+		// Recording the result would need the player's stats row
+		pending, err := tx.Matches().PendingFor(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return ErrUserInMatch
+		}
+
+		// Club memberships go with the account either way, so no club may be left without an admin
+		clubs, err := tx.Clubs().ListForUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		for _, c := range clubs {
+			if err := checkNotLastClubAdmin(ctx, tx, c.ID, userID); err != nil {
+				return err
+			}
+		}
+
+		if !anonymize {
+			played, err := tx.Matches().Played(ctx, []int64{userID}, 1)
+			if err != nil {
+				return err
+			}
+			if len(played) > 0 {
+				return ErrUserHasMatches
+			}
+			return tx.Users().Delete(ctx, userID)
+		}
+
+		// Nobody knows this password, so the account can never be logged into again
+		secret, err := randomHex(32)
+		if err != nil {
+			return err
+		}
+		if err := tx.Queue().Withdraw(ctx, userID); err != nil {
+			return err
+		}
+		return tx.Users().Anonymize(ctx, userID, fmt.Sprintf("deleted-%d", userID), s.hashPassword(secret))
+	})
+	if err != nil {
+		return err
+	}
+
+	// Only the account state is kept; the log must not hold on to the deleted details
+	s.audit.Record(actor, 0, model.AuditUserDeleted, "user", userID, accountState(&before),
+		map[string]bool{"anonymized": anonymize})
+	return nil
+}
+
+// accountState is the part of an account recorded in the audit log
+func accountState(user *model.User) map[string]interface{} {
+	return map[string]interface{}{"role": user.Role, "is_active": user.IsActive}
+}
+
+// lifecycleTarget loads the account an admin is changing
+func lifecycleTarget(ctx context.Context, tx database.Store, userID int64) (*model.User, error) {
+	user, err := tx.Users().GetByID(ctx, userID)
+	if err == database.ErrNotFound {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
+
+// checkNotLastAdmin stops the platform from losing its only active admin
+func checkNotLastAdmin(ctx context.Context, tx database.Store, user *model.User) error {
+	if user.Role != model.RoleAdmin || !user.IsActive {
+		return nil
+	}
+	admins, err := tx.Users().CountActiveAdmins(ctx)
+	if err != nil {
+		return err
+	}
+	if admins <= 1 {
+		return ErrLastAdmin
+	}
+	return nil
+}
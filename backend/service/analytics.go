@@ -0,0 +1,223 @@
+package service
+
+import (
+	"backend/database"
+	"backend/model"
+	"context"
+	"errors"
+	"sort"
+)
+
+const (
+	// defaultPairMinMatches is how many matches together make a partner or opponent count
+	// as best, worst or nemesis
+	defaultPairMinMatches = 3
+	// headToHeadRecent is how many shared matches a head-to-head lists
+	headToHeadRecent = 10
+)
+
+var ErrSamePlayer = errors.New("cannot compare a player with themselves")
+
+// PlayerAnalytics returns a player's record in the club's decided matches, broken down by
+// partner and opponent
+func (s *MatchService) PlayerAnalytics(clubID, userID int64, minMatches int) (*model.PlayerAnalytics, error) {
+	if minMatches <= 0 {
+		minMatches = defaultPairMinMatches
+	}
+
+	ctx := context.Background()
+
+	matches, err := s.store.Matches().Decided(ctx, database.MatchFilter{ClubID: clubID, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := &model.PlayerAnalytics{UserID: userID, MinMatches: minMatches}
+	partners := make(map[int64]*model.Record)
+	opponents := make(map[int64]*model.Record)
+	tally := func(records map[int64]*model.Record, id int64, won bool, pointsFor, pointsAgainst int) {
+		if records[id] == nil {
+			records[id] = &model.Record{}
+		}
+		addResult(records[id], won, pointsFor, pointsAgainst)
+	}
+
+	for _, match := range matches {
+		own, other, won, pointsFor, pointsAgainst := sides(match, userID)
+		addResult(&analytics.Overall, won, pointsFor, pointsAgainst)
+		for _, id := range own {
+			if id != userID {
+				tally(partners, id, won, pointsFor, pointsAgainst)
+			}
+		}
+		for _, id := range other {
+			tally(opponents, id, won, pointsFor, pointsAgainst)
+		}
+	}
+
+	names := s.namesByID(ctx, partners, opponents)
+	analytics.Partners = pairRecords(partners, names)
+	analytics.Opponents = pairRecords(opponents, names)
+
+	var qualified []model.PairRecord
+	for _, p := range analytics.Partners {
+		if p.Matches >= minMatches {
+			qualified = append(qualified, p)
+		}
+	}
+	if len(qualified) > 0 {
+		sort.SliceStable(qualified, func(i, j int) bool { return betterRecord(qualified[i], qualified[j]) })
+		analytics.BestPartner = &qualified[0]
+		if len(qualified) > 1 {
+			analytics.WorstPartner = &qualified[len(qualified)-1]
+		}
+	}
+
+	for i, o := range analytics.Opponents {
+		if o.Matches < minMatches || o.Losses <= o.Wins {
+			continue
+		}
+		if analytics.Nemesis == nil || betterRecord(*analytics.Nemesis, o) {
+			analytics.Nemesis = &analytics.Opponents[i]
+		}
+	}
+
+	return analytics, nil
+}
+
+// HeadToHead compares a player with another member of the club across the matches they
+// both played, as opponents and as partners
+func (s *MatchService) HeadToHead(clubID, userID, otherID int64) (*model.HeadToHead, error) {
+	if userID == otherID {
+		return nil, ErrSamePlayer
+	}
+
+	ctx := context.Background()
+
+	other, err := s.store.Users().GetByID(ctx, otherID)
+	if err == database.ErrNotFound {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.store.Users().AllInClub(ctx, clubID, []int64{otherID})
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, ErrUserNotFound
+	}
+
+	matches, err := s.store.Matches().Decided(ctx, database.MatchFilter{ClubID: clubID, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	h2h := &model.HeadToHead{
+		UserID:    userID,
+		OtherID:   otherID,
+		OtherName: other.Name,
+		Recent:    []model.MatchHistory{},
+	}
+
+	// Decided matches come oldest first
+	for i := len(matches) - 1; i >= 0; i-- {
+		match := matches[i]
+		own, opposing, won, pointsFor, pointsAgainst := sides(match, userID)
+		switch {
+		case contains(own, otherID):
+			addResult(&h2h.Together, won, pointsFor, pointsAgainst)
+		case contains(opposing, otherID):
+			addResult(&h2h.Against, won, pointsFor, pointsAgainst)
+		default:
+			continue
+		}
+		if len(h2h.Recent) < headToHeadRecent {
+			h2h.Recent = append(h2h.Recent, model.MatchHistory{Match: match, Won: won})
+		}
+	}
+
+	return h2h, nil
+}
+
+// sides splits a decided match into the player's team and the other team, with the
+// result and points from the player's side
+func sides(match model.Match, userID int64) (own, other []int64, won bool, pointsFor, pointsAgainst int) {
+	own, other = match.Team1, match.Team2
+	won = match.Result == string(model.MatchResultTeam1)
+	for _, score := range match.Scores {
+		pointsFor += score.Team1Score
+		pointsAgainst += score.Team2Score
+	}
+
+	if !contains(match.Team1, userID) {
+		own, other = other, own
+		won = !won
+		pointsFor, pointsAgainst = pointsAgainst, pointsFor
+	}
+	return own, other, won, pointsFor, pointsAgainst
+}
+
+// addResult adds one match to a record and refreshes its rates
+func addResult(record *model.Record, won bool, pointsFor, pointsAgainst int) {
+	record.Matches++
+	if won {
+		record.Wins++
+	} else {
+		record.Losses++
+	}
+	record.PointsFor += pointsFor
+	record.PointsAgainst += pointsAgainst
+
+	record.WinRate = float64(record.Wins) * 100 / float64(record.Matches)
+	record.AvgPointDiff = float64(record.PointsFor-record.PointsAgainst) / float64(record.Matches)
+}
+
+// betterRecord reports whether a is the stronger record: higher win rate, then
+// point differential, then more matches
+func betterRecord(a, b model.PairRecord) bool {
+	if a.WinRate != b.WinRate {
+		return a.WinRate > b.WinRate
+	}
+	if a.AvgPointDiff != b.AvgPointDiff {
+		return a.AvgPointDiff > b.AvgPointDiff
+	}
+	return a.Matches > b.Matches
+}
+
+// pairRecords lists per-player records, most matches first
+func pairRecords(records map[int64]*model.Record, names map[int64]string) []model.PairRecord {
+	pairs := make([]model.PairRecord, 0, len(records))
+	for id, record := range records {
+		pairs = append(pairs, model.PairRecord{UserID: id, Name: names[id], Record: *record})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Matches != pairs[j].Matches {
+			return pairs[i].Matches > pairs[j].Matches
+		}
+		return pairs[i].UserID < pairs[j].UserID
+	})
+	return pairs
+}
+
+// namesByID looks up the names of everyone in the given records
+func (s *MatchService) namesByID(ctx context.Context, groups ...map[int64]*model.Record) map[int64]string {
+	var ids []int64
+	for _, records := range groups {
+		for id := range records {
+			ids = append(ids, id)
+		}
+	}
+
+	names := make(map[int64]string, len(ids))
+	if len(ids) == 0 {
+		return names
+	}
+	if users, err := s.store.Users().GetMany(ctx, ids); err == nil {
+		for _, u := range users {
+			names[u.ID] = u.Name
+		}
+	}
+	return names
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"backend/database"
+	"backend/model"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// defaultAuditLimit bounds audit log listings
+const defaultAuditLimit = 100
+
+// AuditLog records privileged changes. Services record after a change succeeds, so the
+// log never shows a change that was rolled back.
+type AuditLog struct {
+	store database.Store
+}
+
+// NewAuditLog creates an audit log backed by store
+func NewAuditLog(store database.Store) *AuditLog {
+	return &AuditLog{store: store}
+}
+
+// Record stores one change. before and after are the target's state, nil where it did
+// not exist. clubID 0 marks an account-wide change. Failing to record is logged rather
+// than undoing a change that has already been made.
+func (a *AuditLog) Record(actor model.Actor, clubID int64, action model.AuditAction, targetType string, targetID interface{}, before, after interface{}) {
+	// Services may run without an audit log (e.g. CLI tools)
+	if a == nil {
+		return
+	}
+
+	event := model.AuditEvent{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   fmt.Sprint(targetID),
+		Before:     auditJSON(before),
+		After:      auditJSON(after),
+		RequestID:  actor.RequestID,
+		IP:         actor.IP,
+	}
+	if actor.UserID != 0 {
+		event.ActorID = &actor.UserID
+	}
+	if clubID != 0 {
+		event.ClubID = &clubID
+	}
+
+	if err := a.store.Audit().Create(context.Background(), &event); err != nil {
+		log.Printf("failed to record audit event %s on %s %s: %v", action, targetType, event.TargetID, err)
+	}
+}
+
+// List returns recorded changes, newest first
+func (a *AuditLog) List(filter database.AuditFilter) ([]model.AuditEvent, error) {
+	if filter.Limit <= 0 || filter.Limit > defaultAuditLimit {
+		filter.Limit = defaultAuditLimit
+	}
+	return a.store.Audit().List(context.Background(), filter)
+}
+
+// auditJSON encodes a target's state, leaving nil states empty
+func auditJSON(state interface{}) json.RawMessage {
+	if state == nil {
+		return nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		log.Printf("failed to encode audit state: %v", err)
+		return nil
+	}
+	return data
+}
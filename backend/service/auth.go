@@ -2,12 +2,17 @@ package service
 
 import (
 	"backend/config"
+	"backend/database"
 	"backend/model"
 	"context"
+	"crypto/hmac"
 	"crypto/rand"
-	"database/sql"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"fmt"
+	"log"
+	"math/big"
 	"regexp"
 	"strings"
 	"time"
@@ -25,27 +30,43 @@ var (
 	ErrPasswordMatch      = errors.New("passwords do not match")
 	ErrPasswordSameAsUser = errors.New("password cannot be the same as username")
 	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrTokenReused        = errors.New("refresh token was already used")
 	ErrInvalidPhone       = errors.New("invalid phone number format")
 	ErrUserNotFound       = errors.New("user not found")
-	ErrPhoneMismatch      = errors.New("phone number does not match")
+	ErrInvalidResetCode   = errors.New("invalid or expired reset code")
+	ErrLoginThrottled     = errors.New("too many failed logins, try again later")
+	ErrAccountLocked      = errors.New("account is temporarily locked")
+)
+
+const (
+	// maxResetAttempts is how many guesses a password reset code allows
+	maxResetAttempts = 5
+	// maxResetRequests is how many reset codes an account is sent per resetRequestWindow
+	maxResetRequests   = 3
+	resetRequestWindow = time.Hour
 )
 
 // AuthService handles authentication logic
 type AuthService struct {
-	config *config.Config
-	db     *sql.DB
+	config   *config.Config
+	store    database.Store
+	notifier Notifier
+	audit    *AuditLog
+	tickets  *streamTickets
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService(cfg *config.Config, db *sql.DB) *AuthService {
+func NewAuthService(cfg *config.Config, store database.Store, notifier Notifier, audit *AuditLog) *AuthService {
 	svc := &AuthService{
-		config: cfg,
-		db:     db,
+		config:   cfg,
+		store:    store,
+		notifier: notifier,
+		audit:    audit,
+		tickets:  &streamTickets{issued: make(map[string]streamTicket)},
 	}
 
-	if db != nil {
+	if store != nil {
 		svc.ensureAdminExists()
-		svc.ensureTablesUpdated()
 	}
 
 	return svc
@@ -53,24 +74,21 @@ func NewAuthService(cfg *config.Config, db *sql.DB) *AuthService {
 
 func (s *AuthService) ensureAdminExists() {
 	ctx := context.Background()
-	var count int
-	s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users WHERE username = 'kong@admin'").Scan(&count)
-	if count == 0 {
-		hash := s.hashPassword("Admin@123!")
-		s.db.ExecContext(ctx, `
-			INSERT INTO users (username, password_hash, name, phone, bio, role, hand_preference, skill_tier, is_active, created_at, updated_at)
-			VALUES ('kong@admin', $1, 'Super Admin', '0899999999', 'System Administrator', 'admin', 'right', 'A', true, NOW(), NOW())
-		`, hash)
+	if exists, _ := s.store.Users().UsernameExists(ctx, "kong@admin"); !exists {
+		s.store.Users().Create(ctx, &model.User{
+			Username:       "kong@admin",
+			PasswordHash:   s.hashPassword("Admin@123!"),
+			Name:           "Super Admin",
+			Phone:          "0899999999",
+			Bio:            "System Administrator",
+			Role:           model.RoleAdmin,
+			HandPreference: model.HandRight,
+			SkillTier:      model.SkillA,
+			IsActive:       true,
+		})
 	}
 }
 
-func (s *AuthService) ensureTablesUpdated() {
-	ctx := context.Background()
-	// Add new columns if they don't exist
-	s.db.ExecContext(ctx, `ALTER TABLE users ADD COLUMN IF NOT EXISTS hand_preference VARCHAR(10) DEFAULT 'right'`)
-	s.db.ExecContext(ctx, `ALTER TABLE users ADD COLUMN IF NOT EXISTS skill_tier VARCHAR(5) DEFAULT 'N'`)
-}
-
 // Register creates a new user account
 func (s *AuthService) Register(req model.RegisterRequest) (*model.AuthResponse, error) {
 	if req.Password != req.ConfirmPassword {
@@ -87,48 +105,46 @@ func (s *AuthService) Register(req model.RegisterRequest) (*model.AuthResponse,
 
 	ctx := context.Background()
 
-	var existingCount int
-	s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users WHERE username = $1", req.Username).Scan(&existingCount)
-	if existingCount > 0 {
+	users := s.store.Users()
+
+	exists, err := users.UsernameExists(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
 		return nil, ErrUserExists
 	}
 
 	if req.Phone != "" {
-		var phoneCount int
-		s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users WHERE phone = $1", req.Phone).Scan(&phoneCount)
-		if phoneCount > 0 {
+		exists, err := users.PhoneExists(ctx, req.Phone)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
 			return nil, ErrPhoneExists
 		}
 	}
 
-	hash := s.hashPassword(req.Password)
-
 	name := req.Name
 	if name == "" {
 		name = req.Username
 	}
 
-	var user model.User
-	now := time.Now()
-	err := s.db.QueryRowContext(ctx, `
-		INSERT INTO users (username, password_hash, name, phone, bio, role, hand_preference, skill_tier, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, '', 'player', 'right', 'N', true, $5, $5)
-		RETURNING id, username, name, phone, bio, role, hand_preference, skill_tier, is_active, created_at, updated_at
-	`, req.Username, hash, name, req.Phone, now).Scan(
-		&user.ID, &user.Username, &user.Name, &user.Phone, &user.Bio,
-		&user.Role, &user.HandPreference, &user.SkillTier, &user.IsActive, &user.CreatedAt, &user.UpdatedAt,
-	)
-
-	if err != nil {
+	// New players start in the default club; other clubs add them as members
+	user := model.User{
+		Username:       req.Username,
+		PasswordHash:   s.hashPassword(req.Password),
+		Name:           name,
+		Phone:          req.Phone,
+		Role:           model.RolePlayer,
+		HandPreference: model.HandRight,
+		SkillTier:      model.SkillN,
+		IsActive:       true,
+	}
+	if err := users.Create(ctx, &user); err != nil {
 		return nil, err
 	}
 
-	s.db.ExecContext(ctx, `
-		INSERT INTO user_stats (user_id, skill_level, skill_points)
-		VALUES ($1, 'Beginner', 0)
-		ON CONFLICT (user_id) DO NOTHING
-	`, user.ID)
-
 	accessToken, err := s.generateAccessToken(&user)
 	if err != nil {
 		return nil, err
@@ -140,145 +156,286 @@ func (s *AuthService) Register(req model.RegisterRequest) (*model.AuthResponse,
 	}, nil
 }
 
-// Login authenticates a user
-func (s *AuthService) Login(req model.LoginRequest) (*model.AuthResponse, string, error) {
+// Login authenticates a user. Repeated wrong passwords slow down and then lock the
+// account; blocked attempts return a *LoginBlockedError without checking the password.
+func (s *AuthService) Login(req model.LoginRequest, client model.ClientInfo) (*model.AuthResponse, string, error) {
 	ctx := context.Background()
 
-	var user model.User
-	var passwordHash string
-	var handPref, skillTier sql.NullString
-
 	// Allow login with either username or phone number
-	err := s.db.QueryRowContext(ctx, `
-		SELECT id, username, password_hash, name, phone, bio, role, 
-		       COALESCE(hand_preference, 'right'), COALESCE(skill_tier, 'N'), 
-		       is_active, created_at, updated_at
-		FROM users WHERE username = $1 OR phone = $1
-	`, req.Username).Scan(
-		&user.ID, &user.Username, &passwordHash, &user.Name, &user.Phone,
-		&user.Bio, &user.Role, &handPref, &skillTier,
-		&user.IsActive, &user.CreatedAt, &user.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
+	user, err := s.store.Users().GetByLogin(ctx, req.Username)
+	if err == database.ErrNotFound {
+		s.recordLoginEvent(ctx, nil, req.Username, model.LoginUnknownUser, client, "")
 		return nil, "", ErrInvalidCredentials
 	}
 	if err != nil {
 		return nil, "", err
 	}
 
-	user.HandPreference = model.HandPreference(handPref.String)
-	user.SkillTier = model.SkillTier(skillTier.String)
+	failures, err := s.claimLoginAttempt(ctx, user.ID)
+	if blocked, ok := err.(*LoginBlockedError); ok {
+		kind := model.LoginThrottled
+		if blocked.Err == ErrAccountLocked {
+			kind = model.LoginWhileLocked
+		}
+		s.recordLoginEvent(ctx, &user.ID, req.Username, kind, client, "")
+		return nil, "", blocked
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !s.verifyPassword(req.Password, user.PasswordHash) {
+		return nil, "", s.failedLogin(ctx, user, failures, req.Username, client)
+	}
+
+	// Only reveal the account is disabled to someone who knows the password. The right
+	// password still clears the attempts counted against it.
+	if !user.IsActive {
+		s.store.Users().Unlock(ctx, user.ID)
+		return nil, "", ErrAccountDisabled
+	}
 
-	if !s.verifyPassword(req.Password, passwordHash) {
-		return nil, "", ErrInvalidCredentials
+	// This attempt was counted up front; RecordLogin clears the count
+	if failures-1 >= throttleAfterFailures {
+		s.recordLoginEvent(ctx, &user.ID, req.Username, model.LoginAfterFailures, client,
+			fmt.Sprintf("%d failures before success", failures-1))
 	}
 
-	s.db.ExecContext(ctx, "UPDATE users SET last_login_at = NOW() WHERE id = $1", user.ID)
+	s.store.Users().RecordLogin(ctx, user.ID)
+	user.FailedLogins = 0
+	user.LockedUntil = nil
 
-	accessToken, err := s.generateAccessToken(&user)
+	accessToken, err := s.generateAccessToken(user)
 	if err != nil {
 		return nil, "", err
 	}
 
-	refreshToken, err := s.generateRefreshToken(&user)
+	refreshToken, err := s.generateRefreshToken(ctx, s.store, user.ID, nil, client)
 	if err != nil {
 		return nil, "", err
 	}
 
 	return &model.AuthResponse{
 		AccessToken: accessToken,
-		User:        user,
+		User:        *user,
 	}, refreshToken, nil
 }
 
-// VerifyUserForReset verifies username and phone for password reset
-func (s *AuthService) VerifyUserForReset(req model.ForgotPasswordRequest) error {
+// RequestPasswordReset sends a single-use reset code to the account with the given
+// username or phone number. Earlier codes stop working. Unknown accounts, and accounts
+// already sent maxResetRequests codes within resetRequestWindow, are ignored so the
+// response does not reveal which accounts exist.
+func (s *AuthService) RequestPasswordReset(req model.ForgotPasswordRequest) error {
 	ctx := context.Background()
 
-	var storedPhone string
-	err := s.db.QueryRowContext(ctx, `
-		SELECT phone FROM users WHERE username = $1
-	`, req.Username).Scan(&storedPhone)
-
-	if err == sql.ErrNoRows {
-		return ErrUserNotFound
+	user, err := s.store.Users().GetByLogin(ctx, req.Username)
+	if err == database.ErrNotFound {
+		return nil
 	}
 	if err != nil {
 		return err
 	}
 
-	if storedPhone != req.Phone {
-		return ErrPhoneMismatch
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return err
 	}
+	code := fmt.Sprintf("%06d", n.Int64())
+	ttl := s.config.Auth.ResetCodeDuration
 
-	return nil
+	sent := true
+	err = s.store.WithTx(ctx, func(tx database.Store) error {
+		// Locking the account makes parallel requests count each other's codes
+		if _, err := tx.Users().GetForUpdate(ctx, user.ID); err != nil {
+			return err
+		}
+		recent, err := tx.Resets().CountSince(ctx, user.ID, time.Now().Add(-resetRequestWindow))
+		if err != nil {
+			return err
+		}
+		if recent >= maxResetRequests {
+			sent = false
+			return nil
+		}
+
+		if err := tx.Resets().InvalidateForUser(ctx, user.ID); err != nil {
+			return err
+		}
+		return tx.Resets().Create(ctx, &model.PasswordReset{
+			UserID:    user.ID,
+			CodeHash:  s.hashResetCode(user.ID, code),
+			ExpiresAt: time.Now().Add(ttl),
+		})
+	})
+	if err != nil || !sent {
+		return err
+	}
+
+	return s.notifier.Send(ctx, user, fmt.Sprintf(
+		"Your SmashQueue password reset code is %s. It expires in %d minutes.", code, int(ttl.Minutes())))
 }
 
-// ResetPassword resets password after verification
+// ResetPassword sets a new password using a reset code, then signs the user out everywhere
 func (s *AuthService) ResetPassword(req model.ResetPasswordRequest) error {
-	// First verify
-	if err := s.VerifyUserForReset(model.ForgotPasswordRequest{
-		Username: req.Username,
-		Phone:    req.Phone,
-	}); err != nil {
+	ctx := context.Background()
+
+	user, err := s.store.Users().GetByLogin(ctx, req.Username)
+	if err == database.ErrNotFound {
+		return ErrInvalidResetCode
+	}
+	if err != nil {
 		return err
 	}
 
-	// Validate new password
-	if err := s.validatePassword(req.Username, req.NewPassword); err != nil {
+	reset, err := s.store.Resets().Active(ctx, user.ID)
+	if err == database.ErrNotFound {
+		return ErrInvalidResetCode
+	}
+	if err != nil {
 		return err
 	}
 
-	ctx := context.Background()
-	hash := s.hashPassword(req.NewPassword)
+	// Validate new password before spending a guess on it
+	if err := s.validatePassword(user.Username, req.NewPassword); err != nil {
+		return err
+	}
 
-	_, err := s.db.ExecContext(ctx, `
-		UPDATE users SET password_hash = $2, updated_at = NOW() WHERE username = $1
-	`, req.Username, hash)
+	// The guess is counted before the code is compared, so parallel guesses cannot
+	// all slip in under the limit
+	claimed, err := s.store.Resets().ClaimAttempt(ctx, reset.ID, maxResetAttempts)
+	if err != nil {
+		return err
+	}
+	if !claimed || !hmac.Equal([]byte(reset.CodeHash), []byte(s.hashResetCode(user.ID, req.Code))) {
+		return ErrInvalidResetCode
+	}
 
-	return err
+	return s.store.WithTx(ctx, func(tx database.Store) error {
+		// A concurrent reset may have spent the code since it was read
+		used, err := tx.Resets().Use(ctx, reset.ID)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidResetCode
+		}
+
+		if err := tx.Users().UpdatePassword(ctx, user.ID, s.hashPassword(req.NewPassword)); err != nil {
+			return err
+		}
+
+		return tx.Tokens().RevokeAllForUser(ctx, user.ID)
+	})
 }
 
-// RefreshAccessToken generates a new access token from a refresh token
-func (s *AuthService) RefreshAccessToken(refreshToken string) (*model.AuthResponse, error) {
+// ChangePassword sets a new password for a signed-in user after checking their current one,
+// then signs out every other device. currentToken is the caller's refresh token.
+func (s *AuthService) ChangePassword(userID int64, req model.ChangePasswordRequest, currentToken string) error {
 	ctx := context.Background()
 
-	var token model.RefreshToken
-	var userID int64
-	err := s.db.QueryRowContext(ctx, `
-		SELECT id, user_id, expires_at, revoked_at
-		FROM refresh_tokens WHERE token = $1
-	`, refreshToken).Scan(&token.ID, &userID, &token.ExpiresAt, &token.RevokedAt)
+	user, err := s.store.Users().GetByID(ctx, userID)
+	if err == database.ErrNotFound {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if !s.verifyPassword(req.CurrentPassword, user.PasswordHash) {
+		return ErrInvalidCredentials
+	}
+	if err := s.validatePassword(user.Username, req.NewPassword); err != nil {
+		return err
+	}
 
-	if err == sql.ErrNoRows {
-		return nil, ErrInvalidToken
+	keep := s.currentFamily(ctx, userID, currentToken)
+	return s.store.WithTx(ctx, func(tx database.Store) error {
+		if err := tx.Users().UpdatePassword(ctx, userID, s.hashPassword(req.NewPassword)); err != nil {
+			return err
+		}
+		if keep == "" {
+			return tx.Tokens().RevokeAllForUser(ctx, userID)
+		}
+		return tx.Tokens().RevokeOthers(ctx, userID, keep)
+	})
+}
+
+// hashResetCode keys the hash with the server secret since a six-digit code is easy to brute-force
+func (s *AuthService) hashResetCode(userID int64, code string) string {
+	mac := hmac.New(sha256.New, []byte(s.config.Auth.SecretKey))
+	fmt.Fprintf(mac, "%d:%s", userID, code)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RefreshAccessToken exchanges a refresh token for a new access token and a new refresh token.
+// The presented token is rotated out; presenting it again means it was copied, so the whole
+// family is revoked and the sign-in must start over.
+func (s *AuthService) RefreshAccessToken(refreshToken string, client model.ClientInfo) (*model.AuthResponse, string, error) {
+	ctx := context.Background()
+
+	token, err := s.store.Tokens().GetByHash(ctx, hashToken(refreshToken))
+	if err == database.ErrNotFound {
+		return nil, "", ErrInvalidToken
 	}
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
+	if token.RotatedAt != nil && token.RevokedAt == nil {
+		return nil, "", s.revokeReusedFamily(ctx, token)
+	}
 	if !token.IsValid() {
-		return nil, ErrInvalidToken
+		return nil, "", ErrInvalidToken
 	}
 
-	user, err := s.GetUserByID(userID)
+	user, err := s.GetUserByID(token.UserID)
+	if err != nil || !user.IsActive {
+		return nil, "", ErrInvalidToken
+	}
+
+	var next string
+	err = s.store.WithTx(ctx, func(tx database.Store) error {
+		// Another request may have rotated the token since it was read
+		rotated, err := tx.Tokens().Rotate(ctx, token.ID)
+		if err != nil {
+			return err
+		}
+		if !rotated {
+			return ErrTokenReused
+		}
+
+		next, err = s.generateRefreshToken(ctx, tx, user.ID, token, client)
+		return err
+	})
+	if err == ErrTokenReused {
+		return nil, "", s.revokeReusedFamily(ctx, token)
+	}
 	if err != nil {
-		return nil, ErrInvalidToken
+		return nil, "", err
 	}
 
 	accessToken, err := s.generateAccessToken(user)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	return &model.AuthResponse{
 		AccessToken: accessToken,
 		User:        *user,
-	}, nil
+	}, next, nil
+}
+
+// revokeReusedFamily ends the sign-in a replayed refresh token belongs to
+func (s *AuthService) revokeReusedFamily(ctx context.Context, token *model.RefreshToken) error {
+	log.Printf("refresh token reuse for user %d, revoking family %s", token.UserID, token.FamilyID)
+	if err := s.store.Tokens().RevokeFamily(ctx, token.FamilyID); err != nil {
+		return err
+	}
+	return ErrTokenReused
 }
 
-// ValidateToken verifies and decodes an access token
+// ValidateToken verifies and decodes an access token. The account must still be active,
+// and its current role replaces the one in the token so role changes apply immediately.
 func (s *AuthService) ValidateToken(tokenString string) (*model.TokenPayload, error) {
 	var payload model.TokenPayload
 
@@ -294,74 +451,37 @@ func (s *AuthService) ValidateToken(tokenString string) (*model.TokenPayload, er
 		return nil, ErrInvalidToken
 	}
 
-	return &payload, nil
-}
-
-// GetUserByID returns a user by ID
-func (s *AuthService) GetUserByID(userID int64) (*model.User, error) {
-	ctx := context.Background()
-
-	var user model.User
-	var handPref, skillTier sql.NullString
-
-	err := s.db.QueryRowContext(ctx, `
-		SELECT id, username, name, phone, bio, role, 
-		       COALESCE(hand_preference, 'right'), COALESCE(skill_tier, 'N'),
-		       is_active, created_at, updated_at
-		FROM users WHERE id = $1
-	`, userID).Scan(
-		&user.ID, &user.Username, &user.Name, &user.Phone,
-		&user.Bio, &user.Role, &handPref, &skillTier,
-		&user.IsActive, &user.CreatedAt, &user.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, errors.New("user not found")
+	user, err := s.GetUserByID(payload.UserID)
+	if err == ErrUserNotFound {
+		return nil, ErrInvalidToken
 	}
 	if err != nil {
 		return nil, err
 	}
+	if !user.IsActive {
+		return nil, ErrAccountDisabled
+	}
+	payload.Role = user.Role
 
-	user.HandPreference = model.HandPreference(handPref.String)
-	user.SkillTier = model.SkillTier(skillTier.String)
-
-	return &user, nil
+	return &payload, nil
 }
 
-// GetAllUsers returns all users with stats (for admin)
-func (s *AuthService) GetAllUsers() ([]model.UserListItem, error) {
-	ctx := context.Background()
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT u.id, u.username, u.name, u.phone, u.role, 
-		       COALESCE(u.hand_preference, 'right'), COALESCE(u.skill_tier, 'N'),
-		       u.is_active, 
-		       COALESCE(us.skill_level, 'Beginner'), COALESCE(us.win_rate, 0),
-		       COALESCE(us.total_matches, 0), COALESCE(us.wins, 0)
-		FROM users u
-		LEFT JOIN user_stats us ON u.id = us.user_id
-		ORDER BY u.name ASC
-	`)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var users []model.UserListItem
-	for rows.Next() {
-		var u model.UserListItem
-		if err := rows.Scan(&u.ID, &u.Username, &u.Name, &u.Phone, &u.Role,
-			&u.HandPreference, &u.SkillTier, &u.IsActive, &u.SkillLevel, &u.WinRate,
-			&u.TotalMatches, &u.Wins); err != nil {
-			continue
-		}
-		users = append(users, u)
+// GetUserByID returns a user by ID
+func (s *AuthService) GetUserByID(userID int64) (*model.User, error) {
+	user, err := s.store.Users().GetByID(context.Background(), userID)
+	if err == database.ErrNotFound {
+		return nil, ErrUserNotFound
 	}
+	return user, err
+}
 
-	return users, nil
+// GetAllUsers returns a club's members with stats and their club role (for admin)
+func (s *AuthService) GetAllUsers(clubID int64) ([]model.UserListItem, error) {
+	return s.store.Users().ListByClub(context.Background(), clubID)
 }
 
-// UpdatePlayerAdmin updates player hand preference and skill tier (admin only)
-func (s *AuthService) UpdatePlayerAdmin(userID int64, req model.UpdatePlayerAdminRequest) error {
+// UpdatePlayerAdmin updates a club member's hand preference and skill tier (admin only)
+func (s *AuthService) UpdatePlayerAdmin(actor model.Actor, clubID, userID int64, req model.UpdatePlayerAdminRequest) error {
 	ctx := context.Background()
 
 	// Validate skill tier
@@ -382,35 +502,25 @@ func (s *AuthService) UpdatePlayerAdmin(userID int64, req model.UpdatePlayerAdmi
 		return errors.New("invalid hand preference")
 	}
 
-	_, err := s.db.ExecContext(ctx, `
-		UPDATE users SET hand_preference = $2, skill_tier = $3, updated_at = NOW()
-		WHERE id = $1
-	`, userID, req.HandPreference, req.SkillTier)
-
-	return err
-}
-
-// VerifyUserByPhone verifies username and phone match
-func (s *AuthService) VerifyUserByPhone(username, phone string) error {
-	ctx := context.Background()
-
-	var existingPhone string
-	err := s.db.QueryRowContext(ctx,
-		"SELECT phone FROM users WHERE username = $1",
-		username,
-	).Scan(&existingPhone)
-
-	if err == sql.ErrNoRows {
+	user, err := s.store.Users().GetByID(ctx, userID)
+	if err == database.ErrNotFound {
 		return ErrUserNotFound
 	}
 	if err != nil {
 		return err
 	}
 
-	if existingPhone != phone {
-		return ErrPhoneMismatch
+	err = s.store.Users().UpdatePlayer(ctx, clubID, userID,
+		model.HandPreference(req.HandPreference), model.SkillTier(req.SkillTier))
+	if err == database.ErrNotFound {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
 	}
 
+	s.audit.Record(actor, clubID, model.AuditPlayerUpdated, "user", userID,
+		map[string]interface{}{"hand_preference": user.HandPreference, "skill_tier": user.SkillTier}, req)
 	return nil
 }
 
@@ -508,32 +618,60 @@ func (s *AuthService) generateAccessToken(user *model.User) (string, error) {
 	return v2.Encrypt(key, payload, nil)
 }
 
-func (s *AuthService) generateRefreshToken(user *model.User) (string, error) {
-	tokenBytes := make([]byte, 32)
-	if _, err := rand.Read(tokenBytes); err != nil {
+// generateRefreshToken issues a refresh token replacing prev in its family, starting a new
+// family when prev is nil. Only the token's hash is stored.
+func (s *AuthService) generateRefreshToken(ctx context.Context, repo database.Store, userID int64, prev *model.RefreshToken, client model.ClientInfo) (string, error) {
+	value, err := randomHex(32)
+	if err != nil {
 		return "", err
 	}
 
-	token := hex.EncodeToString(tokenBytes)
-	expiresAt := time.Now().Add(s.config.Auth.RefreshTokenDuration)
+	token := model.RefreshToken{
+		UserID:    userID,
+		TokenHash: hashToken(value),
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
+		ExpiresAt: time.Now().Add(s.config.Auth.RefreshTokenDuration),
+	}
+	if prev != nil {
+		token.FamilyID = prev.FamilyID
+		token.SignedInAt = prev.SignedInAt
+	} else if token.FamilyID, err = randomHex(16); err != nil {
+		return "", err
+	}
+	if err := repo.Tokens().Create(ctx, &token); err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
 
+// Logout revokes the sign-in the refresh token belongs to
+func (s *AuthService) Logout(refreshToken string) error {
 	ctx := context.Background()
-	_, err := s.db.ExecContext(ctx, `
-		INSERT INTO refresh_tokens (user_id, token, expires_at, created_at)
-		VALUES ($1, $2, $3, NOW())
-	`, user.ID, token, expiresAt)
 
+	token, err := s.store.Tokens().GetByHash(ctx, hashToken(refreshToken))
+	if err == database.ErrNotFound {
+		return nil
+	}
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	return token, nil
+	return s.store.Tokens().RevokeFamily(ctx, token.FamilyID)
 }
 
-func (s *AuthService) Logout(refreshToken string) error {
-	ctx := context.Background()
-	_, err := s.db.ExecContext(ctx, `
-		UPDATE refresh_tokens SET revoked_at = NOW() WHERE token = $1
-	`, refreshToken)
-	return err
+// randomHex returns n random bytes hex-encoded
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken returns the SHA-256 of a token, which is what gets stored
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
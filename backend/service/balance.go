@@ -0,0 +1,188 @@
+package service
+
+import (
+	"backend/model"
+	"context"
+	"math"
+)
+
+const (
+	// recentMatchWindow is how many recent matches are checked for repeat partnerships
+	recentMatchWindow = 30
+	// repeatPartnerPenalty is the strength gap a repeated partnership is worth
+	repeatPartnerPenalty = 8.0
+)
+
+// tierStrength maps skill tiers to a base strength on a 0-90 scale
+var tierStrength = map[model.SkillTier]float64{
+	model.SkillBG: 0,
+	model.SkillSM: 10,
+	model.SkillS:  20,
+	model.SkillN:  30,
+	model.SkillPM: 40,
+	model.SkillP:  50,
+	model.SkillPP: 60,
+	model.SkillC:  70,
+	model.SkillB:  80,
+	model.SkillA:  90,
+}
+
+// BalanceTeams proposes a team1/team2 split for a club that minimizes the skill gap
+// and avoids pairing players who partnered recently
+func (s *MatchService) BalanceTeams(clubID int64, req model.BalanceTeamsRequest) (*model.TeamProposal, error) {
+	playerIDs := req.UserIDs
+	if len(playerIDs) == 0 {
+		called, err := s.queueService.GetCalled(clubID, req.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		if len(called) > 4 {
+			called = called[:4]
+		}
+		for _, entry := range called {
+			playerIDs = append(playerIDs, entry.UserID)
+		}
+	}
+
+	proposal, err := s.balancePlayers(context.Background(), playerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	proposal.SessionID = req.SessionID
+	proposal.Court = req.Court
+	if proposal.Court == "" {
+		if court, err := s.courtService.NextAvailable(clubID, s.sessionService.CourtIDs(req.SessionID)); err == nil {
+			proposal.Court = court.Name
+		}
+	}
+
+	return proposal, nil
+}
+
+func (s *MatchService) balancePlayers(ctx context.Context, playerIDs []int64) (*model.TeamProposal, error) {
+	if len(playerIDs) != 2 && len(playerIDs) != 4 {
+		return nil, ErrInvalidTeam
+	}
+	seen := make(map[int64]bool)
+	for _, id := range playerIDs {
+		if seen[id] {
+			return nil, ErrInvalidTeam
+		}
+		seen[id] = true
+	}
+
+	strength, err := s.playerStrengths(ctx, playerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Singles has only one possible split
+	if len(playerIDs) == 2 {
+		a, b := strength[playerIDs[0]], strength[playerIDs[1]]
+		return &model.TeamProposal{
+			CreateMatchRequest: model.CreateMatchRequest{
+				Team1: []int64{playerIDs[0]},
+				Team2: []int64{playerIDs[1]},
+			},
+			Team1Strength: a,
+			Team2Strength: b,
+			StrengthGap:   math.Abs(a - b),
+		}, nil
+	}
+
+	partnered, err := s.recentPartnerships(ctx, playerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// The three ways to split four players into two pairs
+	p := playerIDs
+	splits := [][2][]int64{
+		{{p[0], p[1]}, {p[2], p[3]}},
+		{{p[0], p[2]}, {p[1], p[3]}},
+		{{p[0], p[3]}, {p[1], p[2]}},
+	}
+
+	var best *model.TeamProposal
+	bestCost := math.MaxFloat64
+	for _, split := range splits {
+		t1 := strength[split[0][0]] + strength[split[0][1]]
+		t2 := strength[split[1][0]] + strength[split[1][1]]
+		repeats := partnered[pairKey(split[0][0], split[0][1])] + partnered[pairKey(split[1][0], split[1][1])]
+
+		gap := math.Abs(t1 - t2)
+		cost := gap + float64(repeats)*repeatPartnerPenalty
+		if cost < bestCost {
+			bestCost = cost
+			best = &model.TeamProposal{
+				CreateMatchRequest: model.CreateMatchRequest{
+					Team1: split[0],
+					Team2: split[1],
+				},
+				Team1Strength:  t1,
+				Team2Strength:  t2,
+				StrengthGap:    gap,
+				RepeatPartners: repeats,
+			}
+		}
+	}
+
+	return best, nil
+}
+
+// playerStrengths scores each player from skill tier adjusted by their rating
+func (s *MatchService) playerStrengths(ctx context.Context, playerIDs []int64) (map[int64]float64, error) {
+	users, err := s.store.Users().GetMany(ctx, playerIDs)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.store.Stats().GetMany(ctx, playerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	strength := make(map[int64]float64, len(playerIDs))
+	for _, u := range users {
+		rating, deviation := DefaultRating, DefaultDeviation
+		if st, ok := stats[u.ID]; ok {
+			rating, deviation = st.Rating, st.RatingDev
+		}
+
+		// Rating moves the tier by one tier per 200 points, trusted more as deviation shrinks
+		confidence := math.Max(0, (DefaultDeviation-deviation)/(DefaultDeviation-minDeviation))
+		strength[u.ID] = tierStrength[u.SkillTier] + (rating-DefaultRating)/20*confidence
+	}
+
+	if len(strength) != len(playerIDs) {
+		return nil, ErrUserNotFound
+	}
+
+	return strength, nil
+}
+
+// recentPartnerships counts how often each pair of players shared a team recently
+func (s *MatchService) recentPartnerships(ctx context.Context, playerIDs []int64) (map[[2]int64]int, error) {
+	matches, err := s.store.Matches().Played(ctx, playerIDs, recentMatchWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	partnered := make(map[[2]int64]int)
+	for _, m := range matches {
+		for _, team := range [][]int64{m.Team1, m.Team2} {
+			if len(team) == 2 {
+				partnered[pairKey(team[0], team[1])]++
+			}
+		}
+	}
+
+	return partnered, nil
+}
+
+func pairKey(a, b int64) [2]int64 {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]int64{a, b}
+}
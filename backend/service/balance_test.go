@@ -0,0 +1,117 @@
+package service
+
+import (
+	"backend/database"
+	"backend/model"
+	"context"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestBalancePlayers(t *testing.T) {
+	// rated players carry a settled rating; partnered counts past matches per pair of player indexes
+	tests := []struct {
+		name      string
+		tiers     []model.SkillTier
+		rated     map[int]float64
+		partnered map[[2]int]int
+		partner   int // index of the player paired with the first; -1 for singles
+		gap       float64
+	}{
+		{"singles", []model.SkillTier{model.SkillP, model.SkillN}, nil, nil, -1, 20},
+		{"even players keep the given order", []model.SkillTier{model.SkillN, model.SkillN, model.SkillN, model.SkillN}, nil, nil, 1, 0},
+		{"strongest with weakest", []model.SkillTier{model.SkillA, model.SkillB, model.SkillC, model.SkillBG}, nil, nil, 3, 60},
+		{"settled ratings shift the tiers", []model.SkillTier{model.SkillN, model.SkillN, model.SkillN, model.SkillN},
+			map[int]float64{0: 1700, 1: 1700}, nil, 2, 0},
+		{"recent partners split up", []model.SkillTier{model.SkillN, model.SkillN, model.SkillN, model.SkillN},
+			nil, map[[2]int]int{{0, 1}: 1}, 2, 0},
+		{"a small gap beats two repeats", []model.SkillTier{model.SkillC, model.SkillB, model.SkillBG, model.SkillSM},
+			nil, map[[2]int]int{{0, 3}: 2}, 3, 0},
+		{"repeats outweigh a small gap", []model.SkillTier{model.SkillC, model.SkillB, model.SkillBG, model.SkillSM},
+			nil, map[[2]int]int{{0, 3}: 3}, 2, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestServices(t)
+			ctx := context.Background()
+			ids := ts.addTieredPlayers(t, tt.tiers)
+
+			for i, rating := range tt.rated {
+				change := model.RatingChange{UserID: ids[i], RatingAfter: rating, DeviationAfter: minDeviation}
+				if err := ts.store.Stats().SaveRating(ctx, change, DefaultVolatility); err != nil {
+					t.Fatalf("save rating: %v", err)
+				}
+			}
+			opponents := ts.addPlayers(t, 2)
+			for pair, n := range tt.partnered {
+				for range n {
+					match := &model.Match{ClubID: database.MemoryDefaultClubID, Team1: []int64{ids[pair[0]], ids[pair[1]]}, Team2: opponents}
+					if err := ts.store.Matches().Create(ctx, match); err != nil {
+						t.Fatalf("create match: %v", err)
+					}
+				}
+			}
+
+			proposal, err := ts.matches.balancePlayers(ctx, ids)
+			if err != nil {
+				t.Fatalf("balancePlayers: %v", err)
+			}
+			if !contains(proposal.Team1, ids[0]) {
+				t.Fatalf("team1 %v does not hold the first player", proposal.Team1)
+			}
+			if tt.partner >= 0 && !contains(proposal.Team1, ids[tt.partner]) {
+				t.Errorf("teams %v vs %v, want the first player with player %d", proposal.Team1, proposal.Team2, ids[tt.partner])
+			}
+			if math.Abs(proposal.StrengthGap-tt.gap) > 1e-9 {
+				t.Errorf("strength gap = %.2f, want %.2f", proposal.StrengthGap, tt.gap)
+			}
+		})
+	}
+}
+
+func TestBalancePlayersRejects(t *testing.T) {
+	ts := newTestServices(t)
+	ids := ts.addPlayers(t, 4)
+
+	tests := []struct {
+		name    string
+		players []int64
+		want    error
+	}{
+		{"three players", ids[:3], ErrInvalidTeam},
+		{"one player", ids[:1], ErrInvalidTeam},
+		{"duplicate player", []int64{ids[0], ids[1], ids[2], ids[0]}, ErrInvalidTeam},
+		{"unknown player", []int64{ids[0], 999999}, ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ts.matches.balancePlayers(context.Background(), tt.players); err != tt.want {
+				t.Errorf("balancePlayers(%v) = %v, want %v", tt.players, err, tt.want)
+			}
+		})
+	}
+}
+
+// addTieredPlayers registers one player per skill tier
+func (ts *testServices) addTieredPlayers(t *testing.T, tiers []model.SkillTier) []int64 {
+	t.Helper()
+
+	ids := make([]int64, len(tiers))
+	for i, tier := range tiers {
+		user := &model.User{
+			Username:  fmt.Sprintf("tiered%d", i),
+			Name:      fmt.Sprintf("Tiered %d", i),
+			Role:      model.RolePlayer,
+			SkillTier: tier,
+			IsActive:  true,
+		}
+		if err := ts.store.Users().Create(context.Background(), user); err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		ids[i] = user.ID
+	}
+	return ids
+}
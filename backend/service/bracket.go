@@ -0,0 +1,233 @@
+package service
+
+import (
+	"backend/model"
+)
+
+// plannedMatch is a bracket match before it is saved. Links are indices into the plan
+// (-1 for none) since IDs are only known once the matches are inserted.
+type plannedMatch struct {
+	model.BracketMatch
+	next      int
+	loserNext int
+}
+
+// seedOrder returns the bracket lines of a draw of the given size (a power of two), so the
+// top seeds can only meet in the late rounds: 1, 8, 4, 5, 2, 7, 3, 6 for eight lines
+func seedOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		next := make([]int, 0, len(order)*2)
+		for _, s := range order {
+			next = append(next, s, 2*len(order)+1-s)
+		}
+		order = next
+	}
+	return order
+}
+
+// planBracket lays out the bracket for entries in seed order. Lines without an entry are
+// byes. Matches are returned in order of play: winners, losers, then final rounds.
+func planBracket(format model.TournamentFormat, entries []model.TournamentEntry) []plannedMatch {
+	size, rounds := 2, 1
+	for size < len(entries) {
+		size *= 2
+		rounds++
+	}
+
+	var plan []plannedMatch
+	add := func(side model.BracketSide, round, position int) int {
+		plan = append(plan, plannedMatch{
+			BracketMatch: model.BracketMatch{Bracket: side, Round: round, Position: position, Status: model.BracketPending},
+			next:         -1,
+			loserNext:    -1,
+		})
+		return len(plan) - 1
+	}
+	link := func(from, to, slot int) {
+		plan[from].next, plan[from].NextSlot = to, slot
+	}
+	linkLoser := func(from, to, slot int) {
+		plan[from].loserNext, plan[from].LoserNextSlot = to, slot
+	}
+
+	// Winners bracket: round r has size/2^r matches, the winner of match i moving to match i/2
+	winners := make([][]int, rounds+1)
+	for r := 1; r <= rounds; r++ {
+		for i := 0; i < size>>r; i++ {
+			winners[r] = append(winners[r], add(model.BracketWinners, r, i+1))
+		}
+	}
+	for r := 1; r < rounds; r++ {
+		for i, m := range winners[r] {
+			link(m, winners[r+1][i/2], i%2+1)
+		}
+	}
+
+	order := seedOrder(size)
+	for i, m := range winners[1] {
+		if seed := order[2*i]; seed <= len(entries) {
+			plan[m].Entry1ID = &entries[seed-1].ID
+		}
+		if seed := order[2*i+1]; seed <= len(entries) {
+			plan[m].Entry2ID = &entries[seed-1].ID
+		}
+	}
+
+	if format != model.FormatDoubleElimination {
+		return plan
+	}
+
+	// Losers bracket: odd rounds pair up survivors, even rounds bring in the losers of the
+	// next winners round. Those drop in reversed every other round to avoid early rematches.
+	losers := make([][]int, 2*(rounds-1)+1)
+	for r := 1; r <= 2*(rounds-1); r++ {
+		for i := 0; i < size>>((r+1)/2+1); i++ {
+			losers[r] = append(losers[r], add(model.BracketLosers, r, i+1))
+		}
+	}
+	for i, m := range winners[1] {
+		linkLoser(m, losers[1][i/2], i%2+1)
+	}
+	for r := 2; r <= rounds; r++ {
+		drop := losers[2*(r-1)]
+		for i, m := range winners[r] {
+			j := i
+			if r%2 == 0 {
+				j = len(drop) - 1 - i
+			}
+			linkLoser(m, drop[j], 2)
+		}
+	}
+	for r := 1; r < 2*(rounds-1); r++ {
+		for i, m := range losers[r] {
+			if r%2 == 1 {
+				link(m, losers[r+1][i], 1)
+			} else {
+				link(m, losers[r+1][i/2], i%2+1)
+			}
+		}
+	}
+
+	// Grand final between the two bracket winners. If the losers bracket winner takes it,
+	// both have lost once and the reset decides the title.
+	final := add(model.BracketFinal, 1, 1)
+	reset := add(model.BracketFinal, 2, 1)
+	link(winners[rounds][0], final, 1)
+	link(losers[2*(rounds-1)][0], final, 2)
+	link(final, reset, 1)
+	linkLoser(final, reset, 2)
+
+	return plan
+}
+
+// bracket is a tournament's saved bracket being advanced in memory. Changed matches are
+// tracked so only they are written back.
+type bracket struct {
+	matches []model.BracketMatch
+	index   map[int64]int
+	changed map[int64]bool
+}
+
+func newBracket(matches []model.BracketMatch) *bracket {
+	b := &bracket{matches: matches, index: make(map[int64]int, len(matches)), changed: make(map[int64]bool)}
+	for i, m := range matches {
+		b.index[m.ID] = i
+	}
+	return b
+}
+
+// get returns the bracket match with the given ID, or nil
+func (b *bracket) get(id *int64) *model.BracketMatch {
+	if id == nil {
+		return nil
+	}
+	i, ok := b.index[*id]
+	if !ok {
+		return nil
+	}
+	return &b.matches[i]
+}
+
+// decide completes a match with the given winner and moves both entries on
+func (b *bracket) decide(m *model.BracketMatch, winner, loser *int64) {
+	m.WinnerEntryID, m.LoserEntryID = winner, loser
+	m.Status = model.BracketCompleted
+	b.changed[m.ID] = true
+
+	// The winners bracket champion taking the grand final ends the tournament without a reset
+	if m.Bracket == model.BracketFinal && m.Round == 1 && winner != nil && m.Entry1ID != nil && *winner == *m.Entry1ID {
+		return
+	}
+	b.place(b.get(m.NextMatchID), m.NextSlot, winner)
+	b.place(b.get(m.LoserNextMatchID), m.LoserNextSlot, loser)
+}
+
+func (b *bracket) place(m *model.BracketMatch, slot int, entry *int64) {
+	if m == nil || entry == nil {
+		return
+	}
+	id := *entry
+	if slot == 1 {
+		m.Entry1ID = &id
+	} else {
+		m.Entry2ID = &id
+	}
+	b.changed[m.ID] = true
+}
+
+// settle resolves every pending match whose entries are now known: ready when both are
+// there, a bye when only one can ever arrive, and skipped when neither can
+func (b *bracket) settle() {
+	for progress := true; progress; {
+		progress = false
+		for i := range b.matches {
+			m := &b.matches[i]
+			if m.Status != model.BracketPending {
+				continue
+			}
+
+			open1, open2 := b.awaiting(m.ID, 1), b.awaiting(m.ID, 2)
+			switch {
+			case m.Entry1ID != nil && m.Entry2ID != nil:
+				m.Status = model.BracketReady
+			case open1 || open2:
+				continue
+			case m.Entry1ID != nil:
+				b.decide(m, m.Entry1ID, nil)
+			case m.Entry2ID != nil:
+				b.decide(m, m.Entry2ID, nil)
+			default:
+				m.Status = model.BracketSkipped
+			}
+			b.changed[m.ID] = true
+			progress = true
+		}
+	}
+}
+
+// awaiting reports whether an entry may still arrive in a match slot from an unfinished match
+func (b *bracket) awaiting(id int64, slot int) bool {
+	for _, m := range b.matches {
+		feeds := (m.NextMatchID != nil && *m.NextMatchID == id && m.NextSlot == slot) ||
+			(m.LoserNextMatchID != nil && *m.LoserNextMatchID == id && m.LoserNextSlot == slot)
+		if feeds && m.Status != model.BracketCompleted && m.Status != model.BracketSkipped {
+			return true
+		}
+	}
+	return false
+}
+
+// champion returns the tournament winner once the deciding match is over
+func (b *bracket) champion() *int64 {
+	for _, m := range b.matches {
+		if m.Status != model.BracketCompleted {
+			continue
+		}
+		next := b.get(m.NextMatchID)
+		if next == nil || next.Status == model.BracketSkipped {
+			return m.WinnerEntryID
+		}
+	}
+	return nil
+}
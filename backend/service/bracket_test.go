@@ -0,0 +1,211 @@
+package service
+
+import (
+	"backend/model"
+	"reflect"
+	"testing"
+)
+
+// newTestBracket plans a bracket for n entries seeded by ID (1 is the top seed), numbers
+// the matches from 1 in order of play and settles the byes
+func newTestBracket(format model.TournamentFormat, n int) *bracket {
+	entries := make([]model.TournamentEntry, n)
+	for i := range entries {
+		entries[i].ID = int64(i + 1)
+	}
+
+	plan := planBracket(format, entries)
+	matches := make([]model.BracketMatch, len(plan))
+	for i, p := range plan {
+		m := p.BracketMatch
+		m.ID = int64(i + 1)
+		if p.next >= 0 {
+			id := int64(p.next + 1)
+			m.NextMatchID = &id
+		}
+		if p.loserNext >= 0 {
+			id := int64(p.loserNext + 1)
+			m.LoserNextMatchID = &id
+		}
+		matches[i] = m
+	}
+
+	b := newBracket(matches)
+	b.settle()
+	return b
+}
+
+// countStatus counts the bracket matches in each status
+func (b *bracket) countStatus() map[model.BracketMatchStatus]int {
+	counts := make(map[model.BracketMatchStatus]int)
+	for _, m := range b.matches {
+		counts[m.Status]++
+	}
+	return counts
+}
+
+func TestSeedOrder(t *testing.T) {
+	tests := []struct {
+		size int
+		want []int
+	}{
+		{2, []int{1, 2}},
+		{4, []int{1, 4, 2, 3}},
+		{8, []int{1, 8, 4, 5, 2, 7, 3, 6}},
+		{16, []int{1, 16, 8, 9, 4, 13, 5, 12, 2, 15, 7, 10, 3, 14, 6, 11}},
+	}
+
+	for _, tt := range tests {
+		if got := seedOrder(tt.size); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("seedOrder(%d) = %v, want %v", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestPlanBracket(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  model.TournamentFormat
+		entries int
+		sides   map[model.BracketSide]int
+		byes    int
+		ready   int
+	}{
+		{"single, two entries", model.FormatSingleElimination, 2,
+			map[model.BracketSide]int{model.BracketWinners: 1}, 0, 1},
+		{"single, full draw", model.FormatSingleElimination, 8,
+			map[model.BracketSide]int{model.BracketWinners: 7}, 0, 4},
+		{"single, byes for the top seeds", model.FormatSingleElimination, 5,
+			map[model.BracketSide]int{model.BracketWinners: 7}, 3, 2},
+		{"double, four entries", model.FormatDoubleElimination, 4,
+			map[model.BracketSide]int{model.BracketWinners: 3, model.BracketLosers: 2, model.BracketFinal: 2}, 0, 2},
+		{"double, full draw", model.FormatDoubleElimination, 8,
+			map[model.BracketSide]int{model.BracketWinners: 7, model.BracketLosers: 6, model.BracketFinal: 2}, 0, 4},
+		{"double, one bye", model.FormatDoubleElimination, 3,
+			map[model.BracketSide]int{model.BracketWinners: 3, model.BracketLosers: 2, model.BracketFinal: 2}, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBracket(tt.format, tt.entries)
+
+			sides := make(map[model.BracketSide]int)
+			for _, m := range b.matches {
+				sides[m.Bracket]++
+			}
+			if !reflect.DeepEqual(sides, tt.sides) {
+				t.Errorf("matches per side = %v, want %v", sides, tt.sides)
+			}
+
+			counts := b.countStatus()
+			if counts[model.BracketCompleted] != tt.byes || counts[model.BracketReady] != tt.ready {
+				t.Errorf("%d byes and %d ready, want %d and %d", counts[model.BracketCompleted], counts[model.BracketReady], tt.byes, tt.ready)
+			}
+			for _, m := range b.matches {
+				if m.Status == model.BracketCompleted && m.LoserEntryID != nil {
+					t.Errorf("bye %d/%d has a loser", m.Round, m.Position)
+				}
+			}
+		})
+	}
+}
+
+func TestBracketPlayThrough(t *testing.T) {
+	topSeed := func(m *model.BracketMatch) *int64 {
+		if *m.Entry1ID < *m.Entry2ID {
+			return m.Entry1ID
+		}
+		return m.Entry2ID
+	}
+	// The losers bracket champion takes the grand final, forcing the reset
+	upsetFinal := func(resetTo func(*model.BracketMatch) *int64) func(*model.BracketMatch) *int64 {
+		return func(m *model.BracketMatch) *int64 {
+			switch {
+			case m.Bracket == model.BracketFinal && m.Round == 1:
+				return m.Entry2ID
+			case m.Bracket == model.BracketFinal:
+				return resetTo(m)
+			}
+			return topSeed(m)
+		}
+	}
+	gfWinner := func(m *model.BracketMatch) *int64 { return m.Entry1ID }
+
+	tests := []struct {
+		name     string
+		format   model.TournamentFormat
+		entries  int
+		winner   func(m *model.BracketMatch) *int64
+		champion int64
+		played   int
+		reset    bool
+	}{
+		{"single, favourites win", model.FormatSingleElimination, 8, topSeed, 1, 7, false},
+		{"single, with byes", model.FormatSingleElimination, 6, topSeed, 1, 5, false},
+		{"double, winners champion takes the final", model.FormatDoubleElimination, 4, topSeed, 1, 6, false},
+		{"double, reset won by the top seed", model.FormatDoubleElimination, 4, upsetFinal(topSeed), 1, 7, true},
+		{"double, losers champion wins the reset", model.FormatDoubleElimination, 4, upsetFinal(gfWinner), 2, 7, true},
+		{"double, with byes", model.FormatDoubleElimination, 5, topSeed, 1, 8, false},
+		{"double, full draw", model.FormatDoubleElimination, 8, topSeed, 1, 14, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBracket(tt.format, tt.entries)
+
+			losses := make(map[int64]int)
+			played := 0
+			for {
+				var m *model.BracketMatch
+				for i := range b.matches {
+					if b.matches[i].Status == model.BracketReady {
+						m = &b.matches[i]
+						break
+					}
+				}
+				if m == nil {
+					break
+				}
+				if b.champion() != nil {
+					t.Fatalf("match %d/%d ready after the champion was decided", m.Round, m.Position)
+				}
+
+				winner, loser := tt.winner(m), m.Entry1ID
+				if *loser == *winner {
+					loser = m.Entry2ID
+				}
+				b.decide(m, winner, loser)
+				b.settle()
+				losses[*loser]++
+				played++
+			}
+
+			champion := b.champion()
+			if champion == nil || *champion != tt.champion {
+				t.Fatalf("champion = %v, want entry %d", champion, tt.champion)
+			}
+			if played != tt.played {
+				t.Errorf("%d matches played, want %d", played, tt.played)
+			}
+			if counts := b.countStatus(); counts[model.BracketPending] != 0 {
+				t.Errorf("%d matches still pending", counts[model.BracketPending])
+			}
+
+			reset := b.matches[len(b.matches)-1]
+			if tt.format == model.FormatDoubleElimination && (reset.Status == model.BracketCompleted) != tt.reset {
+				t.Errorf("grand final reset %s, want played %v", reset.Status, tt.reset)
+			}
+
+			// Everyone but the champion goes out on their last allowed loss
+			allowed := 1
+			if tt.format == model.FormatDoubleElimination {
+				allowed = 2
+			}
+			for id := int64(1); id <= int64(tt.entries); id++ {
+				if id != *champion && losses[id] != allowed {
+					t.Errorf("entry %d lost %d times, want %d", id, losses[id], allowed)
+				}
+			}
+		})
+	}
+}
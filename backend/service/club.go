@@ -0,0 +1,305 @@
+package service
+
+import (
+	"backend/database"
+	"backend/model"
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrClubNotFound  = errors.New("club not found")
+	ErrClubExists    = errors.New("club slug already exists")
+	ErrInvalidClub   = errors.New("invalid club details")
+	ErrNotClubMember = errors.New("user is not a member of this club")
+	ErrInvalidRole   = errors.New("invalid role")
+	ErrLastClubAdmin = errors.New("club must keep at least one admin")
+	ErrAlreadyMember = errors.New("user is already a member of this club")
+	ErrNoInvite      = errors.New("no pending invite to this club")
+)
+
+var clubSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,49}$`)
+
+// ClubService manages club tenants and their memberships
+type ClubService struct {
+	audit     *AuditLog
+	store     database.Store
+	defaultID int64
+}
+
+// NewClubService creates a new club service
+func NewClubService(audit *AuditLog, store database.Store) *ClubService {
+	svc := &ClubService{
+		audit: audit,
+		store: store,
+	}
+
+	if club, err := store.Clubs().GetBySlug(context.Background(), model.DefaultClubSlug); err == nil {
+		svc.defaultID = club.ID
+	}
+
+	return svc
+}
+
+// DefaultID returns the ID of the club used when a request does not name one
+func (s *ClubService) DefaultID() int64 {
+	return s.defaultID
+}
+
+// List returns every club
+func (s *ClubService) List() ([]model.Club, error) {
+	return s.store.Clubs().List(context.Background())
+}
+
+// ListForUser returns the clubs a user belongs to with their role in each
+func (s *ClubService) ListForUser(userID int64) ([]model.ClubMembership, error) {
+	return s.store.Clubs().ListForUser(context.Background(), userID)
+}
+
+// GetByID returns a club by ID
+func (s *ClubService) GetByID(clubID int64) (*model.Club, error) {
+	club, err := s.store.Clubs().Get(context.Background(), clubID)
+	if err == database.ErrNotFound {
+		return nil, ErrClubNotFound
+	}
+	return club, err
+}
+
+// Create registers a new club and makes its creator the club admin
+func (s *ClubService) Create(actor model.Actor, req model.CreateClubRequest) (*model.Club, error) {
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	name := strings.TrimSpace(req.Name)
+	if !clubSlugPattern.MatchString(slug) || name == "" || len(name) > 255 {
+		return nil, ErrInvalidClub
+	}
+
+	ctx := context.Background()
+
+	club := model.Club{Slug: slug, Name: name}
+	err := s.store.WithTx(ctx, func(tx database.Store) error {
+		if err := tx.Clubs().Create(ctx, &club); err != nil {
+			return err
+		}
+		return tx.Clubs().SetMember(ctx, club.ID, actor.UserID, model.RoleAdmin)
+	})
+	if err == database.ErrDuplicate {
+		return nil, ErrClubExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, club.ID, model.AuditClubCreated, "club", club.ID, nil, club)
+
+	return &club, nil
+}
+
+// Membership returns a user's membership in a club
+func (s *ClubService) Membership(clubID, userID int64) (*model.ClubMember, error) {
+	member, err := s.store.Clubs().Member(context.Background(), clubID, userID)
+	if err == database.ErrNotFound {
+		return nil, ErrNotClubMember
+	}
+	return member, err
+}
+
+// Members lists a club's members
+func (s *ClubService) Members(clubID int64) ([]model.ClubMember, error) {
+	return s.store.Clubs().Members(context.Background(), clubID)
+}
+
+// SetMember changes an existing member's role in a club. Users join a club only by
+// accepting an invite, so club admins cannot pull other accounts into their club.
+func (s *ClubService) SetMember(actor model.Actor, clubID int64, req model.ClubMemberRequest) (*model.ClubMember, error) {
+	role, err := clubRole(req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	before, err := s.Membership(clubID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if role != model.RoleAdmin {
+		if err := checkNotLastClubAdmin(ctx, s.store, clubID, req.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.store.Clubs().SetMember(ctx, clubID, req.UserID, role); err != nil {
+		return nil, err
+	}
+
+	member, err := s.Membership(clubID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, clubID, model.AuditMemberSet, "user", req.UserID, before, member)
+
+	return member, nil
+}
+
+// InviteMember invites a user to join a club with a role. The user becomes a member
+// once they accept; inviting them again replaces the earlier invite.
+func (s *ClubService) InviteMember(actor model.Actor, clubID int64, req model.ClubMemberRequest) (*model.ClubInvite, error) {
+	role, err := clubRole(req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	club, err := s.GetByID(clubID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.store.Users().GetByID(ctx, req.UserID); err == database.ErrNotFound {
+		return nil, ErrUserNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.Membership(clubID, req.UserID); err == nil {
+		return nil, ErrAlreadyMember
+	} else if err != ErrNotClubMember {
+		return nil, err
+	}
+
+	invite := &model.ClubInvite{
+		ClubID:   clubID,
+		ClubName: club.Name,
+		UserID:   req.UserID,
+		Role:     role,
+	}
+	if actor.UserID != 0 {
+		invite.InvitedBy = &actor.UserID
+	}
+
+	if err := s.store.Clubs().Invite(ctx, invite); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, clubID, model.AuditMemberInvited, "user", req.UserID, nil, invite)
+
+	return invite, nil
+}
+
+// Invites lists a user's pending club invites
+func (s *ClubService) Invites(userID int64) ([]model.ClubInvite, error) {
+	return s.store.Clubs().Invites(context.Background(), userID)
+}
+
+// AcceptInvite makes the actor a member of the club they were invited to
+func (s *ClubService) AcceptInvite(actor model.Actor, clubID int64) (*model.ClubMember, error) {
+	ctx := context.Background()
+
+	err := s.store.WithTx(ctx, func(tx database.Store) error {
+		invite, err := tx.Clubs().GetInvite(ctx, clubID, actor.UserID)
+		if err == database.ErrNotFound {
+			return ErrNoInvite
+		}
+		if err != nil {
+			return err
+		}
+
+		// Joining never lowers the role of someone who is already a member
+		if _, err := tx.Clubs().Member(ctx, clubID, actor.UserID); err == database.ErrNotFound {
+			if err := tx.Clubs().SetMember(ctx, clubID, actor.UserID, invite.Role); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		return tx.Clubs().DeleteInvite(ctx, clubID, actor.UserID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.Membership(clubID, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, clubID, model.AuditMemberJoined, "user", actor.UserID, nil, member)
+
+	return member, nil
+}
+
+// DeclineInvite discards the actor's invite to a club
+func (s *ClubService) DeclineInvite(actor model.Actor, clubID int64) error {
+	err := s.store.Clubs().DeleteInvite(context.Background(), clubID, actor.UserID)
+	if err == database.ErrNotFound {
+		return ErrNoInvite
+	}
+	return err
+}
+
+// RemoveMember takes a user out of a club
+func (s *ClubService) RemoveMember(actor model.Actor, clubID, userID int64) error {
+	ctx := context.Background()
+
+	if err := checkNotLastClubAdmin(ctx, s.store, clubID, userID); err != nil {
+		return err
+	}
+
+	before, err := s.Membership(clubID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.Clubs().RemoveMember(ctx, clubID, userID); err == database.ErrNotFound {
+		return ErrNotClubMember
+	} else if err != nil {
+		return err
+	}
+
+	s.audit.Record(actor, clubID, model.AuditMemberRemoved, "user", userID, before, nil)
+
+	return nil
+}
+
+// clubRole validates a requested club role, defaulting to player
+func clubRole(role model.Role) (model.Role, error) {
+	switch role {
+	case model.RolePlayer, model.RoleOrganizer, model.RoleAdmin:
+		return role, nil
+	case "":
+		return model.RolePlayer, nil
+	default:
+		return "", ErrInvalidRole
+	}
+}
+
+// checkNotLastClubAdmin stops a club from losing its only admin
+func checkNotLastClubAdmin(ctx context.Context, repo database.Store, clubID, userID int64) error {
+	members, err := repo.Clubs().Members(ctx, clubID)
+	if err != nil {
+		return err
+	}
+
+	otherAdmins, isAdmin := 0, false
+	for _, m := range members {
+		if m.Role != model.RoleAdmin {
+			continue
+		}
+		if m.UserID == userID {
+			isAdmin = true
+		} else {
+			otherAdmins++
+		}
+	}
+
+	if isAdmin && otherAdmins == 0 {
+		return ErrLastClubAdmin
+	}
+	return nil
+}
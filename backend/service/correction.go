@@ -0,0 +1,132 @@
+package service
+
+import (
+	"backend/database"
+	"backend/model"
+	"context"
+	"errors"
+)
+
+var (
+	ErrMatchNotFinished = errors.New("match has no result to correct")
+	ErrMatchVoided      = errors.New("match has been voided")
+)
+
+// CorrectResult replaces the scores of a finished match (organizer only). Stats, streaks
+// and ratings end up as if the corrected scores had been recorded in the first place.
+func (s *MatchService) CorrectResult(actor model.Actor, clubID, matchID int64, req model.CorrectResultRequest) (*model.Match, error) {
+	return s.amend(actor, clubID, matchID, model.CorrectionEdit, req.Reason,
+		func(match *model.Match) (string, []model.GameScore, error) {
+			winner, err := ValidateScores(match.Scoring, req.Scores)
+			if err != nil {
+				return "", nil, err
+			}
+			return string(winner), req.Scores, nil
+		})
+}
+
+// VoidMatch strikes a finished match from the record (organizer only). Its scores are kept
+// for reference, but it no longer counts towards anyone's stats or rating. A voided
+// round-robin fixture is put back to be played again.
+func (s *MatchService) VoidMatch(actor model.Actor, clubID, matchID int64, reason string) (*model.Match, error) {
+	return s.amend(actor, clubID, matchID, model.CorrectionVoid, reason,
+		func(match *model.Match) (string, []model.GameScore, error) {
+			return string(model.MatchResultVoid), match.Scores, nil
+		})
+}
+
+// Corrections returns the changes made to one of a club's matches, oldest first
+func (s *MatchService) Corrections(clubID, matchID int64) ([]model.MatchCorrection, error) {
+	return s.store.Matches().Corrections(context.Background(), clubID, matchID)
+}
+
+// amend changes the result of a finished match to what change returns, keeps a history
+// entry and replays the ratings and records it affects, all in one transaction
+func (s *MatchService) amend(actor model.Actor, clubID, matchID int64, kind model.CorrectionKind, reason string,
+	change func(match *model.Match) (string, []model.GameScore, error)) (*model.Match, error) {
+	ctx := context.Background()
+
+	var match *model.Match
+	var before model.Match
+
+	err := s.store.WithTx(ctx, func(tx database.Store) error {
+		var err error
+		match, err = tx.Matches().GetForUpdate(ctx, clubID, matchID)
+		if err == database.ErrNotFound {
+			return ErrMatchNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		switch match.Result {
+		case string(model.MatchResultPending):
+			return ErrMatchNotFinished
+		case string(model.MatchResultVoid):
+			return ErrMatchVoided
+		}
+		before = *match
+
+		result, scores, err := change(match)
+		if err != nil {
+			return err
+		}
+
+		// Later bracket matches were drawn from this winner, so only the scores may change
+		if result != before.Result {
+			if _, err := tx.Tournaments().BracketMatchFor(ctx, matchID); err == nil {
+				return ErrBracketResultLocked
+			} else if err != database.ErrNotFound {
+				return err
+			}
+		}
+
+		if err := tx.Matches().Amend(ctx, matchID, result, scores); err != nil {
+			return err
+		}
+
+		correction := model.MatchCorrection{
+			MatchID:      matchID,
+			Kind:         kind,
+			Reason:       reason,
+			ResultBefore: before.Result,
+			ResultAfter:  result,
+			ScoresBefore: before.Scores,
+			ScoresAfter:  numberGames(scores),
+		}
+		if actor.UserID != 0 {
+			correction.CorrectedBy = &actor.UserID
+		}
+		if err := tx.Matches().AddCorrection(ctx, &correction); err != nil {
+			return err
+		}
+
+		match.Result = result
+		match.Scores = correction.ScoresAfter
+		if err := amendFixture(ctx, tx, match); err != nil {
+			return err
+		}
+		return s.userService.replayFrom(ctx, tx, match)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	action := model.AuditMatchCorrected
+	if kind == model.CorrectionVoid {
+		action = model.AuditMatchVoided
+	}
+	s.audit.Record(actor, clubID, action, "match", matchID, before, match)
+	s.events.Publish(clubID, model.EventMatchCorrected, match)
+
+	return match, nil
+}
+
+// numberGames returns the scores with games numbered from 1
+func numberGames(scores []model.GameScore) []model.GameScore {
+	numbered := make([]model.GameScore, len(scores))
+	for i, score := range scores {
+		numbered[i] = model.GameScore{Game: i + 1, Team1Score: score.Team1Score, Team2Score: score.Team2Score}
+	}
+	return numbered
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"backend/database"
+	"backend/model"
+	"math"
+	"testing"
+)
+
+// rotation pairs four players differently in each match so every result feeds later ratings
+var rotation = [][2][2]int{
+	{{0, 1}, {2, 3}},
+	{{0, 2}, {1, 3}},
+	{{0, 3}, {1, 2}},
+}
+
+// playRotation records one match per rotation entry with the given scores, skipping nil ones
+func (ts *testServices) playRotation(t *testing.T, players []int64, scores [][]model.GameScore) []int64 {
+	t.Helper()
+
+	club := database.MemoryDefaultClubID
+	matchIDs := make([]int64, len(scores))
+	for i, s := range scores {
+		if s == nil {
+			continue
+		}
+		teams := rotation[i]
+		match, err := ts.matches.Create(model.Actor{}, club, 0, "Court 1",
+			[]int64{players[teams[0][0]], players[teams[0][1]]}, []int64{players[teams[1][0]], players[teams[1][1]]}, nil)
+		if err != nil {
+			t.Fatalf("create match %d: %v", i, err)
+		}
+		if _, err := ts.matches.RecordResult(model.Actor{}, club, match.ID, s); err != nil {
+			t.Fatalf("record match %d: %v", i, err)
+		}
+		matchIDs[i] = match.ID
+	}
+	return matchIDs
+}
+
+// sameStats fails unless both sets of players ended with the same record and rating
+func sameStats(t *testing.T, got, want *testServices, gotPlayers, wantPlayers []int64) {
+	t.Helper()
+
+	for i := range gotPlayers {
+		g, err := got.users.GetStats(gotPlayers[i])
+		if err != nil {
+			t.Fatalf("stats: %v", err)
+		}
+		w, err := want.users.GetStats(wantPlayers[i])
+		if err != nil {
+			t.Fatalf("stats: %v", err)
+		}
+		if g.TotalMatches != w.TotalMatches || g.Wins != w.Wins || g.CurrentStreak != w.CurrentStreak || g.BestStreak != w.BestStreak {
+			t.Errorf("player %d record: %d matches, %d wins, streak %d/%d; want %d, %d, %d/%d", i,
+				g.TotalMatches, g.Wins, g.CurrentStreak, g.BestStreak, w.TotalMatches, w.Wins, w.CurrentStreak, w.BestStreak)
+		}
+		if math.Abs(g.Rating-w.Rating) > 1e-6 || math.Abs(g.RatingDev-w.RatingDev) > 1e-6 {
+			t.Errorf("player %d rating %.4f±%.4f, want %.4f±%.4f", i, g.Rating, g.RatingDev, w.Rating, w.RatingDev)
+		}
+	}
+}
+
+func TestCorrectionReplaysLaterMatches(t *testing.T) {
+	won := games([2]int{21, 15}, [2]int{21, 18})
+	lost := games([2]int{15, 21}, [2]int{18, 21})
+	close := games([2]int{21, 19}, [2]int{19, 21}, [2]int{22, 20})
+
+	tests := []struct {
+		name      string
+		amend     int
+		corrected []model.GameScore // nil voids the match
+	}{
+		{"flip the first result", 0, lost},
+		{"closer scores, same winner", 0, close},
+		{"flip the middle result", 1, lost},
+		{"flip the last result", 2, lost},
+		{"void the first match", 0, nil},
+		{"void the middle match", 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			played := [][]model.GameScore{won, won, won}
+			expected := [][]model.GameScore{won, won, won}
+			expected[tt.amend] = tt.corrected
+
+			ts := newTestServices(t)
+			ts.addCourt(t, "Court 1")
+			players := ts.addPlayers(t, 4)
+			matchIDs := ts.playRotation(t, players, played)
+
+			club := database.MemoryDefaultClubID
+			var err error
+			if tt.corrected == nil {
+				_, err = ts.matches.VoidMatch(model.Actor{}, club, matchIDs[tt.amend], "entered twice")
+			} else {
+				_, err = ts.matches.CorrectResult(model.Actor{}, club, matchIDs[tt.amend], model.CorrectResultRequest{Scores: tt.corrected})
+			}
+			if err != nil {
+				t.Fatalf("amend match %d: %v", tt.amend, err)
+			}
+
+			want := newTestServices(t)
+			want.addCourt(t, "Court 1")
+			wantPlayers := want.addPlayers(t, 4)
+			want.playRotation(t, wantPlayers, expected)
+
+			sameStats(t, ts, want, players, wantPlayers)
+
+			// Rebuilding everything from scratch lands in the same place
+			if _, err := ts.users.RecomputeAllStats(model.Actor{}); err != nil {
+				t.Fatalf("recompute all: %v", err)
+			}
+			sameStats(t, ts, want, players, wantPlayers)
+		})
+	}
+}
+
+func TestAmendRejects(t *testing.T) {
+	ts := newTestServices(t)
+	club := database.MemoryDefaultClubID
+	ts.addCourt(t, "Court 1")
+	players := ts.addPlayers(t, 4)
+	flipped := games([2]int{15, 21}, [2]int{18, 21})
+
+	finished := ts.playRotation(t, players, [][]model.GameScore{straightGames})[0]
+
+	voided := ts.playRotation(t, players, [][]model.GameScore{straightGames})[0]
+	if _, err := ts.matches.VoidMatch(model.Actor{}, club, voided, "no-show"); err != nil {
+		t.Fatalf("void: %v", err)
+	}
+
+	// A decided bracket match has already sent its winner on
+	cup, err := ts.tournaments.Create(model.Actor{}, club, model.TournamentRequest{
+		Name: "Cup", Format: model.FormatSingleElimination, EntryType: model.EntrySingles,
+	})
+	if err != nil {
+		t.Fatalf("create cup: %v", err)
+	}
+	for _, id := range players[:2] {
+		if _, err := ts.tournaments.AddEntry(model.Actor{}, club, cup.ID, model.TournamentEntryRequest{Players: []int64{id}}); err != nil {
+			t.Fatalf("add entry: %v", err)
+		}
+	}
+	drawn, err := ts.tournaments.Start(model.Actor{}, club, cup.ID)
+	if err != nil {
+		t.Fatalf("start cup: %v", err)
+	}
+	final, err := ts.tournaments.StartMatch(model.Actor{}, club, cup.ID, drawn.Matches[0].ID, "Court 1")
+	if err != nil {
+		t.Fatalf("start final: %v", err)
+	}
+	if _, err := ts.matches.RecordResult(model.Actor{}, club, final.ID, straightGames); err != nil {
+		t.Fatalf("record final: %v", err)
+	}
+
+	open, err := ts.matches.Create(model.Actor{}, club, 0, "Court 1", players[:2], players[2:], nil)
+	if err != nil {
+		t.Fatalf("create match: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		matchID int64
+		scores  []model.GameScore
+		want    error
+	}{
+		{"unknown match", 999999, flipped, ErrMatchNotFound},
+		{"match still being played", open.ID, flipped, ErrMatchNotFinished},
+		{"voided match", voided, flipped, ErrMatchVoided},
+		{"bracket winner changed", final.ID, flipped, ErrBracketResultLocked},
+		{"bracket scores with the same winner", final.ID, games([2]int{21, 19}, [2]int{21, 19}), nil},
+		{"finished match", finished, flipped, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ts.matches.CorrectResult(model.Actor{}, club, tt.matchID, model.CorrectResultRequest{Scores: tt.scores})
+			if err != tt.want {
+				t.Errorf("CorrectResult = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := ts.matches.CorrectResult(model.Actor{}, club, finished, model.CorrectResultRequest{Scores: games([2]int{21, 20})}); err == nil {
+		t.Errorf("correcting to invalid scores succeeded")
+	} else if _, ok := err.(*ScoreError); !ok {
+		t.Errorf("correcting to invalid scores = %v, want a *ScoreError", err)
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"backend/database"
+	"backend/model"
+	"context"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrCourtNotFound    = errors.New("court not found")
+	ErrCourtExists      = errors.New("court name already exists")
+	ErrCourtInactive    = errors.New("court is not available")
+	ErrCourtBusy        = errors.New("court already has a pending match")
+	ErrInvalidCourt     = errors.New("invalid court details")
+	ErrNoCourtAvailable = errors.New("no court available")
+)
+
+// CourtService manages the court registry
+type CourtService struct {
+	events *EventBroker
+	audit  *AuditLog
+	store  database.Store
+}
+
+// NewCourtService creates a new court service
+func NewCourtService(events *EventBroker, audit *AuditLog, store database.Store) *CourtService {
+	return &CourtService{
+		events: events,
+		audit:  audit,
+		store:  store,
+	}
+}
+
+// List returns a club's registered courts, optionally including those under maintenance
+func (s *CourtService) List(clubID int64, activeOnly bool) ([]model.Court, error) {
+	return s.store.Courts().List(context.Background(), clubID, activeOnly)
+}
+
+// GetByID returns one of a club's courts by ID
+func (s *CourtService) GetByID(clubID, courtID int64) (*model.Court, error) {
+	court, err := s.store.Courts().Get(context.Background(), clubID, courtID)
+	if err == database.ErrNotFound {
+		return nil, ErrCourtNotFound
+	}
+	return court, err
+}
+
+// GetByName returns one of a club's courts by its name
+func (s *CourtService) GetByName(clubID int64, name string) (*model.Court, error) {
+	court, err := s.store.Courts().GetByName(context.Background(), clubID, strings.TrimSpace(name))
+	if err == database.ErrNotFound {
+		return nil, ErrCourtNotFound
+	}
+	return court, err
+}
+
+// Create registers a new court for a club
+func (s *CourtService) Create(actor model.Actor, clubID int64, req model.CreateCourtRequest) (*model.Court, error) {
+	if req.Status == "" {
+		req.Status = string(model.CourtStatusActive)
+	}
+	if req.Surface == "" {
+		req.Surface = string(model.SurfaceSynthetic)
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if err := validateCourt(name, req.Status, req.Surface); err != nil {
+		return nil, err
+	}
+
+	c := model.Court{
+		Name:    name,
+		Venue:   strings.TrimSpace(req.Venue),
+		Status:  model.CourtStatus(req.Status),
+		Surface: model.CourtSurface(req.Surface),
+	}
+	err := s.store.Courts().Create(context.Background(), clubID, &c)
+	if err == database.ErrDuplicate {
+		return nil, ErrCourtExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(clubID, model.EventCourtCreated, c)
+	s.audit.Record(actor, clubID, model.AuditCourtCreated, "court", c.ID, nil, c)
+
+	return &c, nil
+}
+
+// Update changes a court's details or its active/maintenance state
+func (s *CourtService) Update(actor model.Actor, clubID, courtID int64, req model.UpdateCourtRequest) (*model.Court, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := validateCourt(name, req.Status, req.Surface); err != nil {
+		return nil, err
+	}
+
+	before, err := s.GetByID(clubID, courtID)
+	if err != nil {
+		return nil, err
+	}
+
+	c := model.Court{
+		ID:      courtID,
+		Name:    name,
+		Venue:   strings.TrimSpace(req.Venue),
+		Status:  model.CourtStatus(req.Status),
+		Surface: model.CourtSurface(req.Surface),
+	}
+	ctx := context.Background()
+	err = s.store.WithTx(ctx, func(tx database.Store) error {
+		return tx.Courts().Update(ctx, clubID, &c)
+	})
+	if err == database.ErrNotFound {
+		return nil, ErrCourtNotFound
+	}
+	if err == database.ErrDuplicate {
+		return nil, ErrCourtExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(clubID, model.EventCourtUpdated, c)
+	s.audit.Record(actor, clubID, model.AuditCourtUpdated, "court", c.ID, before, c)
+
+	return &c, nil
+}
+
+// Delete removes a court from a club's registry
+func (s *CourtService) Delete(actor model.Actor, clubID, courtID int64) error {
+	before, err := s.GetByID(clubID, courtID)
+	if err != nil {
+		return err
+	}
+
+	err = s.store.Courts().Delete(context.Background(), clubID, courtID)
+	if err == database.ErrNotFound {
+		return ErrCourtNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	s.events.Publish(clubID, model.EventCourtDeleted, map[string]int64{"id": courtID})
+	s.audit.Record(actor, clubID, model.AuditCourtDeleted, "court", courtID, before, nil)
+
+	return nil
+}
+
+// ValidateForMatch checks that a club court exists and is open for play
+func (s *CourtService) ValidateForMatch(clubID int64, name string) (*model.Court, error) {
+	court, err := s.GetByName(clubID, name)
+	if err != nil {
+		return nil, err
+	}
+	if !court.IsActive() {
+		return nil, ErrCourtInactive
+	}
+	return court, nil
+}
+
+// NextAvailable returns a club's first active court without a pending match.
+// When courtIDs is non-empty only those courts are considered.
+func (s *CourtService) NextAvailable(clubID int64, courtIDs []int64) (*model.Court, error) {
+	court, err := s.store.Courts().NextAvailable(context.Background(), clubID, courtIDs)
+	if err == database.ErrNotFound {
+		return nil, ErrNoCourtAvailable
+	}
+	return court, err
+}
+
+func validateCourt(name, status, surface string) error {
+	if name == "" || len(name) > 100 {
+		return ErrInvalidCourt
+	}
+
+	switch model.CourtStatus(status) {
+	case model.CourtStatusActive, model.CourtStatusMaintenance:
+	default:
+		return ErrInvalidCourt
+	}
+
+	switch model.CourtSurface(surface) {
+	case model.SurfaceSynthetic, model.SurfaceWood, model.SurfaceConcrete:
+	default:
+		return ErrInvalidCourt
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"backend/model"
+	"context"
+	"errors"
+)
+
+var (
+	ErrDeviceNotFound = errors.New("device not found")
+)
+
+// ListDevices returns the user's signed-in devices, most recently used first.
+// currentToken is the caller's refresh token, used to flag their own device.
+func (s *AuthService) ListDevices(userID int64, currentToken string) ([]model.Device, error) {
+	ctx := context.Background()
+
+	tokens, err := s.store.Tokens().Active(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := s.currentFamily(ctx, userID, currentToken)
+	devices := make([]model.Device, 0, len(tokens))
+	for _, t := range tokens {
+		devices = append(devices, model.Device{
+			ID:         t.FamilyID,
+			SignedInAt: t.SignedInAt,
+			LastUsedAt: t.CreatedAt,
+			ExpiresAt:  t.ExpiresAt,
+			UserAgent:  t.UserAgent,
+			IP:         t.IP,
+			Current:    current != "" && t.FamilyID == current,
+		})
+	}
+
+	return devices, nil
+}
+
+// RevokeDevice signs one of the user's devices out, reporting whether it was the caller's own
+func (s *AuthService) RevokeDevice(userID int64, deviceID, currentToken string) (bool, error) {
+	ctx := context.Background()
+
+	tokens, err := s.store.Tokens().Active(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, t := range tokens {
+		if t.FamilyID == deviceID {
+			if err := s.store.Tokens().RevokeFamily(ctx, deviceID); err != nil {
+				return false, err
+			}
+			return deviceID == s.currentFamily(ctx, userID, currentToken), nil
+		}
+	}
+
+	return false, ErrDeviceNotFound
+}
+
+// RevokeDevices signs out every device the user has, except the one holding keepToken
+// when it is given
+func (s *AuthService) RevokeDevices(userID int64, keepToken string) error {
+	ctx := context.Background()
+
+	if keep := s.currentFamily(ctx, userID, keepToken); keep != "" {
+		return s.store.Tokens().RevokeOthers(ctx, userID, keep)
+	}
+	return s.store.Tokens().RevokeAllForUser(ctx, userID)
+}
+
+// ListUserDevices returns any user's signed-in devices (platform admin only)
+func (s *AuthService) ListUserDevices(userID int64) ([]model.Device, error) {
+	if _, err := s.GetUserByID(userID); err != nil {
+		return nil, err
+	}
+	return s.ListDevices(userID, "")
+}
+
+// RevokeUserDevice signs out one of any user's devices (platform admin only)
+func (s *AuthService) RevokeUserDevice(actor model.Actor, userID int64, deviceID string) error {
+	if _, err := s.GetUserByID(userID); err != nil {
+		return err
+	}
+	if _, err := s.RevokeDevice(userID, deviceID, ""); err != nil {
+		return err
+	}
+	s.audit.Record(actor, 0, model.AuditUserDevicesRevoke, "user", userID, nil, map[string]string{"device": deviceID})
+	return nil
+}
+
+// RevokeUserDevices signs out every device any user has (platform admin only)
+func (s *AuthService) RevokeUserDevices(actor model.Actor, userID int64) error {
+	if _, err := s.GetUserByID(userID); err != nil {
+		return err
+	}
+	if err := s.RevokeDevices(userID, ""); err != nil {
+		return err
+	}
+	s.audit.Record(actor, 0, model.AuditUserDevicesRevoke, "user", userID, nil, map[string]string{"device": "all"})
+	return nil
+}
+
+// currentFamily returns the family of the user's refresh token, or "" when the token
+// is missing, unusable or belongs to someone else
+func (s *AuthService) currentFamily(ctx context.Context, userID int64, refreshToken string) string {
+	if refreshToken == "" {
+		return ""
+	}
+	token, err := s.store.Tokens().GetByHash(ctx, hashToken(refreshToken))
+	if err != nil || token.UserID != userID || !token.IsValid() {
+		return ""
+	}
+	return token.FamilyID
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"backend/model"
+	"sync"
+	"time"
+)
+
+// subscriberBuffer is how many events a slow client may fall behind before events are dropped
+const subscriberBuffer = 32
+
+// EventBroker fans out real-time events to connected clients
+type EventBroker struct {
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	userID int64
+	clubID int64
+	ch     chan model.Event
+}
+
+// NewEventBroker creates a new event broker
+func NewEventBroker() *EventBroker {
+	return &EventBroker{
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Subscribe registers a client for one club's broadcasts (all clubs when clubID is 0).
+// userID may be 0 for anonymous clients, which only receive broadcast events.
+// Call the returned func to unsubscribe.
+func (b *EventBroker) Subscribe(userID, clubID int64) (<-chan model.Event, func()) {
+	sub := &subscriber{
+		userID: userID,
+		clubID: clubID,
+		ch:     make(chan model.Event, subscriberBuffer),
+	}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, sub)
+			b.mu.Unlock()
+			close(sub.ch)
+		})
+	}
+}
+
+// Publish broadcasts an event to every client following the club
+func (b *EventBroker) Publish(clubID int64, eventType model.EventType, data interface{}) {
+	b.send(model.Event{Type: eventType, Data: data, Time: time.Now(), ClubID: clubID})
+}
+
+// Notify sends an event to a single user's connections
+func (b *EventBroker) Notify(userID int64, eventType model.EventType, data interface{}) {
+	b.send(model.Event{Type: eventType, Data: data, Time: time.Now(), UserID: userID})
+}
+
+func (b *EventBroker) send(event model.Event) {
+	// Services may run without a broker (e.g. CLI tools)
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscribers {
+		if event.UserID != 0 && event.UserID != sub.userID {
+			continue
+		}
+		if event.ClubID != 0 && sub.clubID != 0 && event.ClubID != sub.clubID {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			// Client is not keeping up; drop rather than block the publisher
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package service
+
+import (
+	"backend/database"
+	"backend/model"
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+const (
+	// defaultMinMatches is how many matches a player needs to be ranked by win rate
+	defaultMinMatches = 5
+	// defaultPageSize and maxPageSize bound leaderboard pages
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+var (
+	ErrInvalidSort   = errors.New("invalid leaderboard sort")
+	ErrInvalidWindow = errors.New("invalid leaderboard window")
+	ErrInvalidTier   = errors.New("invalid skill tier")
+	ErrInvalidHand   = errors.New("invalid hand preference")
+)
+
+// LeaderboardService ranks a club's players from their match results
+type LeaderboardService struct {
+	seasonService *SeasonService
+	store         database.Store
+}
+
+// NewLeaderboardService creates a new leaderboard service
+func NewLeaderboardService(seasonSvc *SeasonService, store database.Store) *LeaderboardService {
+	return &LeaderboardService{
+		seasonService: seasonSvc,
+		store:         store,
+	}
+}
+
+// Leaderboard ranks the club's active players by their results in the window. Records are
+// built from the club's decided matches rather than the all-time stats, so voided and
+// corrected matches count as they stand now. Season leaderboards rank by season records
+// and ratings.
+func (s *LeaderboardService) Leaderboard(clubID int64, query model.LeaderboardQuery) (*model.Leaderboard, error) {
+	ctx := context.Background()
+
+	if err := normalizeLeaderboardQuery(&query); err != nil {
+		return nil, err
+	}
+
+	var season *model.Season
+	var err error
+	switch {
+	case query.Season != 0:
+		season, err = s.seasonService.Get(clubID, query.Season)
+		query.Window = model.WindowSeason
+	case query.Window == model.WindowSeason:
+		season, err = s.seasonService.Active(clubID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries map[int64]*model.LeaderboardEntry
+	var since time.Time
+	if season != nil {
+		since = season.StartedAt
+		entries, err = s.seasonEntries(*season)
+	} else {
+		since = windowStart(query.Window, time.Now())
+		entries, err = s.windowEntries(ctx, clubID, since)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.describePlayers(ctx, entries, query); err != nil {
+		return nil, err
+	}
+
+	ranked := make([]model.LeaderboardEntry, 0, len(entries))
+	for _, entry := range entries {
+		if query.Sort == model.SortWinRate && entry.Matches < query.MinMatches {
+			continue
+		}
+		ranked = append(ranked, *entry)
+	}
+	sortLeaderboard(ranked, query.Sort)
+
+	board := &model.Leaderboard{
+		Sort:     query.Sort,
+		Window:   query.Window,
+		Season:   season,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+		Total:    len(ranked),
+		Entries:  []model.LeaderboardEntry{},
+	}
+	if !since.IsZero() {
+		board.Since = &since
+	}
+	if query.Sort == model.SortWinRate {
+		board.MinMatches = query.MinMatches
+	}
+
+	start := (query.Page - 1) * query.PageSize
+	for i := start; i < len(ranked) && i < start+query.PageSize; i++ {
+		ranked[i].Rank = i + 1
+		board.Entries = append(board.Entries, ranked[i])
+	}
+
+	return board, nil
+}
+
+// normalizeLeaderboardQuery fills in defaults and rejects unknown options
+func normalizeLeaderboardQuery(query *model.LeaderboardQuery) error {
+	switch query.Sort {
+	case "":
+		query.Sort = model.SortRating
+	case model.SortRating, model.SortWinRate, model.SortWins, model.SortStreak:
+	default:
+		return ErrInvalidSort
+	}
+
+	switch query.Window {
+	case "":
+		query.Window = model.WindowAll
+	case model.WindowWeek, model.WindowMonth, model.WindowSeason, model.WindowAll:
+	default:
+		return ErrInvalidWindow
+	}
+
+	if _, ok := tierStrength[query.Tier]; query.Tier != "" && !ok {
+		return ErrInvalidTier
+	}
+	if query.Hand != "" && query.Hand != model.HandLeft && query.Hand != model.HandRight {
+		return ErrInvalidHand
+	}
+
+	if query.MinMatches <= 0 {
+		query.MinMatches = defaultMinMatches
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.PageSize <= 0 {
+		query.PageSize = defaultPageSize
+	}
+	if query.PageSize > maxPageSize {
+		query.PageSize = maxPageSize
+	}
+	return nil
+}
+
+// windowStart returns when a leaderboard window begins, zero for all time
+func windowStart(window model.LeaderboardWindow, now time.Time) time.Time {
+	switch window {
+	case model.WindowWeek:
+		return now.AddDate(0, 0, -7)
+	case model.WindowMonth:
+		return now.AddDate(0, 0, -30)
+	}
+	return time.Time{}
+}
+
+// windowEntries builds each player's record from the club's decided matches since the
+// window started, rated as of their last match in it
+func (s *LeaderboardService) windowEntries(ctx context.Context, clubID int64, since time.Time) (map[int64]*model.LeaderboardEntry, error) {
+	matches, err := s.store.Matches().Decided(ctx, database.MatchFilter{ClubID: clubID, Since: since})
+	if err != nil {
+		return nil, err
+	}
+
+	entries, lastMatch := tallyMatches(matches)
+	if err := s.windowRatings(ctx, entries, lastMatch); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// seasonEntries lists the season records of players who have played in the season
+func (s *LeaderboardService) seasonEntries(season model.Season) (map[int64]*model.LeaderboardEntry, error) {
+	records, err := s.seasonService.Standings(season)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[int64]*model.LeaderboardEntry, len(records))
+	for _, r := range records {
+		if r.TotalMatches == 0 {
+			continue
+		}
+		entries[r.UserID] = &model.LeaderboardEntry{
+			UserID:        r.UserID,
+			Matches:       r.TotalMatches,
+			Wins:          r.Wins,
+			Losses:        r.Losses,
+			WinRate:       r.WinRate,
+			CurrentStreak: r.CurrentStreak,
+			BestStreak:    r.BestStreak,
+			Rating:        r.Rating,
+		}
+	}
+	return entries, nil
+}
+
+// tallyMatches builds each player's record from decided matches, oldest first, and notes
+// the last match each player took part in
+func tallyMatches(matches []model.Match) (map[int64]*model.LeaderboardEntry, map[int64]int64) {
+	entries := make(map[int64]*model.LeaderboardEntry)
+	lastMatch := make(map[int64]int64)
+
+	for _, match := range matches {
+		team1Won := match.Result == string(model.MatchResultTeam1)
+		for _, team := range []struct {
+			players []int64
+			won     bool
+		}{{match.Team1, team1Won}, {match.Team2, !team1Won}} {
+			for _, id := range team.players {
+				entry, ok := entries[id]
+				if !ok {
+					entry = &model.LeaderboardEntry{UserID: id}
+					entries[id] = entry
+				}
+				stats := model.UserStats{
+					TotalMatches:  entry.Matches,
+					Wins:          entry.Wins,
+					Losses:        entry.Losses,
+					CurrentStreak: entry.CurrentStreak,
+					BestStreak:    entry.BestStreak,
+				}
+				applyResult(&stats, team.won)
+				entry.Matches = stats.TotalMatches
+				entry.Wins = stats.Wins
+				entry.Losses = stats.Losses
+				entry.WinRate = stats.WinRate
+				entry.CurrentStreak = stats.CurrentStreak
+				entry.BestStreak = stats.BestStreak
+				lastMatch[id] = match.ID
+			}
+		}
+	}
+
+	return entries, lastMatch
+}
+
+// describePlayers fills in names, hand and tier, dropping inactive accounts and players
+// outside the tier and hand filters
+func (s *LeaderboardService) describePlayers(ctx context.Context, entries map[int64]*model.LeaderboardEntry, query model.LeaderboardQuery) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(entries))
+	for id := range entries {
+		ids = append(ids, id)
+	}
+	users, err := s.store.Users().GetMany(ctx, ids)
+	if err != nil {
+		return err
+	}
+	found := make(map[int64]bool, len(users))
+	for _, user := range users {
+		entry := entries[user.ID]
+		if !user.IsActive ||
+			(query.Tier != "" && user.SkillTier != query.Tier) ||
+			(query.Hand != "" && user.HandPreference != query.Hand) {
+			continue
+		}
+		found[user.ID] = true
+		entry.Name = user.Name
+		entry.HandPreference = user.HandPreference
+		entry.SkillTier = user.SkillTier
+	}
+	for id := range entries {
+		if !found[id] {
+			delete(entries, id)
+		}
+	}
+
+	return nil
+}
+
+// windowRatings sets each player's rating to where it stood after their last match in the window
+func (s *LeaderboardService) windowRatings(ctx context.Context, entries map[int64]*model.LeaderboardEntry, lastMatch map[int64]int64) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	matchIDs := make([]int64, 0, len(entries))
+	for id := range entries {
+		matchIDs = append(matchIDs, lastMatch[id])
+	}
+	changes, err := s.store.Stats().RatingChanges(ctx, distinct(matchIDs))
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		if entry, ok := entries[change.UserID]; ok && lastMatch[change.UserID] == change.MatchID {
+			entry.Rating = change.RatingAfter
+		}
+	}
+	for _, entry := range entries {
+		if entry.Rating == 0 {
+			entry.Rating = DefaultRating
+		}
+	}
+
+	return nil
+}
+
+// sortLeaderboard orders players best first. Ties go to the player with more matches,
+// then the lower user ID so pages stay stable.
+func sortLeaderboard(entries []model.LeaderboardEntry, by model.LeaderboardSort) {
+	key := func(e model.LeaderboardEntry) float64 {
+		switch by {
+		case model.SortWinRate:
+			return e.WinRate
+		case model.SortWins:
+			return float64(e.Wins)
+		case model.SortStreak:
+			return float64(e.CurrentStreak)
+		}
+		return e.Rating
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if key(a) != key(b) {
+			return key(a) > key(b)
+		}
+		if a.Matches != b.Matches {
+			return a.Matches > b.Matches
+		}
+		return a.UserID < b.UserID
+	})
+}
@@ -0,0 +1,547 @@
+package service
+
+import (
+	"backend/database"
+	"backend/model"
+	"context"
+	"sort"
+	"time"
+)
+
+// defaultSlotMinutes is how long each round-robin time slot lasts unless the organizer says otherwise
+const defaultSlotMinutes = 30
+
+// StartFixture puts a scheduled round-robin fixture on a court (organizer only), creating
+// the match its result is recorded against. It defaults to the fixture's scheduled court.
+func (s *TournamentService) StartFixture(actor model.Actor, clubID, tournamentID, fixtureID int64, court string) (*model.Match, error) {
+	ctx := context.Background()
+
+	league, err := s.Get(clubID, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	f, err := findFixture(league.Fixtures, fixtureID)
+	if err != nil {
+		return nil, err
+	}
+	if f.Status != model.FixtureScheduled {
+		return nil, ErrFixtureStarted
+	}
+	if court == "" {
+		court = f.Court
+	}
+
+	players := make(map[int64][]int64, len(league.Entries))
+	for _, e := range league.Entries {
+		players[e.ID] = e.Players
+	}
+	match, err := s.matchService.newMatch(clubID, 0, court, players[f.Entry1ID], players[f.Entry2ID], &league.Tournament.Scoring)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.store.WithTx(ctx, func(tx database.Store) error {
+		t, err := s.getForUpdate(ctx, tx, clubID, tournamentID)
+		if err != nil {
+			return err
+		}
+		if t.Status != model.TournamentInProgress {
+			return ErrTournamentNotRunning
+		}
+
+		// Someone else may have started it since it was read
+		fixtures, err := tx.Tournaments().Fixtures(ctx, tournamentID)
+		if err != nil {
+			return err
+		}
+		f, err := findFixture(fixtures, fixtureID)
+		if err != nil {
+			return err
+		}
+		if f.Status != model.FixtureScheduled {
+			return ErrFixtureStarted
+		}
+
+		if err := s.matchService.insertMatch(ctx, tx, match); err != nil {
+			return err
+		}
+
+		f.MatchID = &match.ID
+		f.Status = model.FixturePlaying
+		return tx.Tournaments().UpdateFixture(ctx, *f)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.matchService.announceMatch(actor, match)
+
+	return match, nil
+}
+
+// leagueCourts returns the registered names of the courts a round robin is scheduled on:
+// the ones it was created with, or every active court of the club
+func (s *TournamentService) leagueCourts(t *model.Tournament) ([]string, error) {
+	names := t.Courts
+	if len(names) == 0 {
+		courts, err := s.matchService.courtService.List(t.ClubID, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range courts {
+			names = append(names, c.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, ErrNoCourts
+	}
+
+	registered := make([]string, 0, len(names))
+	for _, name := range names {
+		court, err := s.matchService.courtService.ValidateForMatch(t.ClubID, name)
+		if err != nil {
+			return nil, err
+		}
+		if !containsName(registered, court.Name) {
+			registered = append(registered, court.Name)
+		}
+	}
+	return registered, nil
+}
+
+// drawLeague splits the seeded entries into groups and saves the fixture list
+func drawLeague(ctx context.Context, tx database.Store, t *model.Tournament, entries []model.TournamentEntry, courts []string) error {
+	assignGroups(entries, t.Groups)
+	if err := tx.Tournaments().SeedEntries(ctx, entries); err != nil {
+		return err
+	}
+
+	for _, f := range scheduleFixtures(t, entries, courts) {
+		if err := tx.Tournaments().AddFixture(ctx, &f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assignGroups deals entries in seed order into groups in a snake (1, 2, 3, 3, 2, 1, ...)
+// so every group gets a similar spread of strength
+func assignGroups(entries []model.TournamentEntry, groups int) {
+	for i := range entries {
+		row, col := i/groups, i%groups
+		if row%2 == 1 {
+			col = groups - 1 - col
+		}
+		entries[i].Group = col + 1
+	}
+}
+
+// circleRounds pairs every entry with every other using the circle method: the first
+// entry stays put while the others rotate one place each round. An odd number of entries
+// gets a bye (0) so one of them sits out each round.
+func circleRounds(ids []int64) [][][2]int64 {
+	ring := append([]int64{}, ids...)
+	if len(ring)%2 == 1 {
+		ring = append(ring, 0)
+	}
+	n := len(ring)
+
+	rounds := make([][][2]int64, 0, n-1)
+	for r := 0; r < n-1; r++ {
+		var pairs [][2]int64
+		for i := 0; i < n/2; i++ {
+			a, b := ring[i], ring[n-1-i]
+			if a == 0 || b == 0 {
+				continue
+			}
+			// Alternate sides so the fixed entry is not always listed first
+			if i == 0 && r%2 == 1 {
+				a, b = b, a
+			}
+			pairs = append(pairs, [2]int64{a, b})
+		}
+		rounds = append(rounds, pairs)
+
+		last := ring[n-1]
+		copy(ring[2:], ring[1:n-1])
+		ring[1] = last
+	}
+	return rounds
+}
+
+// pendingFixture is a fixture waiting for a time slot
+type pendingFixture struct {
+	group, round, order int
+	entry1, entry2      int64
+}
+
+// scheduleFixtures lays every group's circle-method rounds out over the courts, one time
+// slot after another. Earlier rounds go first, but a fixture is held back a slot when one
+// of its entries played in the previous slot, unless nothing else could be played then.
+func scheduleFixtures(t *model.Tournament, entries []model.TournamentEntry, courts []string) []model.Fixture {
+	groups := make(map[int][]int64)
+	for _, e := range entries {
+		groups[e.Group] = append(groups[e.Group], e.ID)
+	}
+	var pool []pendingFixture
+	for g := 1; g <= t.Groups; g++ {
+		for r, pairs := range circleRounds(groups[g]) {
+			for i, p := range pairs {
+				pool = append(pool, pendingFixture{group: g, round: r + 1, order: i, entry1: p[0], entry2: p[1]})
+			}
+		}
+	}
+	sort.SliceStable(pool, func(i, j int) bool {
+		if pool[i].round != pool[j].round {
+			return pool[i].round < pool[j].round
+		}
+		if pool[i].order != pool[j].order {
+			return pool[i].order < pool[j].order
+		}
+		return pool[i].group < pool[j].group
+	})
+
+	minutes := t.SlotMinutes
+	if minutes <= 0 {
+		minutes = defaultSlotMinutes
+	}
+
+	var fixtures []model.Fixture
+	previous := map[int64]bool{}
+	for slot := 1; len(pool) > 0; slot++ {
+		playing := map[int64]bool{}
+		var picked []int
+		pick := func(order []int, rested bool) {
+			for _, i := range order {
+				p := pool[i]
+				if len(picked) == len(courts) {
+					return
+				}
+				if playing[p.entry1] || playing[p.entry2] {
+					continue
+				}
+				if rested && (previous[p.entry1] || previous[p.entry2]) {
+					continue
+				}
+				playing[p.entry1], playing[p.entry2] = true, true
+				picked = append(picked, i)
+			}
+		}
+		order := make([]int, len(pool))
+		for i := range order {
+			order[i] = i
+		}
+		pick(order, true)
+		if len(picked) == 0 {
+			// Everyone left played last slot; start with those who have only one entry back on court
+			sort.SliceStable(order, func(a, b int) bool {
+				return backToBack(previous, pool[order[a]]) < backToBack(previous, pool[order[b]])
+			})
+			pick(order, false)
+		}
+
+		var scheduledAt *time.Time
+		if t.StartsAt != nil {
+			at := t.StartsAt.Add(time.Duration(slot-1) * time.Duration(minutes) * time.Minute)
+			scheduledAt = &at
+		}
+
+		taken := make(map[int]bool, len(picked))
+		for c, i := range picked {
+			p := pool[i]
+			taken[i] = true
+			fixtures = append(fixtures, model.Fixture{
+				TournamentID: t.ID,
+				Group:        p.group,
+				Round:        p.round,
+				Slot:         slot,
+				Court:        courts[c],
+				ScheduledAt:  scheduledAt,
+				Entry1ID:     p.entry1,
+				Entry2ID:     p.entry2,
+				Status:       model.FixtureScheduled,
+			})
+		}
+
+		remaining := pool[:0]
+		for i, p := range pool {
+			if !taken[i] {
+				remaining = append(remaining, p)
+			}
+		}
+		pool = remaining
+		previous = playing
+	}
+	return fixtures
+}
+
+// backToBack counts the entries of a fixture that played in the previous slot
+func backToBack(previous map[int64]bool, p pendingFixture) int {
+	n := 0
+	if previous[p.entry1] {
+		n++
+	}
+	if previous[p.entry2] {
+		n++
+	}
+	return n
+}
+
+// leagueStandings ranks each group from its decided fixtures. Entries are ordered by match
+// wins, then game difference, then point difference; entries still level are ordered by
+// the matches between them, and finally by seed.
+func leagueStandings(entries []model.TournamentEntry, fixtures []model.Fixture, decided map[int64]model.Match) []model.GroupStandings {
+	rows := make(map[int64]*model.Standing, len(entries))
+	seeds := make(map[int64]int, len(entries))
+	byGroup := make(map[int][]*model.Standing)
+	groupCount := 0
+	for _, e := range entries {
+		row := &model.Standing{EntryID: e.ID, Names: e.Names}
+		rows[e.ID] = row
+		seeds[e.ID] = e.Seed
+		byGroup[e.Group] = append(byGroup[e.Group], row)
+		if e.Group > groupCount {
+			groupCount = e.Group
+		}
+	}
+
+	// beat[a][b] counts the matches a won against b
+	beat := make(map[int64]map[int64]int)
+	for _, f := range fixtures {
+		if f.MatchID == nil {
+			continue
+		}
+		match, ok := decided[*f.MatchID]
+		if !ok {
+			continue
+		}
+		one, two := rows[f.Entry1ID], rows[f.Entry2ID]
+		if one == nil || two == nil {
+			continue
+		}
+
+		for _, score := range match.Scores {
+			one.PointsFor += score.Team1Score
+			one.PointsAgainst += score.Team2Score
+			two.PointsFor += score.Team2Score
+			two.PointsAgainst += score.Team1Score
+			if score.Team1Score > score.Team2Score {
+				one.GamesFor++
+				two.GamesAgainst++
+			} else {
+				two.GamesFor++
+				one.GamesAgainst++
+			}
+		}
+
+		winner, loser := one, two
+		if match.Result == string(model.MatchResultTeam2) {
+			winner, loser = two, one
+		}
+		winner.Wins++
+		loser.Losses++
+		one.Played++
+		two.Played++
+		if beat[winner.EntryID] == nil {
+			beat[winner.EntryID] = make(map[int64]int)
+		}
+		beat[winner.EntryID][loser.EntryID]++
+	}
+
+	standings := make([]model.GroupStandings, 0, groupCount)
+	for g := 1; g <= groupCount; g++ {
+		group := byGroup[g]
+		for _, row := range group {
+			row.GameDiff = row.GamesFor - row.GamesAgainst
+			row.PointDiff = row.PointsFor - row.PointsAgainst
+		}
+
+		level := func(a, b *model.Standing) bool {
+			return a.Wins == b.Wins && a.GameDiff == b.GameDiff && a.PointDiff == b.PointDiff
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			a, b := group[i], group[j]
+			if a.Wins != b.Wins {
+				return a.Wins > b.Wins
+			}
+			if a.GameDiff != b.GameDiff {
+				return a.GameDiff > b.GameDiff
+			}
+			if a.PointDiff != b.PointDiff {
+				return a.PointDiff > b.PointDiff
+			}
+			return seeds[a.EntryID] < seeds[b.EntryID]
+		})
+
+		// Head-to-head among each run of level entries: wins in the matches between them
+		for start := 0; start < len(group); {
+			end := start + 1
+			for end < len(group) && level(group[start], group[end]) {
+				end++
+			}
+			tied := group[start:end]
+			if len(tied) > 1 {
+				h2h := make(map[int64]int, len(tied))
+				for _, a := range tied {
+					for _, b := range tied {
+						h2h[a.EntryID] += beat[a.EntryID][b.EntryID]
+					}
+				}
+				sort.SliceStable(tied, func(i, j int) bool {
+					if h2h[tied[i].EntryID] != h2h[tied[j].EntryID] {
+						return h2h[tied[i].EntryID] > h2h[tied[j].EntryID]
+					}
+					return seeds[tied[i].EntryID] < seeds[tied[j].EntryID]
+				})
+			}
+			start = end
+		}
+
+		result := model.GroupStandings{Group: g, Standings: make([]model.Standing, len(group))}
+		for i, row := range group {
+			row.Rank = i + 1
+			result.Standings[i] = *row
+		}
+		standings = append(standings, result)
+	}
+	return standings
+}
+
+// decidedFixtures returns the won or lost matches played for the fixtures, by match ID
+func decidedFixtures(ctx context.Context, repo database.Store, fixtures []model.Fixture) (map[int64]model.Match, error) {
+	decided := map[int64]model.Match{}
+
+	var ids []int64
+	for _, f := range fixtures {
+		if f.MatchID != nil {
+			ids = append(ids, *f.MatchID)
+		}
+	}
+	if len(ids) == 0 {
+		return decided, nil
+	}
+
+	matches, err := repo.Matches().DecidedByID(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range matches {
+		decided[m.ID] = m
+	}
+	return decided, nil
+}
+
+// completeFixture marks a just-recorded round-robin fixture played and completes the
+// tournament once every fixture has been. Matches outside a round robin are left alone.
+// It runs inside the transaction that records the result.
+func completeFixture(ctx context.Context, tx database.Store, match *model.Match) error {
+	f, err := tx.Tournaments().FixtureFor(ctx, match.ID)
+	if err == database.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	t, err := tx.Tournaments().GetForUpdate(ctx, match.ClubID, f.TournamentID)
+	if err != nil {
+		return err
+	}
+	if f.Status != model.FixturePlaying {
+		return nil
+	}
+	f.Status = model.FixtureCompleted
+	if err := tx.Tournaments().UpdateFixture(ctx, *f); err != nil {
+		return err
+	}
+
+	return settleLeague(ctx, tx, t)
+}
+
+// amendFixture keeps a round robin in step with a corrected or voided result. A voided
+// fixture is put back to be played again; either way the tournament's completion and
+// winner are worked out afresh. Matches outside a round robin are left alone. It runs
+// inside the transaction that amends the result.
+func amendFixture(ctx context.Context, tx database.Store, match *model.Match) error {
+	f, err := tx.Tournaments().FixtureFor(ctx, match.ID)
+	if err == database.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	t, err := tx.Tournaments().GetForUpdate(ctx, match.ClubID, f.TournamentID)
+	if err != nil {
+		return err
+	}
+	if match.Result == string(model.MatchResultVoid) {
+		f.MatchID = nil
+		f.Status = model.FixtureScheduled
+		if err := tx.Tournaments().UpdateFixture(ctx, *f); err != nil {
+			return err
+		}
+	}
+
+	return settleLeague(ctx, tx, t)
+}
+
+// settleLeague completes a round robin once every fixture has been played, naming the
+// leader as winner when there is a single group, and reopens it when a fixture has to be
+// played again
+func settleLeague(ctx context.Context, tx database.Store, t *model.Tournament) error {
+	fixtures, err := tx.Tournaments().Fixtures(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	for _, f := range fixtures {
+		if f.Status != model.FixtureCompleted {
+			if t.Status != model.TournamentCompleted {
+				return nil
+			}
+			t.Status = model.TournamentInProgress
+			t.CompletedAt = nil
+			t.WinnerEntryID = nil
+			return tx.Tournaments().Update(ctx, t)
+		}
+	}
+
+	if t.Status != model.TournamentCompleted {
+		now := time.Now()
+		t.Status = model.TournamentCompleted
+		t.CompletedAt = &now
+	}
+	t.WinnerEntryID = nil
+	if t.Groups == 1 {
+		entries, err := tx.Tournaments().Entries(ctx, t.ID)
+		if err != nil {
+			return err
+		}
+		decided, err := decidedFixtures(ctx, tx, fixtures)
+		if err != nil {
+			return err
+		}
+		if standings := leagueStandings(entries, fixtures, decided); len(standings) > 0 && len(standings[0].Standings) > 0 {
+			t.WinnerEntryID = &standings[0].Standings[0].EntryID
+		}
+	}
+	return tx.Tournaments().Update(ctx, t)
+}
+
+func findFixture(fixtures []model.Fixture, id int64) (*model.Fixture, error) {
+	for i := range fixtures {
+		if fixtures[i].ID == id {
+			return &fixtures[i], nil
+		}
+	}
+	return nil, ErrFixtureNotFound
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
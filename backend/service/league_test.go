@@ -0,0 +1,281 @@
+package service
+
+import (
+	"backend/model"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAssignGroups(t *testing.T) {
+	tests := []struct {
+		entries, groups int
+		want            []int
+	}{
+		{4, 1, []int{1, 1, 1, 1}},
+		{6, 2, []int{1, 2, 2, 1, 1, 2}},
+		{7, 3, []int{1, 2, 3, 3, 2, 1, 1}},
+		{8, 4, []int{1, 2, 3, 4, 4, 3, 2, 1}},
+	}
+
+	for _, tt := range tests {
+		entries := make([]model.TournamentEntry, tt.entries)
+		assignGroups(entries, tt.groups)
+		got := make([]int, len(entries))
+		for i, e := range entries {
+			got[i] = e.Group
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("assignGroups(%d entries, %d groups) = %v, want %v", tt.entries, tt.groups, got, tt.want)
+		}
+	}
+}
+
+func TestCircleRounds(t *testing.T) {
+	for n := 2; n <= 9; n++ {
+		t.Run(fmt.Sprintf("%d entries", n), func(t *testing.T) {
+			ids := make([]int64, n)
+			for i := range ids {
+				ids[i] = int64(i + 1)
+			}
+			rounds := circleRounds(ids)
+
+			wantRounds := n - 1
+			if n%2 == 1 {
+				wantRounds = n
+			}
+			if len(rounds) != wantRounds {
+				t.Fatalf("%d rounds, want %d", len(rounds), wantRounds)
+			}
+
+			met := make(map[[2]int64]int)
+			satOut := make(map[int64]int)
+			for r, pairs := range rounds {
+				if len(pairs) != n/2 {
+					t.Errorf("round %d has %d fixtures, want %d", r+1, len(pairs), n/2)
+				}
+				playing := make(map[int64]bool)
+				for _, p := range pairs {
+					if playing[p[0]] || playing[p[1]] || p[0] == p[1] {
+						t.Errorf("round %d plays an entry twice: %v", r+1, pairs)
+					}
+					playing[p[0]], playing[p[1]] = true, true
+					met[pairKey(p[0], p[1])]++
+				}
+				for _, id := range ids {
+					if !playing[id] {
+						satOut[id]++
+					}
+				}
+			}
+
+			if len(met) != n*(n-1)/2 {
+				t.Errorf("%d distinct pairings, want %d", len(met), n*(n-1)/2)
+			}
+			for pair, count := range met {
+				if count != 1 {
+					t.Errorf("%v meet %d times", pair, count)
+				}
+			}
+			for _, id := range ids {
+				if want := n % 2; satOut[id] != want {
+					t.Errorf("entry %d sat out %d rounds, want %d", id, satOut[id], want)
+				}
+			}
+		})
+	}
+}
+
+func TestScheduleFixtures(t *testing.T) {
+	tests := []struct {
+		name       string
+		entries    int
+		groups     int
+		courts     int
+		slots      int
+		backToBack int // entries on court in consecutive slots
+	}{
+		{"six entries rest between fixtures", 6, 1, 1, 15, 0},
+		// With one court, two of four entries sit out each slot and must come straight back
+		// whenever the next pairing mixes the two halves: twice is the least possible
+		{"four entries, one court", 4, 1, 1, 6, 2},
+		{"three entries cannot rest", 3, 1, 1, 3, 2},
+		// Every entry plays every slot when the courts fit a whole round
+		{"courts for a whole round", 4, 1, 4, 3, 8},
+		{"eight entries keep two courts busy", 8, 1, 2, 14, 4},
+		{"two groups share the courts", 8, 2, 2, 6, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := make([]model.TournamentEntry, tt.entries)
+			for i := range entries {
+				entries[i].ID = int64(i + 1)
+			}
+			assignGroups(entries, tt.groups)
+			group := make(map[int64]int, len(entries))
+			for _, e := range entries {
+				group[e.ID] = e.Group
+			}
+			courts := make([]string, tt.courts)
+			for i := range courts {
+				courts[i] = fmt.Sprintf("Court %d", i+1)
+			}
+			start := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+			league := &model.Tournament{Groups: tt.groups, StartsAt: &start}
+
+			fixtures := scheduleFixtures(league, entries, courts)
+
+			bySlot := make(map[int][]model.Fixture)
+			met := make(map[[2]int64]bool)
+			for _, f := range fixtures {
+				bySlot[f.Slot] = append(bySlot[f.Slot], f)
+				met[pairKey(f.Entry1ID, f.Entry2ID)] = true
+				if group[f.Entry1ID] != group[f.Entry2ID] || f.Group != group[f.Entry1ID] {
+					t.Errorf("fixture %d v %d crosses groups", f.Entry1ID, f.Entry2ID)
+				}
+				if want := start.Add(time.Duration(f.Slot-1) * defaultSlotMinutes * time.Minute); f.ScheduledAt == nil || !f.ScheduledAt.Equal(want) {
+					t.Errorf("slot %d scheduled at %v, want %v", f.Slot, f.ScheduledAt, want)
+				}
+			}
+
+			perGroup := tt.entries / tt.groups
+			if want := tt.groups * perGroup * (perGroup - 1) / 2; len(fixtures) != want || len(met) != want {
+				t.Errorf("%d fixtures over %d pairings, want %d", len(fixtures), len(met), want)
+			}
+			if len(bySlot) != tt.slots {
+				t.Errorf("%d slots, want %d", len(bySlot), tt.slots)
+			}
+
+			backToBack := 0
+			previous := map[int64]bool{}
+			for slot := 1; slot <= len(bySlot); slot++ {
+				if len(bySlot[slot]) > tt.courts {
+					t.Errorf("slot %d has %d fixtures on %d courts", slot, len(bySlot[slot]), tt.courts)
+				}
+				playing := map[int64]bool{}
+				for _, f := range bySlot[slot] {
+					for _, id := range []int64{f.Entry1ID, f.Entry2ID} {
+						if playing[id] {
+							t.Errorf("entry %d plays twice in slot %d", id, slot)
+						}
+						playing[id] = true
+						if previous[id] {
+							backToBack++
+						}
+					}
+				}
+				previous = playing
+			}
+			if backToBack != tt.backToBack {
+				t.Errorf("%d back-to-back appearances, want %d", backToBack, tt.backToBack)
+			}
+		})
+	}
+}
+
+func TestLeagueStandings(t *testing.T) {
+	type result struct {
+		entry1, entry2 int64
+		scores         []model.GameScore
+	}
+	// entries lists [ID, seed, group]; want is each group's entry IDs in rank order
+	tests := []struct {
+		name    string
+		entries [][3]int
+		results []result
+		want    [][]int64
+	}{
+		{"match wins first", [][3]int{{1, 1, 1}, {2, 2, 1}, {3, 3, 1}},
+			[]result{
+				{1, 2, games([2]int{21, 19}, [2]int{19, 21}, [2]int{22, 20})},
+				{3, 1, games([2]int{21, 5}, [2]int{21, 5})},
+				{3, 2, games([2]int{21, 5}, [2]int{21, 5})},
+			},
+			[][]int64{{3, 1, 2}}},
+		{"game difference between level wins", [][3]int{{1, 1, 1}, {2, 2, 1}, {3, 3, 1}},
+			[]result{
+				{1, 2, games([2]int{21, 15}, [2]int{21, 15})},
+				{2, 3, games([2]int{21, 15}, [2]int{15, 21}, [2]int{21, 15})},
+				{3, 1, games([2]int{21, 15}, [2]int{15, 21}, [2]int{21, 15})},
+			},
+			[][]int64{{1, 3, 2}}},
+		{"point difference between level games", [][3]int{{1, 1, 1}, {2, 2, 1}, {3, 3, 1}},
+			[]result{
+				{1, 2, games([2]int{21, 10}, [2]int{21, 10})},
+				{2, 3, games([2]int{21, 19}, [2]int{21, 19})},
+				{3, 1, games([2]int{21, 19}, [2]int{21, 19})},
+			},
+			[][]int64{{1, 3, 2}}},
+		{"head-to-head between level entries", [][3]int{{1, 1, 1}, {2, 2, 1}, {3, 3, 1}, {4, 4, 1}},
+			[]result{
+				{2, 1, straightGames},
+				{1, 3, straightGames},
+				{1, 4, straightGames},
+				{2, 3, straightGames},
+				{4, 2, straightGames},
+				{3, 4, straightGames},
+			},
+			[][]int64{{2, 1, 3, 4}}},
+		{"seed when head-to-head is level too", [][3]int{{1, 3, 1}, {2, 1, 1}, {3, 2, 1}},
+			[]result{
+				{1, 2, straightGames},
+				{2, 3, straightGames},
+				{3, 1, straightGames},
+			},
+			[][]int64{{2, 3, 1}}},
+		{"groups are ranked apart", [][3]int{{1, 1, 1}, {2, 2, 2}, {3, 3, 2}, {4, 4, 1}},
+			[]result{
+				{4, 1, straightGames},
+				{2, 3, straightGames},
+			},
+			[][]int64{{4, 1}, {2, 3}}},
+		{"nothing played yet", [][3]int{{1, 2, 1}, {2, 1, 1}}, nil, [][]int64{{2, 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := make([]model.TournamentEntry, len(tt.entries))
+			for i, e := range tt.entries {
+				entries[i] = model.TournamentEntry{ID: int64(e[0]), Seed: e[1], Group: e[2]}
+			}
+
+			var fixtures []model.Fixture
+			decided := make(map[int64]model.Match)
+			for i, r := range tt.results {
+				winner, err := ValidateScores(model.DefaultScoringRules(), r.scores)
+				if err != nil {
+					t.Fatalf("result %d: %v", i, err)
+				}
+				matchID := int64(100 + i)
+				fixtures = append(fixtures, model.Fixture{Entry1ID: r.entry1, Entry2ID: r.entry2, MatchID: &matchID})
+				decided[matchID] = model.Match{ID: matchID, Scores: r.scores, Result: string(winner)}
+			}
+			// A fixture on court and one not yet started count for nobody
+			onCourt := int64(999)
+			fixtures = append(fixtures,
+				model.Fixture{Entry1ID: entries[0].ID, Entry2ID: entries[1].ID, MatchID: &onCourt},
+				model.Fixture{Entry1ID: entries[0].ID, Entry2ID: entries[1].ID})
+
+			standings := leagueStandings(entries, fixtures, decided)
+
+			got := make([][]int64, len(standings))
+			for g, group := range standings {
+				for i, row := range group.Standings {
+					got[g] = append(got[g], row.EntryID)
+					if row.Rank != i+1 {
+						t.Errorf("group %d row %d ranked %d", group.Group, i, row.Rank)
+					}
+					if row.Played != row.Wins+row.Losses || row.GameDiff != row.GamesFor-row.GamesAgainst ||
+						row.PointDiff != row.PointsFor-row.PointsAgainst {
+						t.Errorf("entry %d totals do not add up: %+v", row.EntryID, row)
+					}
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("standings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"backend/database"
+	"backend/model"
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Per-account brute-force protection. Unlike the per-IP rate limit, failures are
+// counted against the account, so rotating addresses does not buy more guesses.
+const (
+	// throttleAfterFailures is how many wrong passwords are allowed before attempts are spaced out
+	throttleAfterFailures = 3
+	// maxLoginDelay caps the wait between attempts while throttled
+	maxLoginDelay = time.Minute
+	// lockoutFailures is how many wrong passwords in a row lock the account
+	lockoutFailures = 10
+	// lockoutDuration is how long a locked account refuses every login
+	lockoutDuration = 15 * time.Minute
+	// defaultLoginEventLimit bounds login event listings
+	defaultLoginEventLimit = 100
+)
+
+// LoginBlockedError reports a login refused before the password was checked.
+// Err is ErrLoginThrottled or ErrAccountLocked.
+type LoginBlockedError struct {
+	Err   error
+	Until time.Time
+}
+
+func (e *LoginBlockedError) Error() string {
+	return fmt.Sprintf("%v until %s", e.Err, e.Until.Format(time.RFC3339))
+}
+
+func (e *LoginBlockedError) Unwrap() error { return e.Err }
+
+// loginDelay returns how long to wait after the latest failure before the next attempt,
+// doubling from one second once the account passes throttleAfterFailures
+func loginDelay(failures int) time.Duration {
+	if failures < throttleAfterFailures {
+		return 0
+	}
+	shift := failures - throttleAfterFailures
+	if shift > 6 {
+		return maxLoginDelay
+	}
+	delay := time.Second << shift
+	if delay > maxLoginDelay {
+		return maxLoginDelay
+	}
+	return delay
+}
+
+// loginBlocked returns an error when the account may not attempt a login yet
+func loginBlocked(user *model.User, now time.Time) *LoginBlockedError {
+	if user.LockedUntil != nil && user.LockedUntil.After(now) {
+		return &LoginBlockedError{Err: ErrAccountLocked, Until: *user.LockedUntil}
+	}
+	if user.LastFailedAt != nil {
+		if next := user.LastFailedAt.Add(loginDelay(user.FailedLogins)); next.After(now) {
+			return &LoginBlockedError{Err: ErrLoginThrottled, Until: next}
+		}
+	}
+	return nil
+}
+
+// claimLoginAttempt checks the account's throttle and lockout and, when an attempt is
+// allowed, counts it as a failure before the password is checked. The user's row is held
+// meanwhile, so concurrent guesses see each other's attempts instead of all passing the
+// same check. A correct password clears the count again. Returns the failures including
+// this attempt, or a *LoginBlockedError.
+func (s *AuthService) claimLoginAttempt(ctx context.Context, userID int64) (int, error) {
+	var failures int
+	err := s.store.WithTx(ctx, func(tx database.Store) error {
+		user, err := tx.Users().GetForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if blocked := loginBlocked(user, time.Now()); blocked != nil {
+			return blocked
+		}
+		failures, err = tx.Users().RecordFailedLogin(ctx, userID)
+		return err
+	})
+	return failures, err
+}
+
+// failedLogin reports a wrong password already counted by claimLoginAttempt, locking the
+// account once it reaches lockoutFailures, and returns the error the caller should report
+func (s *AuthService) failedLogin(ctx context.Context, user *model.User, failures int, login string, client model.ClientInfo) error {
+	if failures < lockoutFailures {
+		s.recordLoginEvent(ctx, &user.ID, login, model.LoginFailed, client,
+			fmt.Sprintf("%d consecutive failures", failures))
+		return ErrInvalidCredentials
+	}
+
+	until := time.Now().Add(lockoutDuration)
+	if err := s.store.Users().Lock(ctx, user.ID, until); err != nil {
+		return err
+	}
+	log.Printf("locked user %d after %d failed logins", user.ID, failures)
+	s.recordLoginEvent(ctx, &user.ID, login, model.LoginLocked, client,
+		fmt.Sprintf("%d consecutive failures, locked until %s", failures, until.Format(time.RFC3339)))
+	return &LoginBlockedError{Err: ErrAccountLocked, Until: until}
+}
+
+// recordLoginEvent stores a login event. Failing to record one must not change the
+// outcome of the login, so errors are only logged.
+func (s *AuthService) recordLoginEvent(ctx context.Context, userID *int64, login string, kind model.LoginEventKind, client model.ClientInfo, detail string) {
+	err := s.store.LoginEvents().Create(ctx, &model.LoginEvent{
+		UserID:    userID,
+		Login:     login,
+		Kind:      kind,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		Detail:    detail,
+	})
+	if err != nil {
+		log.Printf("failed to record %s login event: %v", kind, err)
+	}
+}
+
+// UnlockAccount clears any user's failed logins and lockout (platform admin only)
+func (s *AuthService) UnlockAccount(actor model.Actor, userID int64) error {
+	ctx := context.Background()
+
+	user, err := s.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.Users().Unlock(ctx, userID); err == database.ErrNotFound {
+		return ErrUserNotFound
+	} else if err != nil {
+		return err
+	}
+
+	s.recordLoginEvent(ctx, &userID, user.Username, model.LoginUnlocked, model.ClientInfo{IP: actor.IP},
+		fmt.Sprintf("unlocked by user %d", actor.UserID))
+	s.audit.Record(actor, 0, model.AuditUserUnlocked, "user", userID, nil, nil)
+	return nil
+}
+
+// LoginEvents returns recent login events across all accounts, newest first (platform admin only)
+func (s *AuthService) LoginEvents(filter database.LoginEventFilter) ([]model.LoginEvent, error) {
+	if filter.Limit <= 0 || filter.Limit > defaultLoginEventLimit {
+		filter.Limit = defaultLoginEventLimit
+	}
+	return s.store.LoginEvents().List(context.Background(), filter)
+}
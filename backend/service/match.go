@@ -1,192 +1,361 @@
 package service
 
 import (
+	"backend/database"
 	"backend/model"
 	"context"
-	"database/sql"
 	"errors"
+	"fmt"
+	"log"
 	"time"
-
-	"github.com/lib/pq"
 )
 
 var (
-	ErrMatchNotFound = errors.New("match not found")
-	ErrInvalidTeam   = errors.New("invalid team composition")
+	ErrMatchNotFound        = errors.New("match not found")
+	ErrInvalidTeam          = errors.New("invalid team composition")
+	ErrMatchAlreadyRecorded = errors.New("match result already recorded")
 )
 
 // MatchService handles match operations
 type MatchService struct {
-	userService *UserService
-	db          *sql.DB
+	userService    *UserService
+	queueService   *QueueService
+	courtService   *CourtService
+	sessionService *SessionService
+	events         *EventBroker
+	audit          *AuditLog
+	store          database.Store
+	// autoDispatch is the server default for clubs that have not chosen
+	autoDispatch bool
 }
 
 // NewMatchService creates a new match service
-func NewMatchService(userSvc *UserService, db *sql.DB) *MatchService {
+func NewMatchService(userSvc *UserService, queueSvc *QueueService, courtSvc *CourtService, sessionSvc *SessionService, events *EventBroker, audit *AuditLog, store database.Store) *MatchService {
 	return &MatchService{
-		userService: userSvc,
-		db:          db,
+		userService:    userSvc,
+		queueService:   queueSvc,
+		courtService:   courtSvc,
+		sessionService: sessionSvc,
+		events:         events,
+		audit:          audit,
+		store:          store,
 	}
 }
 
-// Create creates a new match
-func (s *MatchService) Create(court string, team1, team2 []int64) (*model.Match, error) {
+// SetDefaultAutoDispatch sets whether clubs that have not chosen refill freed courts
+// automatically. It is set from configuration at startup.
+func (s *MatchService) SetDefaultAutoDispatch(enabled bool) {
+	s.autoDispatch = enabled
+}
+
+// SetAutoDispatch turns automatic court assignment on or off for a club
+func (s *MatchService) SetAutoDispatch(actor model.Actor, clubID int64, enabled bool) error {
+	before := s.AutoDispatchEnabled(clubID)
+
+	if err := s.store.Clubs().SetAutoDispatch(context.Background(), clubID, enabled); err == database.ErrNotFound {
+		return ErrClubNotFound
+	} else if err != nil {
+		return err
+	}
+
+	if before != enabled {
+		s.audit.Record(actor, clubID, model.AuditAutoDispatch, "club", clubID, before, enabled)
+	}
+	return nil
+}
+
+// AutoDispatchEnabled reports whether a club's freed courts are refilled automatically
+func (s *MatchService) AutoDispatchEnabled(clubID int64) bool {
+	club, err := s.store.Clubs().Get(context.Background(), clubID)
+	if err != nil || club.AutoDispatch == nil {
+		return s.autoDispatch
+	}
+	return *club.AutoDispatch
+}
+
+// Create creates a new match in a club session (0 for the legacy queue) played under the given
+// scoring rules. When rules is nil the session's format is used, or BWF rules outside a session.
+func (s *MatchService) Create(actor model.Actor, clubID, sessionID int64, court string, team1, team2 []int64, rules *model.ScoringRules) (*model.Match, error) {
+	match, err := s.newMatch(clubID, sessionID, court, team1, team2, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	err = s.store.WithTx(ctx, func(tx database.Store) error {
+		return s.insertMatch(ctx, tx, match)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.announceMatch(actor, match)
+
+	return match, nil
+}
+
+// newMatch checks the teams, session and court for a new match and returns it unsaved
+func (s *MatchService) newMatch(clubID, sessionID int64, court string, team1, team2 []int64, rules *model.ScoringRules) (*model.Match, error) {
 	if len(team1) == 0 || len(team2) == 0 {
 		return nil, ErrInvalidTeam
 	}
 	if len(team1) > 2 || len(team2) > 2 {
 		return nil, ErrInvalidTeam
 	}
+	// Each player takes one place, or their rating would be updated twice for one match
+	seen := make(map[int64]bool, len(team1)+len(team2))
+	for _, id := range append(append([]int64{}, team1...), team2...) {
+		if seen[id] {
+			return nil, ErrInvalidTeam
+		}
+		seen[id] = true
+	}
 
-	ctx := context.Background()
-
-	var match model.Match
-	err := s.db.QueryRowContext(ctx, `
-		INSERT INTO matches (court, team1, team2, result, started_at, created_at)
-		VALUES ($1, $2, $3, 'pending', NOW(), NOW())
-		RETURNING id, court, team1, team2, result, started_at, ended_at, created_at
-	`, court, pq.Array(team1), pq.Array(team2)).Scan(
-		&match.ID, &match.Court, pq.Array(&match.Team1), pq.Array(&match.Team2),
-		&match.Result, &match.StartedAt, &match.EndedAt, &match.CreatedAt,
-	)
+	scoring := model.DefaultScoringRules()
+	var courtIDs []int64
+	if sessionID != 0 {
+		sess, err := s.sessionService.GetByID(clubID, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		if sess.Status != model.SessionOpen {
+			return nil, ErrSessionNotOpen
+		}
+		scoring = sess.Scoring
+		courtIDs = sess.CourtIDs
+	}
+	if rules != nil {
+		scoring = *rules
+	}
+	if err := ValidateScoringRules(scoring); err != nil {
+		return nil, err
+	}
 
+	// Court must be registered, not under maintenance and booked for the session
+	registered, err := s.courtService.ValidateForMatch(clubID, court)
 	if err != nil {
 		return nil, err
 	}
+	if len(courtIDs) > 0 && !contains(courtIDs, registered.ID) {
+		return nil, ErrCourtNotInSession
+	}
 
-	// Update queue entries to 'playing' status
-	allPlayers := append(team1, team2...)
-	for _, playerID := range allPlayers {
-		s.db.ExecContext(ctx, `
-			UPDATE queue_entries SET status = 'playing' 
-			WHERE user_id = $1 AND status IN ('waiting', 'called')
-		`, playerID)
+	return &model.Match{
+		ClubID:    clubID,
+		SessionID: sessionPtr(sessionID),
+		CourtID:   &registered.ID,
+		Court:     registered.Name,
+		Team1:     team1,
+		Team2:     team2,
+		Scoring:   scoring,
+	}, nil
+}
+
+// insertMatch saves a new match and marks its players as playing
+func (s *MatchService) insertMatch(ctx context.Context, tx database.Store, match *model.Match) error {
+	allPlayers := append(append([]int64{}, match.Team1...), match.Team2...)
+
+	// Players can only be put on a court by a club they belong to
+	members, err := tx.Users().AllInClub(ctx, match.ClubID, allPlayers)
+	if err != nil {
+		return err
+	}
+	if !members {
+		return ErrNotClubMember
 	}
 
-	return &match, nil
+	// The court must be free; the pending-match index settles concurrent inserts
+	if err := tx.Matches().Create(ctx, match); err == database.ErrDuplicate {
+		return ErrCourtBusy
+	} else if err != nil {
+		return err
+	}
+
+	// Update queue entries to 'playing' status
+	return tx.Queue().MarkPlaying(ctx, match.ClubID, matchSession(match), allPlayers)
 }
 
-// RecordResult records the result of a match
-func (s *MatchService) RecordResult(matchID int64, scores []model.GameScore) (*model.Match, error) {
+// announceMatch records a saved match in the audit log and tells the club and its players
+func (s *MatchService) announceMatch(actor model.Actor, match *model.Match) {
+	s.audit.Record(actor, match.ClubID, model.AuditMatchCreated, "match", match.ID, nil, match)
+	s.events.Publish(match.ClubID, model.EventMatchCreated, match)
+	for _, playerID := range append(append([]int64{}, match.Team1...), match.Team2...) {
+		s.events.Notify(playerID, model.EventPlayerAssigned, model.PlayerNotice{
+			Message: fmt.Sprintf("You're up on %s!", match.Court),
+			Court:   &match.Court,
+			MatchID: &match.ID,
+		})
+	}
+}
+
+// RecordResult records the result of one of a club's matches.
+// All writes happen in one transaction, and a match can only be recorded once.
+func (s *MatchService) RecordResult(actor model.Actor, clubID, matchID int64, scores []model.GameScore) (*model.Match, error) {
 	ctx := context.Background()
 
-	// Get match
-	var match model.Match
-	err := s.db.QueryRowContext(ctx, `
-		SELECT id, court, team1, team2, result, started_at
-		FROM matches WHERE id = $1
-	`, matchID).Scan(&match.ID, &match.Court, pq.Array(&match.Team1), pq.Array(&match.Team2), &match.Result, &match.StartedAt)
+	var match *model.Match
+	var before model.Match
+	now := time.Now()
 
-	if err == sql.ErrNoRows {
-		return nil, ErrMatchNotFound
-	}
+	err := s.store.WithTx(ctx, func(tx database.Store) error {
+		// Lock the match so concurrent submissions of the same result serialize here
+		var err error
+		match, err = tx.Matches().GetForUpdate(ctx, clubID, matchID)
+		if err == database.ErrNotFound {
+			return ErrMatchNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if match.Result != string(model.MatchResultPending) {
+			return ErrMatchAlreadyRecorded
+		}
+		before = *match
+
+		// Scores must follow the match's scoring rules; this also decides the winner
+		winner, err := ValidateScores(match.Scoring, scores)
+		if err != nil {
+			return err
+		}
+		result := string(winner)
+
+		// Update match and insert scores
+		if err := tx.Matches().Complete(ctx, matchID, result, now, scores); err != nil {
+			return err
+		}
+
+		// Update ratings first so stats derive skill level from the new rating
+		if err := s.userService.updateRatings(ctx, tx, matchID, match.Team1, match.Team2, scores, result == "team1"); err != nil {
+			return err
+		}
+
+		// Update player stats
+		for _, playerID := range match.Team1 {
+			if err := s.userService.updateStats(ctx, tx, playerID, result == "team1"); err != nil {
+				return err
+			}
+		}
+		for _, playerID := range match.Team2 {
+			if err := s.userService.updateStats(ctx, tx, playerID, result == "team2"); err != nil {
+				return err
+			}
+		}
+
+		// Remove from queue
+		allPlayers := append(append([]int64{}, match.Team1...), match.Team2...)
+		if err := tx.Queue().RemovePlaying(ctx, clubID, matchSession(match), allPlayers); err != nil {
+			return err
+		}
+
+		// Tournament matches move their bracket or league on
+		match.Result = result
+		return advanceTournament(ctx, tx, match)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Calculate winner
-	team1Wins := 0
-	team2Wins := 0
-	for _, score := range scores {
-		if score.Team1Score > score.Team2Score {
-			team1Wins++
-		} else if score.Team2Score > score.Team1Score {
-			team2Wins++
+	match.EndedAt = &now
+	match.Scores = scores
+	for i := range match.Scores {
+		match.Scores[i].Game = i + 1
+	}
+
+	s.audit.Record(actor, clubID, model.AuditMatchResult, "match", matchID, before, match)
+	s.events.Publish(clubID, model.EventMatchCompleted, match)
+
+	// Refill the freed court; a failed dispatch must not fail the recorded result
+	if s.AutoDispatchEnabled(clubID) {
+		next, err := s.dispatchCourt(actor, clubID, matchSession(match), match.Court)
+		if err != nil {
+			log.Printf("auto-dispatch to %s skipped: %v", match.Court, err)
 		}
+		match.NextMatch = next
 	}
 
-	result := "draw"
-	if team1Wins > team2Wins {
-		result = "team1"
-	} else if team2Wins > team1Wins {
-		result = "team2"
+	return match, nil
+}
+
+// matchSession returns the session a match was played in, or 0 for the legacy queue
+func matchSession(match *model.Match) int64 {
+	if match.SessionID == nil {
+		return 0
 	}
+	return *match.SessionID
+}
 
-	// Update match
-	now := time.Now()
-	_, err = s.db.ExecContext(ctx, `
-		UPDATE matches SET result = $2, ended_at = $3 WHERE id = $1
-	`, matchID, result, now)
-	if err != nil {
+// dispatchCourt calls the next four waiting players in a session onto a court and starts their match
+// The actor is whoever recorded the result that freed the court.
+func (s *MatchService) dispatchCourt(actor model.Actor, clubID, sessionID int64, court string) (*model.Match, error) {
+	// Court may have been put under maintenance while the match was running
+	if _, err := s.courtService.ValidateForMatch(clubID, court); err != nil {
 		return nil, err
 	}
 
-	// Insert scores
-	for i, score := range scores {
-		s.db.ExecContext(ctx, `
-			INSERT INTO match_scores (match_id, game_number, team1_score, team2_score)
-			VALUES ($1, $2, $3, $4)
-		`, matchID, i+1, score.Team1Score, score.Team2Score)
+	called, err := s.queueService.CallNext(actor, clubID, sessionID, 4)
+	if err != nil {
+		return nil, err
 	}
-
-	// Update player stats
-	for _, playerID := range match.Team1 {
-		s.userService.UpdateStats(playerID, result == "team1")
+	if len(called) < 4 {
+		// Not enough players for doubles; leave them for the organizer
+		if err := s.queueService.Requeue(clubID, sessionID, called); err != nil {
+			return nil, err
+		}
+		return nil, ErrQueueEmpty
 	}
-	for _, playerID := range match.Team2 {
-		s.userService.UpdateStats(playerID, result == "team2")
+
+	playerIDs := make([]int64, len(called))
+	for i, entry := range called {
+		playerIDs[i] = entry.UserID
 	}
 
-	// Remove from queue
-	allPlayers := append(match.Team1, match.Team2...)
-	for _, playerID := range allPlayers {
-		s.db.ExecContext(ctx, `
-			DELETE FROM queue_entries WHERE user_id = $1 AND status = 'playing'
-		`, playerID)
+	proposal, err := s.balancePlayers(context.Background(), playerIDs)
+	if err != nil {
+		s.queueService.Requeue(clubID, sessionID, called)
+		return nil, err
 	}
 
-	match.Result = result
-	match.EndedAt = &now
-	match.Scores = scores
+	next, err := s.Create(actor, clubID, sessionID, court, proposal.Team1, proposal.Team2, nil)
+	if err != nil {
+		s.queueService.Requeue(clubID, sessionID, called)
+		return nil, err
+	}
 
-	return &match, nil
+	return next, nil
 }
 
-// GetHistory returns match history for a user
-func (s *MatchService) GetHistory(userID int64, limit int) ([]map[string]interface{}, error) {
+// GetHistory returns a user's match history in a club, limited to one session when sessionID is set
+func (s *MatchService) GetHistory(userID, clubID, sessionID int64, limit int) ([]map[string]interface{}, error) {
 	if limit <= 0 {
 		limit = 20
 	}
 
 	ctx := context.Background()
 
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT m.id, m.court, m.team1, m.team2, m.result, m.started_at, m.ended_at
-		FROM matches m
-		WHERE $1 = ANY(m.team1) OR $1 = ANY(m.team2)
-		ORDER BY m.started_at DESC
-		LIMIT $2
-	`, userID, limit)
-
+	matches, err := s.store.Matches().History(ctx, database.MatchFilter{
+		ClubID: clubID, SessionID: sessionID, UserID: userID, Limit: limit,
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var history []map[string]interface{}
-	for rows.Next() {
-		var match model.Match
-		rows.Scan(&match.ID, &match.Court, pq.Array(&match.Team1), pq.Array(&match.Team2),
-			&match.Result, &match.StartedAt, &match.EndedAt)
 
-		// Get scores for this match
-		match.Scores = s.getMatchScores(ctx, match.ID)
-
-		// Get player names
-		team1Names := s.getPlayerNames(ctx, match.Team1)
-		team2Names := s.getPlayerNames(ctx, match.Team2)
+	names := s.playerNames(ctx, matches)
 
+	var history []map[string]interface{}
+	for _, match := range matches {
 		// Determine if user won
 		inTeam1 := contains(match.Team1, userID)
 		won := (inTeam1 && match.Result == "team1") || (!inTeam1 && match.Result == "team2")
 
 		history = append(history, map[string]interface{}{
 			"id":          match.ID,
+			"session_id":  match.SessionID,
 			"court":       match.Court,
 			"team1":       match.Team1,
 			"team2":       match.Team2,
-			"team1_names": team1Names,
-			"team2_names": team2Names,
+			"team1_names": names(match.Team1),
+			"team2_names": names(match.Team2),
 			"scores":      match.Scores,
 			"result":      match.Result,
 			"started_at":  match.StartedAt,
@@ -198,74 +367,37 @@ func (s *MatchService) GetHistory(userID int64, limit int) ([]map[string]interfa
 	return history, nil
 }
 
-// GetActive returns active matches
-func (s *MatchService) GetActive() ([]model.Match, error) {
-	ctx := context.Background()
-
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, court, team1, team2, result, started_at, ended_at, created_at
-		FROM matches WHERE result = 'pending'
-		ORDER BY started_at DESC
-	`)
-
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	matches := []model.Match{} // Initialize as empty array instead of nil
-	for rows.Next() {
-		var match model.Match
-		rows.Scan(&match.ID, &match.Court, pq.Array(&match.Team1), pq.Array(&match.Team2),
-			&match.Result, &match.StartedAt, &match.EndedAt, &match.CreatedAt)
-		match.Scores = s.getMatchScores(ctx, match.ID)
-		matches = append(matches, match)
-	}
-
-	return matches, nil
+// GetActive returns a club's active matches, limited to one session when sessionID is set
+func (s *MatchService) GetActive(clubID, sessionID int64) ([]model.Match, error) {
+	return s.store.Matches().Active(context.Background(), database.MatchFilter{ClubID: clubID, SessionID: sessionID})
 }
 
-// GetAllCompleted returns all completed matches with player names (for admin)
-func (s *MatchService) GetAllCompleted(limit int) ([]map[string]interface{}, error) {
+// GetAllCompleted returns a club's completed matches with player names (for admin),
+// limited to one session when sessionID is set
+func (s *MatchService) GetAllCompleted(clubID, sessionID int64, limit int) ([]map[string]interface{}, error) {
 	if limit <= 0 {
 		limit = 50
 	}
 
 	ctx := context.Background()
 
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, court, team1, team2, result, started_at, ended_at
-		FROM matches 
-		WHERE result != 'pending'
-		ORDER BY ended_at DESC
-		LIMIT $1
-	`, limit)
-
+	matches, err := s.store.Matches().Completed(ctx, database.MatchFilter{ClubID: clubID, SessionID: sessionID, Limit: limit})
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var results []map[string]interface{}
-	for rows.Next() {
-		var match model.Match
-		rows.Scan(&match.ID, &match.Court, pq.Array(&match.Team1), pq.Array(&match.Team2),
-			&match.Result, &match.StartedAt, &match.EndedAt)
-
-		// Get scores
-		match.Scores = s.getMatchScores(ctx, match.ID)
 
-		// Get player names
-		team1Names := s.getPlayerNames(ctx, match.Team1)
-		team2Names := s.getPlayerNames(ctx, match.Team2)
+	names := s.playerNames(ctx, matches)
 
+	var results []map[string]interface{}
+	for _, match := range matches {
 		results = append(results, map[string]interface{}{
 			"id":          match.ID,
+			"session_id":  match.SessionID,
 			"court":       match.Court,
 			"team1":       match.Team1,
 			"team2":       match.Team2,
-			"team1_names": team1Names,
-			"team2_names": team2Names,
+			"team1_names": names(match.Team1),
+			"team2_names": names(match.Team2),
 			"scores":      match.Scores,
 			"result":      match.Result,
 			"started_at":  match.StartedAt,
@@ -276,45 +408,29 @@ func (s *MatchService) GetAllCompleted(limit int) ([]map[string]interface{}, err
 	return results, nil
 }
 
-func (s *MatchService) getPlayerNames(ctx context.Context, playerIDs []int64) []string {
-	if len(playerIDs) == 0 {
-		return []string{}
-	}
-
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT name FROM users WHERE id = ANY($1)
-	`, pq.Array(playerIDs))
-	if err != nil {
-		return []string{}
-	}
-	defer rows.Close()
-
-	var names []string
-	for rows.Next() {
-		var name string
-		rows.Scan(&name)
-		names = append(names, name)
+// playerNames loads the names of everyone in the matches and returns a lookup for a team
+func (s *MatchService) playerNames(ctx context.Context, matches []model.Match) func(team []int64) []string {
+	var ids []int64
+	for _, m := range matches {
+		ids = append(append(ids, m.Team1...), m.Team2...)
 	}
-	return names
-}
 
-func (s *MatchService) getMatchScores(ctx context.Context, matchID int64) []model.GameScore {
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT game_number, team1_score, team2_score
-		FROM match_scores WHERE match_id = $1 ORDER BY game_number
-	`, matchID)
-	if err != nil {
-		return nil
+	byID := make(map[int64]string, len(ids))
+	if users, err := s.store.Users().GetMany(ctx, ids); err == nil {
+		for _, u := range users {
+			byID[u.ID] = u.Name
+		}
 	}
-	defer rows.Close()
 
-	var scores []model.GameScore
-	for rows.Next() {
-		var score model.GameScore
-		rows.Scan(&score.Game, &score.Team1Score, &score.Team2Score)
-		scores = append(scores, score)
+	return func(team []int64) []string {
+		names := []string{}
+		for _, id := range team {
+			if name, ok := byID[id]; ok {
+				names = append(names, name)
+			}
+		}
+		return names
 	}
-	return scores
 }
 
 func contains(slice []int64, val int64) bool {
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"backend/model"
+	"context"
+	"fmt"
+	"log"
+)
+
+// Notifier delivers a message to a user over a channel such as SMS, LINE or email.
+// Implementations pick the address they need from the user record.
+type Notifier interface {
+	Send(ctx context.Context, user *model.User, message string) error
+}
+
+// NewNotifier returns the notifier for a configured channel
+func NewNotifier(channel string) (Notifier, error) {
+	switch channel {
+	case "", "log":
+		return LogNotifier{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported notify channel %q", channel)
+	}
+}
+
+// LogNotifier writes messages to the server log instead of sending them, for local development
+type LogNotifier struct{}
+
+// Send logs the message with the user it was meant for
+func (LogNotifier) Send(ctx context.Context, user *model.User, message string) error {
+	log.Printf("notify %s (user %d, phone %q): %s", user.Username, user.ID, user.Phone, message)
+	return nil
+}
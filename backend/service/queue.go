@@ -1,12 +1,10 @@
 package service
 
 import (
+	"backend/database"
 	"backend/model"
 	"context"
-	"database/sql"
 	"errors"
-	"fmt"
-	"time"
 )
 
 var (
@@ -16,197 +14,150 @@ var (
 )
 
 // QueueService handles queue operations
+// Each club session has its own queue. Session ID 0 is the club's legacy queue used
+// when no session is open, stored with a NULL session_id.
 type QueueService struct {
-	db *sql.DB
+	courtService   *CourtService
+	sessionService *SessionService
+	events         *EventBroker
+	audit          *AuditLog
+	store          database.Store
 }
 
 // NewQueueService creates a new queue service
-func NewQueueService(db *sql.DB) *QueueService {
-	return &QueueService{db: db}
+func NewQueueService(courtSvc *CourtService, sessionSvc *SessionService, events *EventBroker, audit *AuditLog, store database.Store) *QueueService {
+	return &QueueService{
+		courtService:   courtSvc,
+		sessionService: sessionSvc,
+		events:         events,
+		audit:          audit,
+		store:          store,
+	}
 }
 
-// GetStatus returns the current queue status
-func (s *QueueService) GetStatus(userID int64) (*model.QueueInfo, error) {
+// GetStatus returns the current queue status for a session
+func (s *QueueService) GetStatus(clubID, sessionID, userID int64) (*model.QueueInfo, error) {
 	ctx := context.Background()
+	queue := s.store.Queue()
 
-	info := &model.QueueInfo{}
+	info := &model.QueueInfo{SessionID: sessionPtr(sessionID)}
 
 	// Get total in queue
-	s.db.QueryRowContext(ctx, `
-		SELECT COUNT(*) FROM queue_entries WHERE status = 'waiting'
-	`).Scan(&info.TotalInQueue)
+	info.TotalInQueue, _ = queue.Count(ctx, clubID, sessionID, model.QueueStatusWaiting)
 
 	// Get user's position if in queue
 	if userID > 0 {
-		var position int
-		err := s.db.QueryRowContext(ctx, `
-			SELECT position FROM queue_entries WHERE user_id = $1 AND status = 'waiting'
-		`, userID).Scan(&position)
-		if err == nil {
+		if position, err := queue.Position(ctx, clubID, sessionID, userID); err == nil {
 			info.YourPosition = &position
 
-			// Estimate wait time (5 minutes per person ahead)
-			waitMinutes := (position - 1) * 5
-			if waitMinutes <= 0 {
-				info.EstimatedWait = stringPtr("Next up!")
-			} else {
-				info.EstimatedWait = stringPtr(fmt.Sprintf("~%d min", waitMinutes))
+			// Estimate wait time from court usage and recent match durations
+			if in, err := s.waitInputs(ctx, clubID, sessionID, position); err == nil {
+				info.EstimatedWait = EstimateWait(in)
 			}
 
-			// Next available court
-			nextCourt := s.getNextAvailableCourt(ctx)
-			info.NextCourt = &nextCourt
+			// Next available court among those booked for the session
+			if court, err := s.courtService.NextAvailable(clubID, s.sessionService.CourtIDs(sessionID)); err == nil {
+				info.NextCourt = &court.Name
+			}
 		}
 	}
 
 	// Get currently playing (status = 'playing')
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, user_id, position, status, joined_at
-		FROM queue_entries WHERE status = 'playing'
-		ORDER BY called_at DESC LIMIT 8
-	`)
-	if err == nil {
-		defer rows.Close()
-		for rows.Next() {
-			var entry model.QueueEntry
-			var joinedAt time.Time
-			rows.Scan(&entry.ID, &entry.UserID, &entry.Position, &entry.Status, &joinedAt)
-			entry.JoinedAt = joinedAt
-			info.CurrentlyPlaying = append(info.CurrentlyPlaying, entry)
-		}
+	if playing, err := queue.Playing(ctx, clubID, sessionID, 8); err == nil {
+		info.CurrentlyPlaying = playing
 	}
 
 	return info, nil
 }
 
-func (s *QueueService) getNextAvailableCourt(ctx context.Context) string {
-	courts := []string{"Court 1", "Court 2", "Court 3", "Court 4"}
-
-	// Find court with fewest active matches
-	for _, court := range courts {
-		var count int
-		s.db.QueryRowContext(ctx, `
-			SELECT COUNT(*) FROM matches WHERE court = $1 AND result = 'pending'
-		`, court).Scan(&count)
-		if count == 0 {
-			return court
-		}
-	}
-	return courts[0]
-}
-
-// Join adds a user to the queue
-func (s *QueueService) Join(userID int64) (*model.QueueEntry, error) {
+// Join adds a user to a session's queue
+func (s *QueueService) Join(clubID, sessionID, userID int64) (*model.QueueEntry, error) {
 	ctx := context.Background()
 
 	// Check if already in queue
-	var existingCount int
-	s.db.QueryRowContext(ctx, `
-		SELECT COUNT(*) FROM queue_entries WHERE user_id = $1 AND status = 'waiting'
-	`, userID).Scan(&existingCount)
-
-	if existingCount > 0 {
+	if _, err := s.store.Queue().Position(ctx, clubID, sessionID, userID); err == nil {
 		return nil, ErrAlreadyInQueue
+	} else if err != database.ErrNotFound {
+		return nil, err
 	}
 
-	// Get next position
-	var nextPosition int
-	s.db.QueryRowContext(ctx, `
-		SELECT COALESCE(MAX(position), 0) + 1 FROM queue_entries WHERE status = 'waiting'
-	`).Scan(&nextPosition)
-
-	// Insert entry
-	var entry model.QueueEntry
-	err := s.db.QueryRowContext(ctx, `
-		INSERT INTO queue_entries (user_id, position, status, joined_at)
-		VALUES ($1, $2, 'waiting', NOW())
-		RETURNING id, user_id, position, status, joined_at
-	`, userID, nextPosition).Scan(&entry.ID, &entry.UserID, &entry.Position, &entry.Status, &entry.JoinedAt)
-
+	entry, err := s.store.Queue().Add(ctx, clubID, sessionID, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	return &entry, nil
+	s.events.Publish(clubID, model.EventQueueJoined, entry)
+
+	return entry, nil
 }
 
-// Leave removes a user from the queue
-func (s *QueueService) Leave(userID int64) error {
+// Leave removes a user from a session's queue
+func (s *QueueService) Leave(clubID, sessionID, userID int64) error {
 	ctx := context.Background()
 
-	result, err := s.db.ExecContext(ctx, `
-		DELETE FROM queue_entries WHERE user_id = $1 AND status = 'waiting'
-	`, userID)
+	removed, err := s.store.Queue().Remove(ctx, clubID, sessionID, userID)
 	if err != nil {
 		return err
 	}
-
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
+	if !removed {
 		return ErrNotInQueue
 	}
 
 	// Reorder positions
-	s.db.ExecContext(ctx, `
-		WITH ordered AS (
-			SELECT id, ROW_NUMBER() OVER (ORDER BY position) as new_pos
-			FROM queue_entries WHERE status = 'waiting'
-		)
-		UPDATE queue_entries SET position = ordered.new_pos
-		FROM ordered WHERE queue_entries.id = ordered.id
-	`)
+	s.store.Queue().Reorder(ctx, clubID, sessionID)
+
+	s.events.Publish(clubID, model.EventQueueLeft, map[string]interface{}{"user_id": userID, "session_id": sessionPtr(sessionID)})
 
 	return nil
 }
 
-// CallNext calls the next N players from the queue
-func (s *QueueService) CallNext(count int) ([]model.QueueEntry, error) {
+// CallNext calls the next N players from a session's queue
+func (s *QueueService) CallNext(actor model.Actor, clubID, sessionID int64, count int) ([]model.QueueEntry, error) {
 	if count <= 0 {
 		count = 4 // Default for doubles
 	}
 
 	ctx := context.Background()
 
-	// Get and update next entries
-	rows, err := s.db.QueryContext(ctx, `
-		UPDATE queue_entries 
-		SET status = 'called', called_at = NOW()
-		WHERE id IN (
-			SELECT id FROM queue_entries WHERE status = 'waiting'
-			ORDER BY position LIMIT $1
-		)
-		RETURNING id, user_id, position, status, joined_at
-	`, count)
-
+	called, err := s.store.Queue().CallNext(ctx, clubID, sessionID, count)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var called []model.QueueEntry
-	for rows.Next() {
-		var entry model.QueueEntry
-		rows.Scan(&entry.ID, &entry.UserID, &entry.Position, &entry.Status, &entry.JoinedAt)
-		called = append(called, entry)
-	}
 
 	if len(called) == 0 {
 		return nil, ErrQueueEmpty
 	}
 
 	// Reorder remaining positions
-	s.db.ExecContext(ctx, `
-		WITH ordered AS (
-			SELECT id, ROW_NUMBER() OVER (ORDER BY position) as new_pos
-			FROM queue_entries WHERE status = 'waiting'
-		)
-		UPDATE queue_entries SET position = ordered.new_pos
-		FROM ordered WHERE queue_entries.id = ordered.id
-	`)
+	s.store.Queue().Reorder(ctx, clubID, sessionID)
+
+	s.events.Publish(clubID, model.EventQueueCalled, called)
+	for _, entry := range called {
+		notice := model.PlayerNotice{Message: "You've been called! Please get ready to play."}
+		s.events.Notify(entry.UserID, model.EventPlayerCalled, notice)
+	}
+
+	s.audit.Record(actor, clubID, model.AuditQueueCalled, "session", sessionID, nil, called)
 
 	return called, nil
 }
 
-func stringPtr(s string) *string {
-	return &s
+// GetCalled returns players who have been called but not yet put on a court
+func (s *QueueService) GetCalled(clubID, sessionID int64) ([]model.QueueEntry, error) {
+	return s.store.Queue().Called(context.Background(), clubID, sessionID)
+}
+
+// Requeue puts called players back at the front of their session's queue in their called order
+func (s *QueueService) Requeue(clubID, sessionID int64, entries []model.QueueEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if err := s.store.Queue().Requeue(context.Background(), clubID, sessionID, entries); err != nil {
+		return err
+	}
+
+	s.events.Publish(clubID, model.EventQueueRequeued, entries)
+
+	return nil
 }
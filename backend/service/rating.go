@@ -0,0 +1,161 @@
+package service
+
+import (
+	"backend/model"
+	"math"
+)
+
+// Glicko-2 constants (see http://www.glicko.net/glicko/glicko2.pdf)
+const (
+	DefaultRating     = 1500.0
+	DefaultDeviation  = 350.0
+	DefaultVolatility = 0.06
+
+	glickoScale   = 173.7178
+	glickoTau     = 0.5
+	glickoEpsilon = 0.000001
+	minDeviation  = 30.0
+	marginWeight  = 0.3 // Share of the outcome decided by point margin rather than the win itself
+)
+
+// Rating is a player's Glicko-2 rating on the public (1500-based) scale
+type Rating struct {
+	Rating     float64
+	Deviation  float64
+	Volatility float64
+}
+
+// NewRating returns the rating given to unrated players
+func NewRating() Rating {
+	return Rating{Rating: DefaultRating, Deviation: DefaultDeviation, Volatility: DefaultVolatility}
+}
+
+// RateDoubles computes new ratings for both teams after a match.
+// Each player is rated against the opposing team's average, shifted by how much
+// stronger or weaker their own team is than them, so a strong partner earns less credit.
+func RateDoubles(team1, team2 []Rating, scores []model.GameScore, team1Won bool) ([]Rating, []Rating) {
+	outcome := matchOutcome(scores, team1Won)
+	return rateTeam(team1, team2, outcome), rateTeam(team2, team1, 1-outcome)
+}
+
+func rateTeam(team, opponents []Rating, outcome float64) []Rating {
+	teamMu, _ := teamComposite(team)
+	oppMu, oppPhi := teamComposite(opponents)
+
+	updated := make([]Rating, len(team))
+	for i, player := range team {
+		mu := (player.Rating - DefaultRating) / glickoScale
+		effectiveOpp := mu + (oppMu - teamMu)
+		updated[i] = glicko2Update(player, effectiveOpp, oppPhi, outcome)
+	}
+	return updated
+}
+
+// teamComposite returns the mean mu and pooled phi of a team on the Glicko-2 scale
+func teamComposite(team []Rating) (float64, float64) {
+	var mu, phiSq float64
+	for _, r := range team {
+		mu += (r.Rating - DefaultRating) / glickoScale
+		phi := r.Deviation / glickoScale
+		phiSq += phi * phi
+	}
+	n := float64(len(team))
+	return mu / n, math.Sqrt(phiSq / n)
+}
+
+// matchOutcome blends the win/loss with the share of points won, from team1's side
+func matchOutcome(scores []model.GameScore, team1Won bool) float64 {
+	win := 0.0
+	if team1Won {
+		win = 1
+	}
+
+	var team1Points, total int
+	for _, score := range scores {
+		team1Points += score.Team1Score
+		total += score.Team1Score + score.Team2Score
+	}
+	if total == 0 {
+		return win
+	}
+
+	share := float64(team1Points) / float64(total)
+	return (1-marginWeight)*win + marginWeight*share
+}
+
+func glicko2Update(player Rating, oppMu, oppPhi, outcome float64) Rating {
+	mu := (player.Rating - DefaultRating) / glickoScale
+	phi := player.Deviation / glickoScale
+	sigma := player.Volatility
+
+	g := 1 / math.Sqrt(1+3*oppPhi*oppPhi/(math.Pi*math.Pi))
+	e := 1 / (1 + math.Exp(-g*(mu-oppMu)))
+	v := 1 / (g * g * e * (1 - e))
+	delta := v * g * (outcome - e)
+
+	sigma = newVolatility(phi, sigma, v, delta)
+
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*g*(outcome-e)
+
+	return Rating{
+		Rating:     newMu*glickoScale + DefaultRating,
+		Deviation:  math.Max(newPhi*glickoScale, minDeviation),
+		Volatility: sigma,
+	}
+}
+
+// newVolatility solves for the new volatility with the Illinois algorithm (step 5 of Glicko-2)
+func newVolatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		num := ex * (delta*delta - phi*phi - v - ex)
+		den := 2 * math.Pow(phi*phi+v+ex, 2)
+		return num/den - (x-a)/(glickoTau*glickoTau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*glickoTau) < 0 {
+			k++
+		}
+		B = a - k*glickoTau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > glickoEpsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+
+	return math.Exp(A / 2)
+}
+
+// skillLevelForRating maps a rating to the display skill level
+func skillLevelForRating(rating float64, matches int) string {
+	if matches < 5 {
+		return "Beginner"
+	}
+	if rating >= 1800 {
+		return "Expert"
+	}
+	if rating >= 1600 {
+		return "Advanced"
+	}
+	if rating >= 1400 {
+		return "Intermediate"
+	}
+	return "Beginner"
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"backend/model"
+	"math"
+	"testing"
+)
+
+func TestMatchOutcome(t *testing.T) {
+	tests := []struct {
+		name     string
+		scores   []model.GameScore
+		team1Won bool
+		want     float64
+	}{
+		{"win without scores", nil, true, 1},
+		{"loss without scores", nil, false, 0},
+		{"whitewash", games([2]int{21, 0}, [2]int{21, 0}), true, 1},
+		{"even points in a win", games([2]int{21, 19}, [2]int{19, 21}, [2]int{21, 19}), true, 0.7 + 0.3*61.0/120},
+		{"narrow loss", games([2]int{19, 21}, [2]int{20, 22}), false, 0.3 * 39.0 / 82},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchOutcome(tt.scores, tt.team1Won); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("matchOutcome = %.4f, want %.4f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateDoubles(t *testing.T) {
+	fresh := []Rating{NewRating(), NewRating()}
+	strong := []Rating{
+		{Rating: 1800, Deviation: 80, Volatility: DefaultVolatility},
+		{Rating: 1800, Deviation: 80, Volatility: DefaultVolatility},
+	}
+	weak := []Rating{
+		{Rating: 1300, Deviation: 80, Volatility: DefaultVolatility},
+		{Rating: 1300, Deviation: 80, Volatility: DefaultVolatility},
+	}
+
+	t.Run("even teams move symmetrically", func(t *testing.T) {
+		won, lost := RateDoubles(fresh, fresh, games([2]int{21, 15}, [2]int{21, 15}), true)
+		for i := range won {
+			gain, loss := won[i].Rating-DefaultRating, DefaultRating-lost[i].Rating
+			if gain <= 0 || math.Abs(gain-loss) > 1e-6 {
+				t.Errorf("player %d gained %.2f and the opponent lost %.2f; want equal and positive", i, gain, loss)
+			}
+			if won[i].Deviation >= DefaultDeviation || lost[i].Deviation >= DefaultDeviation {
+				t.Errorf("player %d deviation did not shrink after a match", i)
+			}
+		}
+	})
+
+	t.Run("bigger margins earn more", func(t *testing.T) {
+		close, _ := RateDoubles(fresh, fresh, games([2]int{22, 20}, [2]int{22, 20}), true)
+		easy, _ := RateDoubles(fresh, fresh, games([2]int{21, 5}, [2]int{21, 5}), true)
+		if easy[0].Rating <= close[0].Rating {
+			t.Errorf("easy win %.2f should rate above close win %.2f", easy[0].Rating, close[0].Rating)
+		}
+	})
+
+	t.Run("upsets earn more than expected wins", func(t *testing.T) {
+		scores := games([2]int{21, 18}, [2]int{21, 18})
+		upset, _ := RateDoubles(weak, strong, scores, true)
+		expected, _ := RateDoubles(strong, weak, scores, true)
+		upsetGain := upset[0].Rating - weak[0].Rating
+		expectedGain := expected[0].Rating - strong[0].Rating
+		if upsetGain <= expectedGain {
+			t.Errorf("upset gained %.2f, expected win %.2f; the upset should gain more", upsetGain, expectedGain)
+		}
+	})
+
+	t.Run("an uncertain partner moves further", func(t *testing.T) {
+		mixed := []Rating{strong[0], {Rating: 1800, Deviation: DefaultDeviation, Volatility: DefaultVolatility}}
+		won, _ := RateDoubles(mixed, strong, games([2]int{21, 15}, [2]int{21, 15}), true)
+		settledGain, newGain := won[0].Rating-mixed[0].Rating, won[1].Rating-mixed[1].Rating
+		if newGain <= settledGain {
+			t.Errorf("uncertain partner gained %.2f, settled partner %.2f; the uncertain one should gain more", newGain, settledGain)
+		}
+	})
+
+	t.Run("deviation has a floor", func(t *testing.T) {
+		settled := []Rating{
+			{Rating: 1500, Deviation: minDeviation, Volatility: DefaultVolatility},
+			{Rating: 1500, Deviation: minDeviation, Volatility: DefaultVolatility},
+		}
+		won, _ := RateDoubles(settled, settled, games([2]int{21, 15}, [2]int{21, 15}), true)
+		if won[0].Deviation < minDeviation {
+			t.Errorf("deviation %.2f fell below the floor %.0f", won[0].Deviation, minDeviation)
+		}
+	})
+}
+
+func TestSkillLevelForRating(t *testing.T) {
+	tests := []struct {
+		rating  float64
+		matches int
+		want    string
+	}{
+		{2000, 4, "Beginner"},
+		{1800, 5, "Expert"},
+		{1799, 20, "Advanced"},
+		{1600, 20, "Advanced"},
+		{1400, 20, "Intermediate"},
+		{1399, 20, "Beginner"},
+	}
+
+	for _, tt := range tests {
+		if got := skillLevelForRating(tt.rating, tt.matches); got != tt.want {
+			t.Errorf("skillLevelForRating(%.0f, %d) = %s, want %s", tt.rating, tt.matches, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"backend/database"
+	"backend/model"
+	"context"
+	"time"
+)
+
+// RecomputeStats rebuilds one player's stats from their matches and scores (platform admin only).
+// Their rating is replayed match by match against the ratings their partners and opponents
+// had going into each match, so nobody else's stats change.
+func (s *UserService) RecomputeStats(actor model.Actor, userID int64) (*model.UserStats, error) {
+	ctx := context.Background()
+
+	if _, err := s.store.Users().GetByID(ctx, userID); err == database.ErrNotFound {
+		return nil, ErrUserNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var before, after *model.UserStats
+	err := s.store.WithTx(ctx, func(tx database.Store) error {
+		var err error
+		if before, err = tx.Stats().Get(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.recomputePlayer(ctx, tx, userID); err != nil {
+			return err
+		}
+		after, err = tx.Stats().Get(ctx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, 0, model.AuditStatsRecomputed, "user", userID, before, after)
+	return after, nil
+}
+
+// RecomputeAllStats rebuilds every player's stats by replaying all decided matches in the
+// order they ended, starting everyone unrated (platform admin only)
+func (s *UserService) RecomputeAllStats(actor model.Actor) (*model.RecomputeResult, error) {
+	ctx := context.Background()
+
+	var result model.RecomputeResult
+	err := s.store.WithTx(ctx, func(tx database.Store) error {
+		if err := tx.Stats().Reset(ctx); err != nil {
+			return err
+		}
+
+		matches, err := tx.Matches().DecidedFrom(ctx, time.Time{}, 0)
+		if err != nil {
+			return err
+		}
+
+		current := make(map[int64]Rating)
+		var players []int64
+		for _, m := range matches {
+			for _, id := range append(append([]int64{}, m.Team1...), m.Team2...) {
+				if _, ok := current[id]; !ok {
+					current[id] = NewRating()
+					players = append(players, id)
+				}
+			}
+			if err := s.rateMatch(ctx, tx, m, current); err != nil {
+				return err
+			}
+		}
+
+		for _, id := range players {
+			if err := s.recomputeRecord(ctx, tx, id); err != nil {
+				return err
+			}
+		}
+
+		result = model.RecomputeResult{Players: len(players), Matches: len(matches)}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, 0, model.AuditStatsRecomputed, "stats", "all", nil, result)
+	return &result, nil
+}
+
+// recomputePlayer replays one player's rating through their decided matches, using the
+// recorded ratings of everyone else in them, then rebuilds their record
+func (s *UserService) recomputePlayer(ctx context.Context, repo database.Store, userID int64) error {
+	matches, err := repo.Matches().Decided(ctx, database.MatchFilter{UserID: userID})
+	if err != nil {
+		return err
+	}
+
+	matchIDs := make([]int64, len(matches))
+	for i, m := range matches {
+		matchIDs[i] = m.ID
+	}
+	changes, err := repo.Stats().RatingChanges(ctx, matchIDs)
+	if err != nil {
+		return err
+	}
+
+	// Volatility only affects a player's own update, so the recorded one is not needed for others
+	type key struct{ matchID, userID int64 }
+	recorded := make(map[key]Rating, len(changes))
+	for _, c := range changes {
+		recorded[key{c.MatchID, c.UserID}] = Rating{Rating: c.RatingBefore, Deviation: c.DeviationBefore, Volatility: DefaultVolatility}
+	}
+
+	if err := repo.Stats().ClearRatingHistory(ctx, userID); err != nil {
+		return err
+	}
+
+	rating := NewRating()
+	for _, m := range matches {
+		before := make(map[int64]Rating)
+		for _, id := range append(append([]int64{}, m.Team1...), m.Team2...) {
+			before[id] = NewRating()
+			if r, ok := recorded[key{m.ID, id}]; ok {
+				before[id] = r
+			}
+		}
+		before[userID] = rating
+
+		after := matchRatings(m, before)[userID]
+		if err := s.saveRating(ctx, repo, m, userID, rating, after); err != nil {
+			return err
+		}
+		rating = after
+	}
+
+	if err := repo.Stats().SetRating(ctx, userID, rating.Rating, rating.Deviation, rating.Volatility); err != nil {
+		return err
+	}
+	return s.recomputeRecord(ctx, repo, userID)
+}
+
+// replayFrom brings ratings and records up to date after a finished match was changed.
+// Each rating feeds the next, so every decided match from the changed one onwards is
+// re-rated in order, starting from the ratings players had before it.
+func (s *UserService) replayFrom(ctx context.Context, repo database.Store, changed *model.Match) error {
+	matches, err := repo.Matches().DecidedFrom(ctx, *changed.EndedAt, changed.ID)
+	if err != nil {
+		return err
+	}
+
+	// The changed match is rewound even when it no longer counts (voided)
+	matchIDs := []int64{changed.ID}
+	players := append(append([]int64{}, changed.Team1...), changed.Team2...)
+	for _, m := range matches {
+		if m.ID != changed.ID {
+			matchIDs = append(matchIDs, m.ID)
+		}
+		players = append(append(players, m.Team1...), m.Team2...)
+	}
+	players = distinct(players)
+
+	if err := repo.Stats().DeleteRatingChanges(ctx, matchIDs); err != nil {
+		return err
+	}
+
+	// With the replayed matches gone, each player's latest change is where they stood before them
+	latest, err := repo.Stats().LatestRatings(ctx, players)
+	if err != nil {
+		return err
+	}
+	current := make(map[int64]Rating, len(players))
+	for _, id := range players {
+		current[id] = NewRating()
+		if c, ok := latest[id]; ok {
+			current[id] = Rating{Rating: c.RatingAfter, Deviation: c.DeviationAfter, Volatility: c.Volatility}
+		}
+	}
+
+	for _, m := range matches {
+		if err := s.rateMatch(ctx, repo, m, current); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range players {
+		// Saved for everyone so players left with no replayed match go back to their earlier rating
+		r := current[id]
+		if err := repo.Stats().SetRating(ctx, id, r.Rating, r.Deviation, r.Volatility); err != nil {
+			return err
+		}
+		if err := s.recomputeRecord(ctx, repo, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recomputeRecord rebuilds a player's match counts and streaks from their decided matches
+// in every club. The stored rating is kept.
+func (s *UserService) recomputeRecord(ctx context.Context, repo database.Store, userID int64) error {
+	stats, err := repo.Stats().Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	outcomes, err := repo.Matches().Outcomes(ctx, 0, userID)
+	if err != nil {
+		return err
+	}
+
+	return repo.Stats().SaveRecord(ctx, buildRecord(stats, outcomes))
+}
+
+// distinct drops repeated IDs, keeping the first occurrence
+func distinct(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	unique := ids[:0]
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
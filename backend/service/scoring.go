@@ -0,0 +1,115 @@
+package service
+
+import (
+	"backend/model"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidScoringRules = errors.New("invalid scoring rules")
+
+// ScoreError reports why a recorded set of games breaks the scoring rules.
+// Game is 1-based; 0 means the problem is with the match as a whole.
+type ScoreError struct {
+	Game   int
+	Reason string
+}
+
+func (e *ScoreError) Error() string {
+	if e.Game == 0 {
+		return "invalid score: " + e.Reason
+	}
+	return fmt.Sprintf("invalid score in game %d: %s", e.Game, e.Reason)
+}
+
+// ValidateScoringRules checks that a scoring format can produce a winner
+func ValidateScoringRules(rules model.ScoringRules) error {
+	if rules.PointsToWin < 1 || rules.PointCap < rules.PointsToWin {
+		return ErrInvalidScoringRules
+	}
+	if rules.BestOf < 1 || rules.BestOf%2 == 0 {
+		return ErrInvalidScoringRules
+	}
+	return nil
+}
+
+// ValidateScores checks games against the rally-point rules and returns the winning side.
+// Every game must be complete and no game may be recorded after the match is decided.
+func ValidateScores(rules model.ScoringRules, scores []model.GameScore) (model.MatchResult, error) {
+	if len(scores) == 0 {
+		return "", &ScoreError{Reason: "no games recorded"}
+	}
+	if len(scores) > rules.BestOf {
+		return "", &ScoreError{Reason: fmt.Sprintf("at most %d games can be played", rules.BestOf)}
+	}
+
+	needed := rules.GamesToWin()
+	team1Wins, team2Wins := 0, 0
+
+	for i, score := range scores {
+		game := i + 1
+
+		if team1Wins == needed || team2Wins == needed {
+			return "", &ScoreError{Game: game, Reason: "match was already decided"}
+		}
+
+		if err := validateGame(rules, score); err != "" {
+			return "", &ScoreError{Game: game, Reason: err}
+		}
+
+		if score.Team1Score > score.Team2Score {
+			team1Wins++
+		} else {
+			team2Wins++
+		}
+	}
+
+	switch {
+	case team1Wins == needed:
+		return model.MatchResultTeam1, nil
+	case team2Wins == needed:
+		return model.MatchResultTeam2, nil
+	default:
+		return "", &ScoreError{Reason: fmt.Sprintf("a team must win %d games", needed)}
+	}
+}
+
+// validateGame returns a reason the game score is impossible, or "" if it is valid
+func validateGame(rules model.ScoringRules, score model.GameScore) string {
+	if score.Team1Score < 0 || score.Team2Score < 0 {
+		return "scores cannot be negative"
+	}
+	if score.Team1Score == score.Team2Score {
+		return "a game cannot end level"
+	}
+
+	winner, loser := score.Team1Score, score.Team2Score
+	if loser > winner {
+		winner, loser = loser, winner
+	}
+
+	target, pointCap := rules.PointsToWin, rules.PointCap
+
+	switch {
+	case winner < target:
+		return fmt.Sprintf("game not finished, winner needs %d points", target)
+	case winner > pointCap:
+		return fmt.Sprintf("score cannot exceed %d", pointCap)
+	case winner == pointCap && pointCap > target:
+		// Golden point at the cap, or a two-point lead reached exactly at the cap
+		if loser < pointCap-2 {
+			return fmt.Sprintf("game would have ended before %d", pointCap)
+		}
+	case winner == target:
+		if loser > target-2 && target != pointCap {
+			return "must win by 2 points"
+		}
+	default:
+		// Extended game: the first two-point lead ends it
+		if winner-loser != 2 {
+			return "extended game must end with a 2-point lead"
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"backend/model"
+	"testing"
+)
+
+func games(scores ...[2]int) []model.GameScore {
+	out := make([]model.GameScore, len(scores))
+	for i, s := range scores {
+		out[i] = model.GameScore{Team1Score: s[0], Team2Score: s[1]}
+	}
+	return out
+}
+
+func TestValidateScores(t *testing.T) {
+	bwf := model.DefaultScoringRules()
+	shortGames := model.ScoringRules{PointsToWin: 11, PointCap: 15, BestOf: 5}
+	noSetting := model.ScoringRules{PointsToWin: 15, PointCap: 15, BestOf: 1}
+
+	tests := []struct {
+		name    string
+		rules   model.ScoringRules
+		scores  []model.GameScore
+		want    model.MatchResult
+		badGame int // 1-based game the error points at; -1 when the score is valid
+	}{
+		{"straight games", bwf, games([2]int{21, 15}, [2]int{21, 18}), model.MatchResultTeam1, -1},
+		{"three games", bwf, games([2]int{21, 19}, [2]int{17, 21}, [2]int{19, 21}), model.MatchResultTeam2, -1},
+		{"extended to a two-point lead", bwf, games([2]int{24, 22}, [2]int{21, 10}), model.MatchResultTeam1, -1},
+		{"golden point at 30", bwf, games([2]int{30, 29}, [2]int{21, 0}), model.MatchResultTeam1, -1},
+		{"two-point lead reached at the cap", bwf, games([2]int{28, 30}, [2]int{10, 21}), model.MatchResultTeam2, -1},
+		{"short games", shortGames, games([2]int{11, 9}, [2]int{9, 11}, [2]int{11, 5}, [2]int{12, 10}), model.MatchResultTeam1, -1},
+		{"no setting at the target", noSetting, games([2]int{15, 14}), model.MatchResultTeam1, -1},
+
+		{"no games", bwf, nil, "", 0},
+		{"too many games", bwf, games([2]int{21, 1}, [2]int{1, 21}, [2]int{21, 1}, [2]int{21, 1}), "", 0},
+		{"undecided match", bwf, games([2]int{21, 15}), "", 0},
+		{"game after the match was decided", bwf, games([2]int{21, 15}, [2]int{21, 15}, [2]int{21, 15}), "", 3},
+		{"unfinished game", bwf, games([2]int{20, 15}, [2]int{21, 15}), "", 1},
+		{"level game", bwf, games([2]int{21, 21}), "", 1},
+		{"negative score", bwf, games([2]int{21, -1}), "", 1},
+		{"won by one at the target", bwf, games([2]int{21, 15}, [2]int{21, 20}), "", 2},
+		{"extended game without a two-point lead", bwf, games([2]int{25, 22}), "", 1},
+		{"extended game past the two-point lead", bwf, games([2]int{21, 15}, [2]int{26, 22}), "", 2},
+		{"beyond the cap", bwf, games([2]int{31, 29}), "", 1},
+		{"cap reached after the game was over", bwf, games([2]int{30, 20}), "", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateScores(tt.rules, tt.scores)
+			if tt.badGame < 0 {
+				if err != nil || got != tt.want {
+					t.Fatalf("ValidateScores = %q, %v; want %q", got, err, tt.want)
+				}
+				return
+			}
+
+			scoreErr, ok := err.(*ScoreError)
+			if !ok {
+				t.Fatalf("ValidateScores = %q, %v; want a *ScoreError", got, err)
+			}
+			if scoreErr.Game != tt.badGame {
+				t.Errorf("error points at game %d (%v), want %d", scoreErr.Game, scoreErr, tt.badGame)
+			}
+		})
+	}
+}
+
+func TestValidateScoringRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules model.ScoringRules
+		valid bool
+	}{
+		{"BWF", model.DefaultScoringRules(), true},
+		{"no cap above the target", model.ScoringRules{PointsToWin: 15, PointCap: 15, BestOf: 1}, true},
+		{"zero target", model.ScoringRules{PointsToWin: 0, PointCap: 30, BestOf: 3}, false},
+		{"cap below the target", model.ScoringRules{PointsToWin: 21, PointCap: 20, BestOf: 3}, false},
+		{"even number of games", model.ScoringRules{PointsToWin: 21, PointCap: 30, BestOf: 2}, false},
+		{"no games", model.ScoringRules{PointsToWin: 21, PointCap: 30, BestOf: 0}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateScoringRules(tt.rules)
+			if (err == nil) != tt.valid {
+				t.Errorf("ValidateScoringRules(%+v) = %v, want valid %v", tt.rules, err, tt.valid)
+			}
+		})
+	}
+}
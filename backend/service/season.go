@@ -0,0 +1,368 @@
+package service
+
+import (
+	"backend/config"
+	"backend/database"
+	"backend/model"
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrSeasonNotFound    = errors.New("season not found")
+	ErrSeasonActive      = errors.New("club already has an active season")
+	ErrSeasonEnded       = errors.New("season has already ended")
+	ErrNoActiveSeason    = errors.New("club has no active season")
+	ErrSeasonNameMissing = errors.New("season name is required")
+	ErrInvalidCarryover  = errors.New("rating carry-over must be between 0 and 1")
+)
+
+// SeasonService runs club seasons. Each season keeps its own records and a club-local
+// rating ladder replayed from the season's matches, so the all-time stats and ratings
+// are never reset.
+type SeasonService struct {
+	carryover float64
+	audit     *AuditLog
+	store     database.Store
+}
+
+// NewSeasonService creates a new season service
+func NewSeasonService(cfg *config.Config, audit *AuditLog, store database.Store) *SeasonService {
+	return &SeasonService{
+		carryover: cfg.Season.RatingCarryover,
+		audit:     audit,
+		store:     store,
+	}
+}
+
+// Start opens a new season for the club (organizer only). Every member's starting rating
+// is their rating from the previous season, or their overall rating if they did not play
+// in it, pulled toward the club mean by the carry-over.
+func (s *SeasonService) Start(actor model.Actor, clubID int64, req model.StartSeasonRequest) (*model.Season, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrSeasonNameMissing
+	}
+	carryover := s.carryover
+	if req.RatingCarryover != nil {
+		carryover = *req.RatingCarryover
+	}
+	if carryover < 0 || carryover > 1 || math.IsNaN(carryover) {
+		return nil, ErrInvalidCarryover
+	}
+
+	ctx := context.Background()
+	season := &model.Season{ClubID: clubID, Name: name, RatingCarryover: carryover}
+	if actor.UserID != 0 {
+		season.StartedBy = &actor.UserID
+	}
+
+	err := s.store.WithTx(ctx, func(tx database.Store) error {
+		if _, err := tx.Seasons().Active(ctx, clubID); err == nil {
+			return ErrSeasonActive
+		} else if err != database.ErrNotFound {
+			return err
+		}
+
+		base, err := s.carriedRatings(ctx, tx, clubID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Seasons().Create(ctx, season); err != nil {
+			return err
+		}
+
+		return tx.Seasons().SavePlayers(ctx, startingRecords(season, base))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, clubID, model.AuditSeasonStarted, "season", season.ID, nil, season)
+
+	return season, nil
+}
+
+// End closes a club's active season (organizer only) and snapshots every player's record
+// and rating. Matches recorded after this no longer count toward it.
+func (s *SeasonService) End(actor model.Actor, clubID, seasonID int64) (*model.Season, error) {
+	ctx := context.Background()
+
+	var season *model.Season
+	var before model.Season
+
+	err := s.store.WithTx(ctx, func(tx database.Store) error {
+		var err error
+		season, err = s.get(ctx, tx, clubID, seasonID)
+		if err != nil {
+			return err
+		}
+		if season.Status == model.SeasonEnded {
+			return ErrSeasonEnded
+		}
+		before = *season
+
+		now := time.Now()
+		season.Status = model.SeasonEnded
+		season.EndedAt = &now
+		if actor.UserID != 0 {
+			season.EndedBy = &actor.UserID
+		}
+
+		records, err := s.replay(ctx, tx, *season)
+		if err != nil {
+			return err
+		}
+		if err := tx.Seasons().SavePlayers(ctx, records); err != nil {
+			return err
+		}
+
+		err = tx.Seasons().End(ctx, season.ID, season.EndedBy, now)
+		if err == database.ErrNotFound {
+			return ErrSeasonEnded
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, clubID, model.AuditSeasonEnded, "season", season.ID, before, season)
+
+	return season, nil
+}
+
+// List returns the club's seasons, newest first
+func (s *SeasonService) List(clubID int64) ([]model.Season, error) {
+	return s.store.Seasons().List(context.Background(), clubID)
+}
+
+// Get returns one of the club's seasons. Club 0 matches any club.
+func (s *SeasonService) Get(clubID, seasonID int64) (*model.Season, error) {
+	return s.get(context.Background(), s.store, clubID, seasonID)
+}
+
+// Active returns the club's running season
+func (s *SeasonService) Active(clubID int64) (*model.Season, error) {
+	season, err := s.store.Seasons().Active(context.Background(), clubID)
+	if err == database.ErrNotFound {
+		return nil, ErrNoActiveSeason
+	}
+	return season, err
+}
+
+// Standings returns every player's record in a season, best rated first. Running seasons
+// are replayed from their matches so far; ended seasons return the snapshot taken when
+// they ended.
+func (s *SeasonService) Standings(season model.Season) ([]model.SeasonStats, error) {
+	ctx := context.Background()
+
+	var records []model.SeasonStats
+	var err error
+	if season.Status == model.SeasonEnded {
+		records, err = s.store.Seasons().Players(ctx, season.ID)
+		for i := range records {
+			describeRecord(&records[i].UserStats)
+		}
+	} else {
+		records, err = s.replay(ctx, s.store, season)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Rating != records[j].Rating {
+			return records[i].Rating > records[j].Rating
+		}
+		return records[i].UserID < records[j].UserID
+	})
+	return records, nil
+}
+
+// PlayerStats returns a player's record in a season. Players who were not in the club
+// when it started and have not played in it get the default rating and an empty record.
+func (s *SeasonService) PlayerStats(season model.Season, userID int64) (*model.SeasonStats, error) {
+	records, err := s.Standings(season)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.UserID == userID {
+			return &record, nil
+		}
+	}
+
+	record := newSeasonRecord(season.ID, userID, NewRating())
+	return &record, nil
+}
+
+func (s *SeasonService) get(ctx context.Context, repo database.Store, clubID, seasonID int64) (*model.Season, error) {
+	season, err := repo.Seasons().Get(ctx, clubID, seasonID)
+	if err == database.ErrNotFound {
+		return nil, ErrSeasonNotFound
+	}
+	return season, err
+}
+
+// carriedRatings returns each club member's rating going into a new season, from the
+// club's previous season where they played in it and their overall rating otherwise
+func (s *SeasonService) carriedRatings(ctx context.Context, repo database.Store, clubID int64) (map[int64]Rating, error) {
+	members, err := repo.Users().ListByClub(ctx, clubID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, len(members))
+	for i, m := range members {
+		ids[i] = m.ID
+	}
+
+	ratings, err := s.userRatings(ctx, repo, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	seasons, err := repo.Seasons().List(ctx, clubID)
+	if err != nil {
+		return nil, err
+	}
+	if len(seasons) > 0 {
+		previous, err := repo.Seasons().Players(ctx, seasons[0].ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range previous {
+			if _, member := ratings[p.UserID]; member && p.TotalMatches > 0 {
+				ratings[p.UserID] = Rating{Rating: p.Rating, Deviation: p.RatingDev, Volatility: p.Volatility}
+			}
+		}
+	}
+
+	return ratings, nil
+}
+
+// userRatings returns players' overall ratings, defaulting those without stats
+func (s *SeasonService) userRatings(ctx context.Context, repo database.Store, ids []int64) (map[int64]Rating, error) {
+	stats, err := repo.Stats().GetMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	ratings := make(map[int64]Rating, len(ids))
+	for _, id := range ids {
+		ratings[id] = NewRating()
+		if st, ok := stats[id]; ok {
+			ratings[id] = Rating{Rating: st.Rating, Deviation: st.RatingDev, Volatility: st.Volatility}
+		}
+	}
+	return ratings, nil
+}
+
+// startingRecords pulls each rating toward the mean of them all by the season's carry-over.
+// Deviation moves back toward the default the same way, since decayed ratings are less certain.
+func startingRecords(season *model.Season, base map[int64]Rating) []model.SeasonStats {
+	mean := DefaultRating
+	if len(base) > 0 {
+		var total float64
+		for _, r := range base {
+			total += r.Rating
+		}
+		mean = total / float64(len(base))
+	}
+
+	c := season.RatingCarryover
+	records := make([]model.SeasonStats, 0, len(base))
+	for id, r := range base {
+		start := Rating{
+			Rating:     mean + c*(r.Rating-mean),
+			Deviation:  DefaultDeviation + c*(r.Deviation-DefaultDeviation),
+			Volatility: r.Volatility,
+		}
+		records = append(records, newSeasonRecord(season.ID, id, start))
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].UserID < records[j].UserID })
+	return records
+}
+
+// newSeasonRecord returns an unplayed season record starting from the given rating
+func newSeasonRecord(seasonID, userID int64, start Rating) model.SeasonStats {
+	record := model.SeasonStats{
+		SeasonID:        seasonID,
+		StartRating:     start.Rating,
+		StartDeviation:  start.Deviation,
+		StartVolatility: start.Volatility,
+		UserStats: model.UserStats{
+			UserID:     userID,
+			Rating:     start.Rating,
+			RatingDev:  start.Deviation,
+			Volatility: start.Volatility,
+		},
+	}
+	describeRecord(&record.UserStats)
+	return record
+}
+
+// replay rebuilds every player's season record from their starting rating and the club's
+// decided matches within the season. Players who joined the club after the season started
+// begin at the default rating.
+func (s *SeasonService) replay(ctx context.Context, repo database.Store, season model.Season) ([]model.SeasonStats, error) {
+	players, err := repo.Seasons().Players(ctx, season.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make(map[int64]*model.SeasonStats, len(players))
+	current := make(map[int64]Rating, len(players))
+	for _, p := range players {
+		start := Rating{Rating: p.StartRating, Deviation: p.StartDeviation, Volatility: p.StartVolatility}
+		record := newSeasonRecord(season.ID, p.UserID, start)
+		records[p.UserID] = &record
+		current[p.UserID] = start
+	}
+
+	filter := database.MatchFilter{ClubID: season.ClubID, Since: season.StartedAt}
+	if season.EndedAt != nil {
+		filter.Until = *season.EndedAt
+	}
+	matches, err := repo.Matches().Decided(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, match := range matches {
+		for _, id := range append(append([]int64{}, match.Team1...), match.Team2...) {
+			if _, ok := records[id]; !ok {
+				record := newSeasonRecord(season.ID, id, NewRating())
+				records[id] = &record
+				current[id] = NewRating()
+			}
+		}
+
+		after := matchRatings(match, current)
+		team1Won := match.Result == string(model.MatchResultTeam1)
+		for id, rating := range after {
+			record := records[id]
+			record.Rating, record.RatingDev, record.Volatility = rating.Rating, rating.Deviation, rating.Volatility
+			applyResult(&record.UserStats, contains(match.Team1, id) == team1Won)
+			current[id] = rating
+		}
+	}
+
+	result := make([]model.SeasonStats, 0, len(records))
+	for _, record := range records {
+		result = append(result, *record)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].UserID < result[j].UserID })
+	return result, nil
+}
+
+// describeRecord sets the skill level and points that follow from a record's rating
+func describeRecord(stats *model.UserStats) {
+	stats.SkillLevel = skillLevelForRating(stats.Rating, stats.TotalMatches)
+	stats.SkillPoints = int(math.Round(stats.Rating))
+}
@@ -0,0 +1,765 @@
+package service
+
+import (
+	"backend/config"
+	"backend/database"
+	"backend/model"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testServices wires the services onto an in-memory store
+type testServices struct {
+	store       *database.MemoryStore
+	clubs       *ClubService
+	courts      *CourtService
+	sessions    *SessionService
+	queue       *QueueService
+	users       *UserService
+	matches     *MatchService
+	tournaments *TournamentService
+}
+
+func newTestServices(t *testing.T) *testServices {
+	t.Helper()
+
+	store := database.NewMemoryStore()
+	audit := NewAuditLog(store)
+	events := NewEventBroker()
+	users := NewUserService(nil, audit, store)
+	courts := NewCourtService(events, audit, store)
+	sessions := NewSessionService(courts, events, audit, store)
+	queue := NewQueueService(courts, sessions, events, audit, store)
+
+	matches := NewMatchService(users, queue, courts, sessions, events, audit, store)
+
+	return &testServices{
+		store:       store,
+		clubs:       NewClubService(audit, store),
+		courts:      courts,
+		sessions:    sessions,
+		queue:       queue,
+		users:       users,
+		matches:     matches,
+		tournaments: NewTournamentService(matches, audit, store),
+	}
+}
+
+// addPlayers registers players in the default club
+func (ts *testServices) addPlayers(t *testing.T, n int) []int64 {
+	t.Helper()
+
+	ids := make([]int64, n)
+	for i := range ids {
+		user := &model.User{
+			Username: fmt.Sprintf("player%d_%d", i, time.Now().UnixNano()),
+			Name:     fmt.Sprintf("Player %d", i),
+			Role:     model.RolePlayer,
+			IsActive: true,
+		}
+		if err := ts.store.Users().Create(context.Background(), user); err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		ids[i] = user.ID
+	}
+	return ids
+}
+
+// addCourt registers an active court in the default club
+func (ts *testServices) addCourt(t *testing.T, name string) *model.Court {
+	t.Helper()
+
+	court, err := ts.courts.Create(model.Actor{}, database.MemoryDefaultClubID, model.CreateCourtRequest{Name: name})
+	if err != nil {
+		t.Fatalf("create court %s: %v", name, err)
+	}
+	return court
+}
+
+var straightGames = []model.GameScore{{Team1Score: 21, Team2Score: 15}, {Team1Score: 21, Team2Score: 18}}
+
+func TestMatchLifecycle(t *testing.T) {
+	ts := newTestServices(t)
+	club := database.MemoryDefaultClubID
+	ts.addCourt(t, "Court 1")
+	players := ts.addPlayers(t, 4)
+
+	for _, id := range players {
+		if _, err := ts.queue.Join(club, 0, id); err != nil {
+			t.Fatalf("join queue: %v", err)
+		}
+	}
+
+	match, err := ts.matches.Create(model.Actor{}, club, 0, "Court 1", players[:2], players[2:], nil)
+	if err != nil {
+		t.Fatalf("create match: %v", err)
+	}
+	if _, err := ts.courts.NextAvailable(club, nil); err != ErrNoCourtAvailable {
+		t.Fatalf("NextAvailable with the only court busy = %v, want ErrNoCourtAvailable", err)
+	}
+
+	if _, err := ts.matches.RecordResult(model.Actor{}, club, match.ID, straightGames); err != nil {
+		t.Fatalf("record result: %v", err)
+	}
+	if _, err := ts.matches.RecordResult(model.Actor{}, club, match.ID, straightGames); err != ErrMatchAlreadyRecorded {
+		t.Fatalf("recording twice = %v, want ErrMatchAlreadyRecorded", err)
+	}
+
+	for i, id := range players {
+		stats, err := ts.users.GetStats(id)
+		if err != nil {
+			t.Fatalf("stats: %v", err)
+		}
+		wantWins := 0
+		if i < 2 {
+			wantWins = 1
+		}
+		if stats.TotalMatches != 1 || stats.Wins != wantWins {
+			t.Errorf("player %d: %d matches, %d wins; want 1, %d", id, stats.TotalMatches, stats.Wins, wantWins)
+		}
+		if (i < 2) != (stats.Rating > DefaultRating) {
+			t.Errorf("player %d rating %.1f moved the wrong way", id, stats.Rating)
+		}
+	}
+
+	if court, err := ts.courts.NextAvailable(club, nil); err != nil || court.Name != "Court 1" {
+		t.Fatalf("NextAvailable after the result = %v, %v; want Court 1", court, err)
+	}
+	info, err := ts.queue.GetStatus(club, 0, players[0])
+	if err != nil {
+		t.Fatalf("queue status: %v", err)
+	}
+	if len(info.CurrentlyPlaying) != 0 {
+		t.Errorf("%d players still playing after the result", len(info.CurrentlyPlaying))
+	}
+}
+
+func TestMatchCreateRejects(t *testing.T) {
+	ts := newTestServices(t)
+	club := database.MemoryDefaultClubID
+	ts.addCourt(t, "Court 1")
+	closed, _ := ts.courts.Create(model.Actor{}, club, model.CreateCourtRequest{Name: "Court 2", Status: string(model.CourtStatusMaintenance)})
+	players := ts.addPlayers(t, 4)
+
+	outsiders := ts.addPlayers(t, 1)
+	if err := ts.store.Clubs().RemoveMember(context.Background(), club, outsiders[0]); err != nil {
+		t.Fatalf("remove member: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		court string
+		team1 []int64
+		team2 []int64
+		want  error
+	}{
+		{"empty team", "Court 1", nil, players[2:], ErrInvalidTeam},
+		{"three a side", "Court 1", players[:3], players[3:], ErrInvalidTeam},
+		{"player on both teams", "Court 1", players[:2], []int64{players[2], players[0]}, ErrInvalidTeam},
+		{"player twice on a team", "Court 1", []int64{players[0], players[0]}, players[2:], ErrInvalidTeam},
+		{"singles against themselves", "Court 1", players[:1], players[:1], ErrInvalidTeam},
+		{"unknown court", "Court 9", players[:2], players[2:], ErrCourtNotFound},
+		{"court under maintenance", closed.Name, players[:2], players[2:], ErrCourtInactive},
+		{"player outside the club", "Court 1", []int64{players[0], outsiders[0]}, players[2:], ErrNotClubMember},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ts.matches.Create(model.Actor{}, club, 0, tt.court, tt.team1, tt.team2, nil); err != tt.want {
+				t.Errorf("Create = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCourtDoubleBooking(t *testing.T) {
+	ts := newTestServices(t)
+	club := database.MemoryDefaultClubID
+	court := ts.addCourt(t, "Court 1")
+	ts.addCourt(t, "Court 2")
+	players := ts.addPlayers(t, 8)
+
+	first, err := ts.matches.Create(model.Actor{}, club, 0, "Court 1", players[:2], players[2:4], nil)
+	if err != nil {
+		t.Fatalf("create match: %v", err)
+	}
+	if _, err := ts.matches.Create(model.Actor{}, club, 0, "Court 1", players[4:6], players[6:], nil); err != ErrCourtBusy {
+		t.Fatalf("second match on a busy court = %v, want ErrCourtBusy", err)
+	}
+
+	// Renaming the court keeps its pending match on it
+	if _, err := ts.courts.Update(model.Actor{}, club, court.ID, model.UpdateCourtRequest{
+		Name: "Centre Court", Status: string(model.CourtStatusActive), Surface: string(model.SurfaceWood),
+	}); err != nil {
+		t.Fatalf("rename court: %v", err)
+	}
+	if _, err := ts.matches.Create(model.Actor{}, club, 0, "Centre Court", players[4:6], players[6:], nil); err != ErrCourtBusy {
+		t.Errorf("match on the renamed busy court = %v, want ErrCourtBusy", err)
+	}
+	if next, err := ts.courts.NextAvailable(club, nil); err != nil || next.Name != "Court 2" {
+		t.Errorf("NextAvailable = %v, %v; want Court 2", next, err)
+	}
+
+	active, err := ts.matches.GetActive(club, 0)
+	if err != nil || len(active) != 1 || active[0].ID != first.ID || active[0].Court != "Centre Court" {
+		t.Errorf("active matches = %+v, %v; want the first match on Centre Court", active, err)
+	}
+}
+
+func TestAutoDispatchPerClub(t *testing.T) {
+	ts := newTestServices(t)
+	club := database.MemoryDefaultClubID
+	ts.addCourt(t, "Court 1")
+	players := ts.addPlayers(t, 8)
+
+	other, err := ts.clubs.Create(model.Actor{UserID: players[0]}, model.CreateClubRequest{Slug: "other", Name: "Other"})
+	if err != nil {
+		t.Fatalf("create club: %v", err)
+	}
+
+	ts.matches.SetDefaultAutoDispatch(false)
+	if err := ts.matches.SetAutoDispatch(model.Actor{UserID: players[0]}, club, true); err != nil {
+		t.Fatalf("enable auto-dispatch: %v", err)
+	}
+	if !ts.matches.AutoDispatchEnabled(club) || ts.matches.AutoDispatchEnabled(other.ID) {
+		t.Fatalf("auto-dispatch should be on only for the club that enabled it")
+	}
+	if err := ts.matches.SetAutoDispatch(model.Actor{}, 999, true); err != ErrClubNotFound {
+		t.Errorf("enabling for an unknown club = %v, want ErrClubNotFound", err)
+	}
+
+	for _, id := range players[4:] {
+		if _, err := ts.queue.Join(club, 0, id); err != nil {
+			t.Fatalf("join queue: %v", err)
+		}
+	}
+	match, err := ts.matches.Create(model.Actor{}, club, 0, "Court 1", players[:2], players[2:4], nil)
+	if err != nil {
+		t.Fatalf("create match: %v", err)
+	}
+	recorded, err := ts.matches.RecordResult(model.Actor{}, club, match.ID, straightGames)
+	if err != nil {
+		t.Fatalf("record result: %v", err)
+	}
+	if recorded.NextMatch == nil || recorded.NextMatch.Court != "Court 1" {
+		t.Fatalf("freed court was not refilled: %+v", recorded.NextMatch)
+	}
+}
+
+func TestSessionClose(t *testing.T) {
+	ts := newTestServices(t)
+	club := database.MemoryDefaultClubID
+	court := ts.addCourt(t, "Court 1")
+	players := ts.addPlayers(t, 5)
+
+	start := time.Now()
+	sess, err := ts.sessions.Create(model.Actor{}, club, model.SessionRequest{
+		Name: "Tuesday", CourtIDs: []int64{court.ID}, StartsAt: start, EndsAt: start.Add(3 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if _, err := ts.sessions.Close(model.Actor{}, club, sess.ID); err != ErrSessionNotOpen {
+		t.Fatalf("closing a scheduled session = %v, want ErrSessionNotOpen", err)
+	}
+	if _, err := ts.sessions.Open(model.Actor{}, club, sess.ID); err != nil {
+		t.Fatalf("open session: %v", err)
+	}
+	if id, err := ts.sessions.Resolve(club, 0); err != nil || id != sess.ID {
+		t.Fatalf("Resolve = %d, %v; want the open session %d", id, err, sess.ID)
+	}
+
+	for _, id := range players {
+		if _, err := ts.queue.Join(club, sess.ID, id); err != nil {
+			t.Fatalf("join queue: %v", err)
+		}
+	}
+	match, err := ts.matches.Create(model.Actor{}, club, sess.ID, court.Name, players[:2], players[2:4], nil)
+	if err != nil {
+		t.Fatalf("create match: %v", err)
+	}
+
+	if _, err := ts.sessions.Close(model.Actor{}, club, sess.ID); err != ErrSessionActiveMatches {
+		t.Fatalf("closing with a match on court = %v, want ErrSessionActiveMatches", err)
+	}
+	if _, err := ts.matches.RecordResult(model.Actor{}, club, match.ID, straightGames); err != nil {
+		t.Fatalf("record result: %v", err)
+	}
+
+	closed, err := ts.sessions.Close(model.Actor{}, club, sess.ID)
+	if err != nil {
+		t.Fatalf("close session: %v", err)
+	}
+	if closed.Status != model.SessionClosed || closed.ClosedAt == nil {
+		t.Errorf("closed session has status %s, closed at %v", closed.Status, closed.ClosedAt)
+	}
+	waiting, _ := ts.store.Queue().Count(context.Background(), club, sess.ID, model.QueueStatusWaiting)
+	if waiting != 0 {
+		t.Errorf("%d players still waiting in a closed session", waiting)
+	}
+	if _, err := ts.sessions.Update(model.Actor{}, club, sess.ID, model.SessionRequest{
+		Name: "Tuesday", StartsAt: start, EndsAt: start.Add(time.Hour),
+	}); err != ErrSessionClosed {
+		t.Errorf("updating a closed session = %v, want ErrSessionClosed", err)
+	}
+}
+
+func TestCourtRegistry(t *testing.T) {
+	ts := newTestServices(t)
+	club := database.MemoryDefaultClubID
+	first := ts.addCourt(t, "Court 1")
+	second := ts.addCourt(t, "Court 2")
+
+	if _, err := ts.courts.Create(model.Actor{}, club, model.CreateCourtRequest{Name: " Court 1 "}); err != ErrCourtExists {
+		t.Errorf("duplicate name = %v, want ErrCourtExists", err)
+	}
+	if _, err := ts.courts.Create(model.Actor{}, club, model.CreateCourtRequest{Name: "Court 3", Surface: "grass"}); err != ErrInvalidCourt {
+		t.Errorf("unknown surface = %v, want ErrInvalidCourt", err)
+	}
+	if _, err := ts.courts.Update(model.Actor{}, club, second.ID, model.UpdateCourtRequest{
+		Name: "Court 1", Status: string(model.CourtStatusActive), Surface: string(model.SurfaceWood),
+	}); err != ErrCourtExists {
+		t.Errorf("renaming onto another court = %v, want ErrCourtExists", err)
+	}
+
+	if _, err := ts.courts.Update(model.Actor{}, club, first.ID, model.UpdateCourtRequest{
+		Name: "Court 1", Status: string(model.CourtStatusMaintenance), Surface: string(model.SurfaceWood),
+	}); err != nil {
+		t.Fatalf("update court: %v", err)
+	}
+	active, err := ts.courts.List(club, true)
+	if err != nil || len(active) != 1 || active[0].ID != second.ID {
+		t.Errorf("active courts = %v, %v; want only Court 2", active, err)
+	}
+
+	if err := ts.courts.Delete(model.Actor{}, club, first.ID); err != nil {
+		t.Fatalf("delete court: %v", err)
+	}
+	if _, err := ts.courts.GetByID(club, first.ID); err != ErrCourtNotFound {
+		t.Errorf("deleted court lookup = %v, want ErrCourtNotFound", err)
+	}
+}
+
+func TestClubMembership(t *testing.T) {
+	ts := newTestServices(t)
+	players := ts.addPlayers(t, 2)
+	owner, other := players[0], players[1]
+
+	club, err := ts.clubs.Create(model.Actor{UserID: owner}, model.CreateClubRequest{Slug: "north", Name: "North"})
+	if err != nil {
+		t.Fatalf("create club: %v", err)
+	}
+	if _, err := ts.clubs.Create(model.Actor{UserID: owner}, model.CreateClubRequest{Slug: "north", Name: "Again"}); err != ErrClubExists {
+		t.Errorf("duplicate slug = %v, want ErrClubExists", err)
+	}
+
+	if _, err := ts.clubs.SetMember(model.Actor{UserID: owner}, club.ID, model.ClubMemberRequest{UserID: owner, Role: model.RolePlayer}); err != ErrLastClubAdmin {
+		t.Errorf("demoting the only admin = %v, want ErrLastClubAdmin", err)
+	}
+	if err := ts.clubs.RemoveMember(model.Actor{UserID: owner}, club.ID, owner); err != ErrLastClubAdmin {
+		t.Errorf("removing the only admin = %v, want ErrLastClubAdmin", err)
+	}
+	if err := ts.clubs.RemoveMember(model.Actor{UserID: owner}, club.ID, other); err != ErrNotClubMember {
+		t.Errorf("removing a non-member = %v, want ErrNotClubMember", err)
+	}
+
+	clubs, err := ts.clubs.ListForUser(owner)
+	if err != nil || len(clubs) != 2 {
+		t.Fatalf("owner's clubs = %v, %v; want the default club and North", clubs, err)
+	}
+}
+
+func TestMemberProfile(t *testing.T) {
+	ts := newTestServices(t)
+	users := NewUserService(newTestAuth(ts.store), NewAuditLog(ts.store), ts.store)
+	players := ts.addPlayers(t, 2)
+	owner, outsider := players[0], players[1]
+
+	club, err := ts.clubs.Create(model.Actor{UserID: owner}, model.CreateClubRequest{Slug: "north", Name: "North"})
+	if err != nil {
+		t.Fatalf("create club: %v", err)
+	}
+
+	if profile, err := users.MemberProfile(club.ID, owner); err != nil || profile.User.ID != owner {
+		t.Errorf("member's profile = %v, %v; want user %d", profile, err, owner)
+	}
+	if _, err := users.MemberProfile(club.ID, outsider); err != ErrUserNotFound {
+		t.Errorf("profile of a user from another club = %v, want ErrUserNotFound", err)
+	}
+	if _, err := users.MemberProfile(database.MemoryDefaultClubID, outsider); err != nil {
+		t.Errorf("profile in the user's own club: %v", err)
+	}
+}
+
+func TestClubInvites(t *testing.T) {
+	ts := newTestServices(t)
+	players := ts.addPlayers(t, 2)
+	owner, guest := players[0], players[1]
+	admin := model.Actor{UserID: owner}
+
+	club, err := ts.clubs.Create(admin, model.CreateClubRequest{Slug: "east", Name: "East"})
+	if err != nil {
+		t.Fatalf("create club: %v", err)
+	}
+
+	if _, err := ts.clubs.SetMember(admin, club.ID, model.ClubMemberRequest{UserID: guest, Role: model.RoleOrganizer}); err != ErrNotClubMember {
+		t.Errorf("setting a non-member's role = %v, want ErrNotClubMember", err)
+	}
+	if _, err := ts.clubs.InviteMember(admin, club.ID, model.ClubMemberRequest{UserID: owner}); err != ErrAlreadyMember {
+		t.Errorf("inviting a member = %v, want ErrAlreadyMember", err)
+	}
+	if _, err := ts.clubs.AcceptInvite(model.Actor{UserID: guest}, club.ID); err != ErrNoInvite {
+		t.Errorf("accepting without an invite = %v, want ErrNoInvite", err)
+	}
+
+	if _, err := ts.clubs.InviteMember(admin, club.ID, model.ClubMemberRequest{UserID: guest, Role: model.RoleOrganizer}); err != nil {
+		t.Fatalf("invite: %v", err)
+	}
+	if _, err := ts.clubs.Membership(club.ID, guest); err != ErrNotClubMember {
+		t.Errorf("membership before accepting = %v, want ErrNotClubMember", err)
+	}
+
+	invites, err := ts.clubs.Invites(guest)
+	if err != nil || len(invites) != 1 || invites[0].ClubName != "East" {
+		t.Fatalf("guest's invites = %v, %v; want the invite to East", invites, err)
+	}
+
+	member, err := ts.clubs.AcceptInvite(model.Actor{UserID: guest}, club.ID)
+	if err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	if member.Role != model.RoleOrganizer {
+		t.Errorf("role after accepting = %s, want organizer", member.Role)
+	}
+	if invites, _ := ts.clubs.Invites(guest); len(invites) != 0 {
+		t.Errorf("invites after accepting = %v, want none", invites)
+	}
+
+	if _, err := ts.clubs.InviteMember(admin, database.MemoryDefaultClubID+100, model.ClubMemberRequest{UserID: guest}); err != ErrClubNotFound {
+		t.Errorf("inviting to a missing club = %v, want ErrClubNotFound", err)
+	}
+	if err := ts.clubs.DeclineInvite(model.Actor{UserID: guest}, club.ID); err != ErrNoInvite {
+		t.Errorf("declining a used invite = %v, want ErrNoInvite", err)
+	}
+}
+
+// newTestAuth creates an auth service on the store, which adds the built-in admin
+func newTestAuth(store *database.MemoryStore) *AuthService {
+	cfg := &config.Config{Auth: config.AuthConfig{
+		SecretKey:            "0123456789abcdef0123456789abcdef",
+		AccessTokenDuration:  time.Minute,
+		RefreshTokenDuration: time.Hour,
+		ResetCodeDuration:    time.Minute,
+	}}
+	return NewAuthService(cfg, store, nil, NewAuditLog(store))
+}
+
+func TestLoginThrottleUnderConcurrentGuesses(t *testing.T) {
+	auth := newTestAuth(database.NewMemoryStore())
+
+	const guesses = 8
+	var wg sync.WaitGroup
+	results := make(chan error, guesses)
+	for i := 0; i < guesses; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := auth.Login(model.LoginRequest{Username: "kong@admin", Password: "wrong"}, model.ClientInfo{})
+			results <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	checked, throttled := 0, 0
+	for err := range results {
+		switch {
+		case err == ErrInvalidCredentials:
+			checked++
+		case errors.Is(err, ErrLoginThrottled):
+			throttled++
+		default:
+			t.Errorf("unexpected login error: %v", err)
+		}
+	}
+	if checked != throttleAfterFailures || throttled != guesses-throttleAfterFailures {
+		t.Errorf("passwords checked = %d, throttled = %d; want %d and %d",
+			checked, throttled, throttleAfterFailures, guesses-throttleAfterFailures)
+	}
+
+	// The right password is throttled too until the delay passes
+	if _, _, err := auth.Login(model.LoginRequest{Username: "kong@admin", Password: "Admin@123!"}, model.ClientInfo{}); !errors.Is(err, ErrLoginThrottled) {
+		t.Errorf("login while throttled = %v, want ErrLoginThrottled", err)
+	}
+}
+
+// sentCodes records the reset codes an auth service sends
+type sentCodes struct {
+	mu    sync.Mutex
+	codes []string
+}
+
+func (n *sentCodes) Send(ctx context.Context, user *model.User, message string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.codes = append(n.codes, regexp.MustCompile(`\d{6}`).FindString(message))
+	return nil
+}
+
+func (n *sentCodes) last() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.codes[len(n.codes)-1]
+}
+
+func TestResetCodeGuessLimit(t *testing.T) {
+	auth := newTestAuth(database.NewMemoryStore())
+	sent := &sentCodes{}
+	auth.notifier = sent
+	const login, password = "kong@admin", "Newpass@123!"
+
+	request := func() string {
+		t.Helper()
+		if err := auth.RequestPasswordReset(model.ForgotPasswordRequest{Username: login}); err != nil {
+			t.Fatalf("request reset: %v", err)
+		}
+		return sent.last()
+	}
+	reset := func(code string) error {
+		return auth.ResetPassword(model.ResetPasswordRequest{Username: login, Code: code, NewPassword: password})
+	}
+	wrong := func(code string) string {
+		n, _ := strconv.Atoi(code)
+		return fmt.Sprintf("%06d", (n+1)%1_000_000)
+	}
+
+	// The sixth guess is refused even when it is right
+	code := request()
+	for i := 0; i < maxResetAttempts; i++ {
+		if err := reset(wrong(code)); err != ErrInvalidResetCode {
+			t.Fatalf("wrong guess %d = %v, want ErrInvalidResetCode", i+1, err)
+		}
+	}
+	if err := reset(code); err != ErrInvalidResetCode {
+		t.Fatalf("right code as guess %d = %v, want ErrInvalidResetCode", maxResetAttempts+1, err)
+	}
+
+	// Parallel guesses cannot get past the limit either
+	code = request()
+	var wg sync.WaitGroup
+	for i := 0; i < 2*maxResetAttempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reset(wrong(code))
+		}()
+	}
+	wg.Wait()
+	if err := reset(code); err != ErrInvalidResetCode {
+		t.Fatalf("right code after %d parallel guesses = %v, want ErrInvalidResetCode", 2*maxResetAttempts, err)
+	}
+
+	// The last code allowed this hour still works, and no more are sent
+	code = request()
+	request()
+	if len(sent.codes) != maxResetRequests {
+		t.Errorf("%d codes sent, want %d", len(sent.codes), maxResetRequests)
+	}
+	if err := reset(code); err != nil {
+		t.Errorf("reset with the right code: %v", err)
+	}
+}
+
+func TestStreamTicketIsSingleUse(t *testing.T) {
+	auth := newTestAuth(database.NewMemoryStore())
+	admin, err := auth.store.Users().GetByUsername(context.Background(), "kong@admin")
+	if err != nil {
+		t.Fatalf("load admin: %v", err)
+	}
+
+	ticket, err := auth.IssueStreamTicket(admin.ID)
+	if err != nil {
+		t.Fatalf("issue ticket: %v", err)
+	}
+
+	payload, err := auth.RedeemStreamTicket(ticket.Ticket)
+	if err != nil || payload.UserID != admin.ID {
+		t.Fatalf("redeem = %v, %v; want the admin's claims", payload, err)
+	}
+	if _, err := auth.RedeemStreamTicket(ticket.Ticket); err != ErrInvalidToken {
+		t.Errorf("second redeem = %v, want ErrInvalidToken", err)
+	}
+	if _, err := auth.RedeemStreamTicket("not-a-ticket"); err != ErrInvalidToken {
+		t.Errorf("unknown ticket = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestDeleteUserWithMatchHistory(t *testing.T) {
+	ts := newTestServices(t)
+	auth := newTestAuth(ts.store)
+	builtin, err := ts.store.Users().GetByUsername(context.Background(), "kong@admin")
+	if err != nil {
+		t.Fatalf("load admin: %v", err)
+	}
+	admin := model.Actor{UserID: builtin.ID}
+	club := database.MemoryDefaultClubID
+	ts.addCourt(t, "Court 1")
+	players := ts.addPlayers(t, 5)
+
+	match, err := ts.matches.Create(model.Actor{}, club, 0, "Court 1", players[:2], players[2:4], nil)
+	if err != nil {
+		t.Fatalf("create match: %v", err)
+	}
+	if err := auth.DeleteUser(admin, players[0], false); err != ErrUserInMatch {
+		t.Errorf("deleting a player mid-match = %v, want ErrUserInMatch", err)
+	}
+	if _, err := ts.matches.RecordResult(model.Actor{}, club, match.ID, straightGames); err != nil {
+		t.Fatalf("record result: %v", err)
+	}
+
+	if err := auth.DeleteUser(admin, players[0], false); err != ErrUserHasMatches {
+		t.Errorf("hard-deleting a player with history = %v, want ErrUserHasMatches", err)
+	}
+	if err := auth.DeleteUser(admin, players[0], true); err != nil {
+		t.Errorf("anonymizing a player with history: %v", err)
+	}
+	if err := auth.DeleteUser(admin, players[4], false); err != nil {
+		t.Errorf("hard-deleting a player without history: %v", err)
+	}
+
+	if _, err := ts.users.RecomputeAllStats(model.Actor{}); err != nil {
+		t.Errorf("recompute after deletes: %v", err)
+	}
+}
+
+func TestDeleteUserLeavesNothingBehind(t *testing.T) {
+	ts := newTestServices(t)
+	ctx := context.Background()
+	auth := newTestAuth(ts.store)
+	builtin, err := ts.store.Users().GetByUsername(ctx, "kong@admin")
+	if err != nil {
+		t.Fatalf("load admin: %v", err)
+	}
+	admin := model.Actor{UserID: builtin.ID}
+	club := database.MemoryDefaultClubID
+	players := ts.addPlayers(t, 5)
+
+	// An abandoned match stays pending behind a long run of finished ones
+	abandoned := &model.Match{ClubID: club, Team1: players[:2], Team2: players[2:4]}
+	if err := ts.store.Matches().Create(ctx, abandoned); err != nil {
+		t.Fatalf("create match: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	for i := 0; i < 60; i++ {
+		m := &model.Match{ClubID: club, Team1: players[:2], Team2: players[2:4]}
+		if err := ts.store.Matches().Create(ctx, m); err != nil {
+			t.Fatalf("create match: %v", err)
+		}
+		if err := ts.store.Matches().Complete(ctx, m.ID, string(model.MatchResultTeam1), time.Now(), straightGames); err != nil {
+			t.Fatalf("complete match: %v", err)
+		}
+	}
+	if err := auth.DeleteUser(admin, players[0], true); err != ErrUserInMatch {
+		t.Errorf("anonymizing a player with an old unfinished match = %v, want ErrUserInMatch", err)
+	}
+
+	owner, deputy := players[4], players[3]
+	north, err := ts.clubs.Create(model.Actor{UserID: owner}, model.CreateClubRequest{Slug: "north", Name: "North"})
+	if err != nil {
+		t.Fatalf("create club: %v", err)
+	}
+	if err := auth.DeleteUser(admin, owner, true); err != ErrLastClubAdmin {
+		t.Errorf("anonymizing a club's only admin = %v, want ErrLastClubAdmin", err)
+	}
+
+	if _, err := ts.clubs.InviteMember(model.Actor{UserID: owner}, north.ID, model.ClubMemberRequest{UserID: deputy, Role: model.RoleAdmin}); err != nil {
+		t.Fatalf("invite: %v", err)
+	}
+	if _, err := ts.clubs.AcceptInvite(model.Actor{UserID: deputy}, north.ID); err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	if err := auth.DeleteUser(admin, owner, true); err != nil {
+		t.Fatalf("anonymizing a club admin with a deputy: %v", err)
+	}
+	if clubs, err := ts.clubs.ListForUser(owner); err != nil || len(clubs) != 0 {
+		t.Errorf("anonymized user's clubs = %v, %v; want none", clubs, err)
+	}
+}
+
+func TestLeagueFollowsCorrections(t *testing.T) {
+	ts := newTestServices(t)
+	club := database.MemoryDefaultClubID
+	ts.addCourt(t, "Court 1")
+	players := ts.addPlayers(t, 2)
+
+	league, err := ts.tournaments.Create(model.Actor{}, club, model.TournamentRequest{
+		Name: "Ladder", Format: model.FormatRoundRobin, EntryType: model.EntrySingles,
+	})
+	if err != nil {
+		t.Fatalf("create league: %v", err)
+	}
+	for _, id := range players {
+		if _, err := ts.tournaments.AddEntry(model.Actor{}, club, league.ID, model.TournamentEntryRequest{Players: []int64{id}}); err != nil {
+			t.Fatalf("add entry: %v", err)
+		}
+	}
+	drawn, err := ts.tournaments.Start(model.Actor{}, club, league.ID)
+	if err != nil {
+		t.Fatalf("start league: %v", err)
+	}
+	if len(drawn.Fixtures) != 1 {
+		t.Fatalf("%d fixtures for two entries, want 1", len(drawn.Fixtures))
+	}
+	fixture := drawn.Fixtures[0]
+
+	match, err := ts.tournaments.StartFixture(model.Actor{}, club, league.ID, fixture.ID, "")
+	if err != nil {
+		t.Fatalf("start fixture: %v", err)
+	}
+	if _, err := ts.matches.RecordResult(model.Actor{}, club, match.ID, straightGames); err != nil {
+		t.Fatalf("record result: %v", err)
+	}
+
+	winner := func() *int64 {
+		t.Helper()
+		got, err := ts.tournaments.Get(club, league.ID)
+		if err != nil {
+			t.Fatalf("get league: %v", err)
+		}
+		return got.Tournament.WinnerEntryID
+	}
+	if w := winner(); w == nil || *w != fixture.Entry1ID {
+		t.Fatalf("winner after the result = %v, want entry %d", w, fixture.Entry1ID)
+	}
+
+	flipped := []model.GameScore{{Team1Score: 15, Team2Score: 21}, {Team1Score: 18, Team2Score: 21}}
+	if _, err := ts.matches.CorrectResult(model.Actor{}, club, match.ID, model.CorrectResultRequest{Scores: flipped}); err != nil {
+		t.Fatalf("correct result: %v", err)
+	}
+	if w := winner(); w == nil || *w != fixture.Entry2ID {
+		t.Fatalf("winner after flipping the result = %v, want entry %d", w, fixture.Entry2ID)
+	}
+
+	if _, err := ts.matches.VoidMatch(model.Actor{}, club, match.ID, "wrong players"); err != nil {
+		t.Fatalf("void: %v", err)
+	}
+	got, err := ts.tournaments.Get(club, league.ID)
+	if err != nil {
+		t.Fatalf("get league: %v", err)
+	}
+	if got.Tournament.Status != model.TournamentInProgress || got.Tournament.WinnerEntryID != nil {
+		t.Errorf("league after void: status %s, winner %v; want in progress without a winner",
+			got.Tournament.Status, got.Tournament.WinnerEntryID)
+	}
+	if f := got.Fixtures[0]; f.Status != model.FixtureScheduled || f.MatchID != nil {
+		t.Errorf("fixture after void: status %s, match %v; want scheduled without a match", f.Status, f.MatchID)
+	}
+	if _, err := ts.tournaments.StartFixture(model.Actor{}, club, league.ID, fixture.ID, ""); err != nil {
+		t.Errorf("replaying the voided fixture: %v", err)
+	}
+}
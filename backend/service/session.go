@@ -0,0 +1,263 @@
+package service
+
+import (
+	"backend/database"
+	"backend/model"
+	"context"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrSessionNotFound      = errors.New("session not found")
+	ErrSessionNotOpen       = errors.New("session is not open")
+	ErrSessionClosed        = errors.New("session is closed")
+	ErrSessionActiveMatches = errors.New("session still has matches in progress")
+	ErrInvalidSession       = errors.New("invalid session details")
+	ErrCourtNotInSession    = errors.New("court is not booked for this session")
+)
+
+// SessionService manages play sessions. A session scopes its own queue and matches;
+// when no session is open the legacy global queue (session ID 0) is used.
+type SessionService struct {
+	courtService *CourtService
+	events       *EventBroker
+	audit        *AuditLog
+	store        database.Store
+}
+
+// NewSessionService creates a new session service
+func NewSessionService(courtSvc *CourtService, events *EventBroker, audit *AuditLog, store database.Store) *SessionService {
+	return &SessionService{
+		courtService: courtSvc,
+		events:       events,
+		audit:        audit,
+		store:        store,
+	}
+}
+
+// List returns a club's sessions, newest first, optionally filtered by status
+func (s *SessionService) List(clubID int64, status string, limit int) ([]model.Session, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	return s.store.Sessions().List(context.Background(), clubID, model.SessionStatus(status), limit)
+}
+
+// GetByID returns one of a club's sessions by ID
+func (s *SessionService) GetByID(clubID, sessionID int64) (*model.Session, error) {
+	sess, err := s.store.Sessions().Get(context.Background(), clubID, sessionID)
+	if err == database.ErrNotFound {
+		return nil, ErrSessionNotFound
+	}
+	return sess, err
+}
+
+// Current returns the club's most recently opened session that is still open
+func (s *SessionService) Current(clubID int64) (*model.Session, error) {
+	sess, err := s.store.Sessions().Current(context.Background(), clubID)
+	if err == database.ErrNotFound {
+		return nil, ErrSessionNotFound
+	}
+	return sess, err
+}
+
+// Resolve picks the session a queue or match request applies to.
+// An explicit ID must refer to an open session of the club; 0 falls back to the
+// club's current open session, or to its legacy queue when none is open.
+func (s *SessionService) Resolve(clubID, requested int64) (int64, error) {
+	if requested > 0 {
+		sess, err := s.GetByID(clubID, requested)
+		if err != nil {
+			return 0, err
+		}
+		if sess.Status != model.SessionOpen {
+			return 0, ErrSessionNotOpen
+		}
+		return sess.ID, nil
+	}
+
+	sess, err := s.Current(clubID)
+	if err == ErrSessionNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return sess.ID, nil
+}
+
+// CourtIDs returns the courts booked for a session, or nil when any court may be used
+func (s *SessionService) CourtIDs(sessionID int64) []int64 {
+	if sessionID == 0 {
+		return nil
+	}
+	sess, err := s.store.Sessions().Get(context.Background(), 0, sessionID)
+	if err != nil {
+		return nil
+	}
+	return sess.CourtIDs
+}
+
+// Create schedules a new session for a club
+func (s *SessionService) Create(actor model.Actor, clubID int64, req model.SessionRequest) (*model.Session, error) {
+	scoring, err := s.validate(clubID, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	sess := &model.Session{
+		ClubID:    clubID,
+		Name:      req.Name,
+		Venue:     req.Venue,
+		CourtIDs:  req.CourtIDs,
+		Fee:       req.Fee,
+		Scoring:   scoring,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		CreatedBy: actor.UserID,
+	}
+	if err := s.store.Sessions().Create(context.Background(), sess); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, clubID, model.AuditSessionCreated, "session", sess.ID, nil, sess)
+
+	return sess, nil
+}
+
+// Update changes the details of a session that has not been closed
+func (s *SessionService) Update(actor model.Actor, clubID, sessionID int64, req model.SessionRequest) (*model.Session, error) {
+	scoring, err := s.validate(clubID, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.GetByID(clubID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status == model.SessionClosed {
+		return nil, ErrSessionClosed
+	}
+
+	sess := *existing
+	sess.Name = req.Name
+	sess.Venue = req.Venue
+	sess.CourtIDs = req.CourtIDs
+	sess.Fee = req.Fee
+	sess.Scoring = scoring
+	sess.StartsAt = req.StartsAt
+	sess.EndsAt = req.EndsAt
+	if err := s.store.Sessions().Update(context.Background(), &sess); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, clubID, model.AuditSessionUpdated, "session", sessionID, existing, sess)
+
+	return &sess, nil
+}
+
+// Open starts a scheduled session so players can queue for it
+func (s *SessionService) Open(actor model.Actor, clubID, sessionID int64) (*model.Session, error) {
+	before, err := s.GetByID(clubID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	sess, err := s.store.Sessions().Open(context.Background(), clubID, sessionID)
+	if err == database.ErrNotFound {
+		return nil, ErrSessionClosed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(clubID, model.EventSessionOpened, sess)
+	s.audit.Record(actor, clubID, model.AuditSessionOpened, "session", sessionID, before, sess)
+
+	return sess, nil
+}
+
+// Close ends an open session and clears anyone still waiting in its queue.
+// All matches must have been recorded first.
+func (s *SessionService) Close(actor model.Actor, clubID, sessionID int64) (*model.Session, error) {
+	ctx := context.Background()
+
+	var before, sess *model.Session
+	err := s.store.WithTx(ctx, func(tx database.Store) error {
+		current, err := tx.Sessions().GetForUpdate(ctx, clubID, sessionID)
+		if err == database.ErrNotFound {
+			return ErrSessionNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if current.Status != model.SessionOpen {
+			return ErrSessionNotOpen
+		}
+		before = current
+
+		pending, err := tx.Matches().Active(ctx, database.MatchFilter{ClubID: clubID, SessionID: sessionID, Limit: 1})
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return ErrSessionActiveMatches
+		}
+
+		if err := tx.Queue().Clear(ctx, clubID, sessionID); err != nil {
+			return err
+		}
+
+		sess, err = tx.Sessions().Close(ctx, clubID, sessionID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(clubID, model.EventSessionClosed, sess)
+	s.audit.Record(actor, clubID, model.AuditSessionClosed, "session", sessionID, before, sess)
+
+	return sess, nil
+}
+
+// validate normalizes a session request and returns its scoring rules
+func (s *SessionService) validate(clubID int64, req *model.SessionRequest) (model.ScoringRules, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Venue = strings.TrimSpace(req.Venue)
+
+	if req.Name == "" || len(req.Name) > 255 || req.Fee < 0 {
+		return model.ScoringRules{}, ErrInvalidSession
+	}
+	if req.StartsAt.IsZero() || !req.EndsAt.After(req.StartsAt) {
+		return model.ScoringRules{}, ErrInvalidSession
+	}
+
+	scoring := model.DefaultScoringRules()
+	if req.Scoring != nil {
+		scoring = *req.Scoring
+	}
+	if err := ValidateScoringRules(scoring); err != nil {
+		return model.ScoringRules{}, err
+	}
+
+	if req.CourtIDs == nil {
+		req.CourtIDs = []int64{}
+	}
+	for _, id := range req.CourtIDs {
+		if _, err := s.courtService.GetByID(clubID, id); err != nil {
+			return model.ScoringRules{}, err
+		}
+	}
+
+	return scoring, nil
+}
+
+func sessionPtr(sessionID int64) *int64 {
+	if sessionID == 0 {
+		return nil
+	}
+	return &sessionID
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"backend/model"
+	"sync"
+	"time"
+)
+
+// streamTicketDuration is how long a stream ticket can be redeemed after it is issued
+const streamTicketDuration = 30 * time.Second
+
+// streamTickets holds the stream tickets not yet redeemed, keyed by their hash
+type streamTickets struct {
+	mu     sync.Mutex
+	issued map[string]streamTicket
+}
+
+type streamTicket struct {
+	userID    int64
+	expiresAt time.Time
+}
+
+// IssueStreamTicket returns a single-use ticket the user can pass as ?ticket= to open
+// the event stream. Unlike an access token, it is worthless once used or expired.
+func (s *AuthService) IssueStreamTicket(userID int64) (*model.StreamTicket, error) {
+	ticket, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(streamTicketDuration)
+
+	s.tickets.mu.Lock()
+	defer s.tickets.mu.Unlock()
+
+	for hash, t := range s.tickets.issued {
+		if !t.expiresAt.After(now) {
+			delete(s.tickets.issued, hash)
+		}
+	}
+	s.tickets.issued[hashToken(ticket)] = streamTicket{userID: userID, expiresAt: expiresAt}
+
+	return &model.StreamTicket{Ticket: ticket, ExpiresAt: expiresAt}, nil
+}
+
+// RedeemStreamTicket spends a stream ticket and returns the claims of the user it was
+// issued to. Unknown, used and expired tickets return ErrInvalidToken.
+func (s *AuthService) RedeemStreamTicket(ticket string) (*model.TokenPayload, error) {
+	hash := hashToken(ticket)
+
+	s.tickets.mu.Lock()
+	t, ok := s.tickets.issued[hash]
+	delete(s.tickets.issued, hash)
+	s.tickets.mu.Unlock()
+
+	if !ok || !t.expiresAt.After(time.Now()) {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.GetUserByID(t.userID)
+	if err == ErrUserNotFound {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrAccountDisabled
+	}
+
+	return &model.TokenPayload{
+		UserID:    user.ID,
+		Username:  user.Username,
+		Role:      user.Role,
+		IssuedAt:  t.expiresAt.Add(-streamTicketDuration),
+		ExpiresAt: t.expiresAt,
+	}, nil
+}
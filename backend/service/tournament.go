@@ -0,0 +1,616 @@
+package service
+
+import (
+	"backend/database"
+	"backend/model"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrTournamentNotFound    = errors.New("tournament not found")
+	ErrTournamentNameMissing = errors.New("tournament name is required")
+	ErrInvalidFormat         = errors.New("invalid tournament format")
+	ErrInvalidEntryType      = errors.New("invalid entry type")
+	ErrInvalidSeeding        = errors.New("invalid seeding method")
+	ErrTournamentStarted     = errors.New("tournament has already started")
+	ErrTournamentNotRunning  = errors.New("tournament is not in progress")
+	ErrEntryNotFound         = errors.New("tournament entry not found")
+	ErrInvalidEntry          = errors.New("entries need one player for singles and two different players for pairs")
+	ErrAlreadyEntered        = errors.New("player is already entered in this tournament")
+	ErrTooFewEntries         = errors.New("not enough entries to draw the bracket")
+	ErrBracketMatchNotFound  = errors.New("bracket match not found")
+	ErrBracketMatchNotReady  = errors.New("bracket match is not ready to play")
+	ErrBracketResultLocked   = errors.New("the winner of a bracket match cannot be changed")
+	ErrInvalidGroups         = errors.New("a round robin needs at least one group")
+	ErrInvalidSlotLength     = errors.New("time slots must be at least one minute long")
+	ErrNoCourts              = errors.New("no courts to schedule fixtures on")
+	ErrFixtureNotFound       = errors.New("fixture not found")
+	ErrFixtureStarted        = errors.New("fixture has already been started")
+)
+
+// TournamentService runs elimination tournaments and round-robin leagues. Bracket matches
+// and fixtures become regular matches when they are put on a court, and recording their
+// result advances the bracket or the standings.
+type TournamentService struct {
+	matchService *MatchService
+	audit        *AuditLog
+	store        database.Store
+}
+
+// NewTournamentService creates a new tournament service
+func NewTournamentService(matchSvc *MatchService, audit *AuditLog, store database.Store) *TournamentService {
+	return &TournamentService{
+		matchService: matchSvc,
+		audit:        audit,
+		store:        store,
+	}
+}
+
+// Create opens a tournament for registration (organizer only). It defaults to a single
+// elimination pairs bracket seeded by rating and played under BWF scoring.
+func (s *TournamentService) Create(actor model.Actor, clubID int64, req model.TournamentRequest) (*model.Tournament, error) {
+	t := &model.Tournament{
+		ClubID:    clubID,
+		Name:      strings.TrimSpace(req.Name),
+		Format:    req.Format,
+		EntryType: req.EntryType,
+		Seeding:   req.Seeding,
+		Scoring:   model.DefaultScoringRules(),
+	}
+	if t.Name == "" {
+		return nil, ErrTournamentNameMissing
+	}
+
+	switch t.Format {
+	case "":
+		t.Format = model.FormatSingleElimination
+	case model.FormatSingleElimination, model.FormatDoubleElimination:
+	case model.FormatRoundRobin:
+		if err := scheduleOptions(t, req); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrInvalidFormat
+	}
+	switch t.EntryType {
+	case "":
+		t.EntryType = model.EntryPairs
+	case model.EntrySingles, model.EntryPairs:
+	default:
+		return nil, ErrInvalidEntryType
+	}
+	switch t.Seeding {
+	case "":
+		t.Seeding = model.SeedByRating
+	case model.SeedByRating, model.SeedBySkillTier:
+	default:
+		return nil, ErrInvalidSeeding
+	}
+
+	if req.Scoring != nil {
+		t.Scoring = *req.Scoring
+	}
+	if err := ValidateScoringRules(t.Scoring); err != nil {
+		return nil, err
+	}
+	if actor.UserID != 0 {
+		t.CreatedBy = &actor.UserID
+	}
+
+	if err := s.store.Tournaments().Create(context.Background(), t); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, clubID, model.AuditTournamentCreated, "tournament", t.ID, nil, t)
+
+	return t, nil
+}
+
+// scheduleOptions sets a round robin's groups, courts and time slots from the request
+func scheduleOptions(t *model.Tournament, req model.TournamentRequest) error {
+	t.Groups, t.SlotMinutes, t.StartsAt = req.Groups, req.SlotMinutes, req.StartsAt
+	if t.Groups == 0 {
+		t.Groups = 1
+	}
+	if t.Groups < 0 {
+		return ErrInvalidGroups
+	}
+	if t.SlotMinutes == 0 {
+		t.SlotMinutes = defaultSlotMinutes
+	}
+	if t.SlotMinutes < 0 {
+		return ErrInvalidSlotLength
+	}
+	for _, name := range req.Courts {
+		if name = strings.TrimSpace(name); name != "" && !containsName(t.Courts, name) {
+			t.Courts = append(t.Courts, name)
+		}
+	}
+	return nil
+}
+
+// List returns the club's tournaments, newest first
+func (s *TournamentService) List(clubID int64) ([]model.Tournament, error) {
+	return s.store.Tournaments().List(context.Background(), clubID)
+}
+
+// Get returns one of the club's tournaments with its entries and bracket
+func (s *TournamentService) Get(clubID, tournamentID int64) (*model.TournamentBracket, error) {
+	ctx := context.Background()
+
+	t, err := s.get(ctx, s.store, clubID, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.entries(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := s.store.Tournaments().BracketMatches(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	result := &model.TournamentBracket{Tournament: *t, Entries: entries, Matches: matches}
+
+	if t.Format == model.FormatRoundRobin && t.Status != model.TournamentRegistration {
+		result.Fixtures, err = s.store.Tournaments().Fixtures(ctx, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		decided, err := decidedFixtures(ctx, s.store, result.Fixtures)
+		if err != nil {
+			return nil, err
+		}
+		result.Standings = leagueStandings(entries, result.Fixtures, decided)
+	}
+
+	return result, nil
+}
+
+// AddEntry registers a player or pair while the tournament is open (organizer only).
+// Players must belong to the club and can only be entered once.
+func (s *TournamentService) AddEntry(actor model.Actor, clubID, tournamentID int64, req model.TournamentEntryRequest) (*model.TournamentEntry, error) {
+	ctx := context.Background()
+	entry := &model.TournamentEntry{TournamentID: tournamentID, Players: req.Players}
+
+	err := s.store.WithTx(ctx, func(tx database.Store) error {
+		t, err := s.getForUpdate(ctx, tx, clubID, tournamentID)
+		if err != nil {
+			return err
+		}
+		if t.Status != model.TournamentRegistration {
+			return ErrTournamentStarted
+		}
+
+		size := 2
+		if t.EntryType == model.EntrySingles {
+			size = 1
+		}
+		if len(entry.Players) != size || len(distinct(append([]int64{}, entry.Players...))) != size {
+			return ErrInvalidEntry
+		}
+
+		members, err := tx.Users().AllInClub(ctx, clubID, entry.Players)
+		if err != nil {
+			return err
+		}
+		if !members {
+			return ErrNotClubMember
+		}
+
+		existing, err := tx.Tournaments().Entries(ctx, tournamentID)
+		if err != nil {
+			return err
+		}
+		for _, e := range existing {
+			for _, id := range entry.Players {
+				if contains(e.Players, id) {
+					return ErrAlreadyEntered
+				}
+			}
+		}
+
+		return tx.Tournaments().AddEntry(ctx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if users, err := s.store.Users().GetMany(ctx, entry.Players); err == nil {
+		entry.Names = entryNames(entry.Players, users)
+	}
+	s.audit.Record(actor, clubID, model.AuditEntryAdded, "tournament", tournamentID, nil, entry)
+
+	return entry, nil
+}
+
+// RemoveEntry withdraws an entry before the bracket is drawn (organizer only)
+func (s *TournamentService) RemoveEntry(actor model.Actor, clubID, tournamentID, entryID int64) error {
+	ctx := context.Background()
+
+	var removed model.TournamentEntry
+	err := s.store.WithTx(ctx, func(tx database.Store) error {
+		t, err := s.getForUpdate(ctx, tx, clubID, tournamentID)
+		if err != nil {
+			return err
+		}
+		if t.Status != model.TournamentRegistration {
+			return ErrTournamentStarted
+		}
+
+		entries, err := tx.Tournaments().Entries(ctx, tournamentID)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if e.ID == entryID {
+				removed = e
+			}
+		}
+		if removed.ID == 0 {
+			return ErrEntryNotFound
+		}
+
+		return tx.Tournaments().RemoveEntry(ctx, tournamentID, entryID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.audit.Record(actor, clubID, model.AuditEntryRemoved, "tournament", tournamentID, removed, nil)
+
+	return nil
+}
+
+// Start closes registration, seeds the entries and draws the bracket (organizer only).
+// Byes go to the top seeds and are settled straight away, so the first matches are ready.
+// Round robins are split into groups and get their fixture list instead.
+func (s *TournamentService) Start(actor model.Actor, clubID, tournamentID int64) (*model.TournamentBracket, error) {
+	ctx := context.Background()
+
+	// Courts are checked up front so the schedule only uses ones that can be played on
+	var courts []string
+	if t, err := s.get(ctx, s.store, clubID, tournamentID); err != nil {
+		return nil, err
+	} else if t.Format == model.FormatRoundRobin && t.Status == model.TournamentRegistration {
+		if courts, err = s.leagueCourts(t); err != nil {
+			return nil, err
+		}
+	}
+
+	var before model.Tournament
+	err := s.store.WithTx(ctx, func(tx database.Store) error {
+		t, err := s.getForUpdate(ctx, tx, clubID, tournamentID)
+		if err != nil {
+			return err
+		}
+		if t.Status != model.TournamentRegistration {
+			return ErrTournamentStarted
+		}
+		before = *t
+
+		entries, err := tx.Tournaments().Entries(ctx, tournamentID)
+		if err != nil {
+			return err
+		}
+		minimum := 2
+		switch t.Format {
+		case model.FormatDoubleElimination:
+			minimum = 3
+		case model.FormatRoundRobin:
+			minimum = 2 * t.Groups
+		}
+		if len(entries) < minimum {
+			return ErrTooFewEntries
+		}
+
+		if err := s.seed(ctx, tx, t.Seeding, entries); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		t.StartedAt = &now
+		if t.Format == model.FormatRoundRobin {
+			err = drawLeague(ctx, tx, t, entries, courts)
+		} else {
+			err = drawBracket(ctx, tx, t, entries)
+		}
+		if err != nil {
+			return err
+		}
+
+		t.Status = model.TournamentInProgress
+		return tx.Tournaments().Update(ctx, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bracket, err := s.Get(clubID, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, clubID, model.AuditTournamentStarted, "tournament", tournamentID, before, bracket.Tournament)
+
+	return bracket, nil
+}
+
+// StartMatch puts a ready bracket match on a court (organizer only), creating the match
+// its result is recorded against
+func (s *TournamentService) StartMatch(actor model.Actor, clubID, tournamentID, bracketMatchID int64, court string) (*model.Match, error) {
+	ctx := context.Background()
+
+	bracket, err := s.Get(clubID, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	bm, err := findBracketMatch(bracket.Matches, bracketMatchID)
+	if err != nil {
+		return nil, err
+	}
+	if bm.Status != model.BracketReady {
+		return nil, ErrBracketMatchNotReady
+	}
+
+	players := make(map[int64][]int64, len(bracket.Entries))
+	for _, e := range bracket.Entries {
+		players[e.ID] = e.Players
+	}
+	match, err := s.matchService.newMatch(clubID, 0, court, players[*bm.Entry1ID], players[*bm.Entry2ID], &bracket.Tournament.Scoring)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.store.WithTx(ctx, func(tx database.Store) error {
+		t, err := s.getForUpdate(ctx, tx, clubID, tournamentID)
+		if err != nil {
+			return err
+		}
+		if t.Status != model.TournamentInProgress {
+			return ErrTournamentNotRunning
+		}
+
+		// Someone else may have started it since it was read
+		matches, err := tx.Tournaments().BracketMatches(ctx, tournamentID)
+		if err != nil {
+			return err
+		}
+		bm, err := findBracketMatch(matches, bracketMatchID)
+		if err != nil {
+			return err
+		}
+		if bm.Status != model.BracketReady {
+			return ErrBracketMatchNotReady
+		}
+
+		if err := s.matchService.insertMatch(ctx, tx, match); err != nil {
+			return err
+		}
+
+		bm.MatchID = &match.ID
+		bm.Status = model.BracketPlaying
+		return tx.Tournaments().UpdateBracketMatch(ctx, *bm)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.matchService.announceMatch(actor, match)
+
+	return match, nil
+}
+
+func (s *TournamentService) get(ctx context.Context, repo database.Store, clubID, tournamentID int64) (*model.Tournament, error) {
+	t, err := repo.Tournaments().Get(ctx, clubID, tournamentID)
+	if err == database.ErrNotFound {
+		return nil, ErrTournamentNotFound
+	}
+	return t, err
+}
+
+func (s *TournamentService) getForUpdate(ctx context.Context, tx database.Store, clubID, tournamentID int64) (*model.Tournament, error) {
+	t, err := tx.Tournaments().GetForUpdate(ctx, clubID, tournamentID)
+	if err == database.ErrNotFound {
+		return nil, ErrTournamentNotFound
+	}
+	return t, err
+}
+
+// entries returns a tournament's entries with their players' names
+func (s *TournamentService) entries(ctx context.Context, tournamentID int64) ([]model.TournamentEntry, error) {
+	entries, err := s.store.Tournaments().Entries(ctx, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for _, e := range entries {
+		ids = append(ids, e.Players...)
+	}
+	users, err := s.store.Users().GetMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].Names = entryNames(entries[i].Players, users)
+	}
+	return entries, nil
+}
+
+// entryNames returns the names of an entry's players in order
+func entryNames(players []int64, users []model.User) []string {
+	names := make([]string, len(players))
+	for i, id := range players {
+		for _, u := range users {
+			if u.ID == id {
+				names[i] = u.Name
+			}
+		}
+	}
+	return names
+}
+
+// seed ranks entries strongest first and numbers them from 1. Strength is the players'
+// average rating, or their average skill tier with rating breaking ties; registration
+// order breaks any remaining ties.
+func (s *TournamentService) seed(ctx context.Context, repo database.Store, method model.SeedingMethod, entries []model.TournamentEntry) error {
+	var ids []int64
+	for _, e := range entries {
+		ids = append(ids, e.Players...)
+	}
+	stats, err := repo.Stats().GetMany(ctx, ids)
+	if err != nil {
+		return err
+	}
+	users, err := repo.Users().GetMany(ctx, ids)
+	if err != nil {
+		return err
+	}
+	tiers := make(map[int64]model.SkillTier, len(users))
+	for _, u := range users {
+		tiers[u.ID] = u.SkillTier
+	}
+
+	ratings := make(map[int64]float64, len(entries))
+	for i, e := range entries {
+		var rating, tier float64
+		for _, id := range e.Players {
+			r := DefaultRating
+			if st, ok := stats[id]; ok {
+				r = st.Rating
+			}
+			rating += r
+			tier += tierStrength[tiers[id]]
+		}
+		ratings[e.ID] = rating / float64(len(e.Players))
+		entries[i].Strength = ratings[e.ID]
+		if method == model.SeedBySkillTier {
+			entries[i].Strength = tier / float64(len(e.Players))
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Strength != entries[j].Strength {
+			return entries[i].Strength > entries[j].Strength
+		}
+		return ratings[entries[i].ID] > ratings[entries[j].ID]
+	})
+	for i := range entries {
+		entries[i].Seed = i + 1
+	}
+	return nil
+}
+
+func findBracketMatch(matches []model.BracketMatch, id int64) (*model.BracketMatch, error) {
+	for i := range matches {
+		if matches[i].ID == id {
+			return &matches[i], nil
+		}
+	}
+	return nil, ErrBracketMatchNotFound
+}
+
+// drawBracket saves the seeded entries and their elimination bracket
+func drawBracket(ctx context.Context, tx database.Store, t *model.Tournament, entries []model.TournamentEntry) error {
+	if err := tx.Tournaments().SeedEntries(ctx, entries); err != nil {
+		return err
+	}
+
+	// Insert in reverse order of play so every match a result moves to already exists
+	plan := planBracket(t.Format, entries)
+	saved := make([]model.BracketMatch, len(plan))
+	for i := len(plan) - 1; i >= 0; i-- {
+		m := plan[i].BracketMatch
+		m.TournamentID = t.ID
+		if next := plan[i].next; next >= 0 {
+			id := saved[next].ID
+			m.NextMatchID = &id
+		}
+		if next := plan[i].loserNext; next >= 0 {
+			id := saved[next].ID
+			m.LoserNextMatchID = &id
+		}
+		if err := tx.Tournaments().AddBracketMatch(ctx, &m); err != nil {
+			return err
+		}
+		saved[i] = m
+	}
+
+	b := newBracket(saved)
+	b.settle()
+	return saveBracket(ctx, tx, b)
+}
+
+// saveBracket writes back the bracket matches that changed
+func saveBracket(ctx context.Context, tx database.Store, b *bracket) error {
+	for _, m := range b.matches {
+		if b.changed[m.ID] {
+			if err := tx.Tournaments().UpdateBracketMatch(ctx, m); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// advanceTournament moves a tournament on after one of its matches is recorded. Matches
+// outside a tournament are left alone. It runs inside the transaction that records the result.
+func advanceTournament(ctx context.Context, tx database.Store, match *model.Match) error {
+	if err := advanceBracket(ctx, tx, match); err != nil {
+		return err
+	}
+	return completeFixture(ctx, tx, match)
+}
+
+// advanceBracket moves the entries of a just-recorded bracket match on to their next matches
+// and completes the tournament after its deciding match. Matches outside a tournament are
+// left alone. It runs inside the transaction that records the result.
+func advanceBracket(ctx context.Context, tx database.Store, match *model.Match) error {
+	bm, err := tx.Tournaments().BracketMatchFor(ctx, match.ID)
+	if err == database.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	t, err := tx.Tournaments().GetForUpdate(ctx, match.ClubID, bm.TournamentID)
+	if err != nil {
+		return err
+	}
+	matches, err := tx.Tournaments().BracketMatches(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+
+	b := newBracket(matches)
+	m := b.get(&bm.ID)
+	if m == nil || m.Status != model.BracketPlaying {
+		return nil
+	}
+
+	// Team 1 is always entry 1's players
+	winner, loser := m.Entry1ID, m.Entry2ID
+	if match.Result == string(model.MatchResultTeam2) {
+		winner, loser = loser, winner
+	}
+	b.decide(m, winner, loser)
+	b.settle()
+	if err := saveBracket(ctx, tx, b); err != nil {
+		return err
+	}
+
+	if champion := b.champion(); champion != nil && t.Status != model.TournamentCompleted {
+		now := time.Now()
+		t.Status = model.TournamentCompleted
+		t.WinnerEntryID = champion
+		t.CompletedAt = &now
+		return tx.Tournaments().Update(ctx, t)
+	}
+	return nil
+}
@@ -1,23 +1,25 @@
 package service
 
 import (
+	"backend/database"
 	"backend/model"
 	"context"
-	"database/sql"
-	"errors"
+	"math"
 )
 
 // UserService handles user profile operations
 type UserService struct {
 	authService *AuthService
-	db          *sql.DB
+	audit       *AuditLog
+	store       database.Store
 }
 
 // NewUserService creates a new user service
-func NewUserService(authSvc *AuthService, db *sql.DB) *UserService {
+func NewUserService(authSvc *AuthService, audit *AuditLog, store database.Store) *UserService {
 	return &UserService{
 		authService: authSvc,
-		db:          db,
+		audit:       audit,
+		store:       store,
 	}
 }
 
@@ -43,69 +45,89 @@ func (s *UserService) GetProfile(userID int64) (*model.UserProfile, error) {
 	}, nil
 }
 
+// MemberProfile returns the profile of a member of the club. Users outside the club are
+// reported as not found so their personal details stay private.
+func (s *UserService) MemberProfile(clubID, userID int64) (*model.UserProfile, error) {
+	member, err := s.store.Users().AllInClub(context.Background(), clubID, []int64{userID})
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, ErrUserNotFound
+	}
+	return s.GetProfile(userID)
+}
+
 // UpdateProfile updates user profile information
 func (s *UserService) UpdateProfile(userID int64, req model.UpdateProfileRequest) (*model.User, error) {
-	ctx := context.Background()
+	user, err := s.store.Users().UpdateProfile(context.Background(), userID, req)
+	if err == database.ErrNotFound {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
 
-	var user model.User
-	err := s.db.QueryRowContext(ctx, `
-		UPDATE users 
-		SET name = $2, bio = $3, phone = COALESCE(NULLIF($4, ''), phone), updated_at = NOW()
-		WHERE id = $1
-		RETURNING id, username, name, phone, bio, role, is_active, created_at, updated_at
-	`, userID, req.Name, req.Bio, req.Phone).Scan(
-		&user.ID, &user.Username, &user.Name, &user.Phone,
-		&user.Bio, &user.Role, &user.IsActive, &user.CreatedAt, &user.UpdatedAt,
-	)
+// GetStats returns the user's performance statistics
+func (s *UserService) GetStats(userID int64) (*model.UserStats, error) {
+	return s.store.Stats().Get(context.Background(), userID)
+}
 
-	if err == sql.ErrNoRows {
-		return nil, errors.New("user not found")
-	}
+// UpdateStats updates user statistics after a match
+func (s *UserService) UpdateStats(userID int64, won bool) error {
+	return s.updateStats(context.Background(), s.store, userID, won)
+}
+
+func (s *UserService) updateStats(ctx context.Context, repo database.Store, userID int64, won bool) error {
+	// Get current stats
+	stats, err := repo.Stats().Get(ctx, userID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return &user, nil
+	// Update stats
+	applyResult(stats, won)
+
+	return repo.Stats().SaveRecord(ctx, stats)
 }
 
-// GetStats returns the user's performance statistics
-func (s *UserService) GetStats(userID int64) (*model.UserStats, error) {
+// GetClubStats returns a player's record in one club's matches.
+// Rating is shared across clubs, so it comes from the player's overall stats.
+func (s *UserService) GetClubStats(clubID, userID int64) (*model.UserStats, error) {
 	ctx := context.Background()
 
-	var stats model.UserStats
-	err := s.db.QueryRowContext(ctx, `
-		SELECT user_id, total_matches, wins, losses, win_rate, current_streak, best_streak, skill_level, skill_points
-		FROM user_stats WHERE user_id = $1
-	`, userID).Scan(
-		&stats.UserID, &stats.TotalMatches, &stats.Wins, &stats.Losses,
-		&stats.WinRate, &stats.CurrentStreak, &stats.BestStreak, &stats.SkillLevel, &stats.SkillPoints,
-	)
-
-	if err == sql.ErrNoRows {
-		// Initialize stats if not found
-		s.db.ExecContext(ctx, `
-			INSERT INTO user_stats (user_id, skill_level) VALUES ($1, 'Beginner') ON CONFLICT DO NOTHING
-		`, userID)
-		return &model.UserStats{UserID: userID, SkillLevel: "Beginner"}, nil
+	overall, err := s.store.Stats().Get(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
+
+	outcomes, err := s.store.Matches().Outcomes(ctx, clubID, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	return &stats, nil
+	return buildRecord(overall, outcomes), nil
 }
 
-// UpdateStats updates user statistics after a match
-func (s *UserService) UpdateStats(userID int64, won bool) error {
-	ctx := context.Background()
-
-	// Get current stats
-	stats, err := s.GetStats(userID)
-	if err != nil {
-		return err
+// buildRecord replays a player's results, oldest first, on top of their current rating
+func buildRecord(rated *model.UserStats, outcomes []bool) *model.UserStats {
+	stats := &model.UserStats{
+		UserID:     rated.UserID,
+		Rating:     rated.Rating,
+		RatingDev:  rated.RatingDev,
+		Volatility: rated.Volatility,
+	}
+	for _, won := range outcomes {
+		applyResult(stats, won)
 	}
 
-	// Update stats
+	stats.SkillLevel = skillLevelForRating(stats.Rating, stats.TotalMatches)
+	stats.SkillPoints = int(math.Round(stats.Rating))
+
+	return stats
+}
+
+// applyResult adds one match result to a player's record
+func applyResult(stats *model.UserStats, won bool) {
 	stats.TotalMatches++
 	if won {
 		stats.Wins++
@@ -127,39 +149,108 @@ func (s *UserService) UpdateStats(userID int64, won bool) error {
 	}
 
 	// Calculate win rate
-	if stats.TotalMatches > 0 {
-		stats.WinRate = float64(stats.Wins) * 100 / float64(stats.TotalMatches)
+	stats.WinRate = float64(stats.Wins) * 100 / float64(stats.TotalMatches)
+
+	// Skill level and points follow the rating, which UpdateRatings has already applied
+	stats.SkillLevel = skillLevelForRating(stats.Rating, stats.TotalMatches)
+	stats.SkillPoints = int(math.Round(stats.Rating))
+}
+
+// UpdateRatings applies the Glicko-2 doubles update for a completed match
+// and stores each player's before/after rating in rating_history
+func (s *UserService) UpdateRatings(matchID int64, team1, team2 []int64, scores []model.GameScore, team1Won bool) error {
+	return s.updateRatings(context.Background(), s.store, matchID, team1, team2, scores, team1Won)
+}
+
+func (s *UserService) updateRatings(ctx context.Context, repo database.Store, matchID int64, team1, team2 []int64, scores []model.GameScore, team1Won bool) error {
+	current, err := s.getRatings(ctx, repo, append(append([]int64{}, team1...), team2...))
+	if err != nil {
+		return err
+	}
+
+	result := string(model.MatchResultTeam2)
+	if team1Won {
+		result = string(model.MatchResultTeam1)
+	}
+	match := model.Match{ID: matchID, Team1: team1, Team2: team2, Scores: scores, Result: result}
+	return s.rateMatch(ctx, repo, match, current)
+}
+
+// rateMatch rates a decided match from the players' ratings in current, saving each
+// change and leaving the new ratings in current
+func (s *UserService) rateMatch(ctx context.Context, repo database.Store, match model.Match, current map[int64]Rating) error {
+	after := matchRatings(match, current)
+	for _, id := range append(append([]int64{}, match.Team1...), match.Team2...) {
+		if err := s.saveRating(ctx, repo, match, id, current[id], after[id]); err != nil {
+			return err
+		}
+		current[id] = after[id]
 	}
+	return nil
+}
 
-	// Calculate skill level
-	stats.SkillLevel = calculateSkillLevel(stats.WinRate, stats.TotalMatches)
-	stats.SkillPoints = stats.TotalMatches*10 + stats.Wins*5
+// matchRatings returns every player's rating after a decided match, given their ratings going in
+func matchRatings(match model.Match, before map[int64]Rating) map[int64]Rating {
+	before1 := make([]Rating, len(match.Team1))
+	for i, id := range match.Team1 {
+		before1[i] = before[id]
+	}
+	before2 := make([]Rating, len(match.Team2))
+	for i, id := range match.Team2 {
+		before2[i] = before[id]
+	}
 
-	// Save to database
-	_, err = s.db.ExecContext(ctx, `
-		UPDATE user_stats SET
-			total_matches = $2, wins = $3, losses = $4, win_rate = $5,
-			current_streak = $6, best_streak = $7, skill_level = $8, skill_points = $9,
-			updated_at = NOW()
-		WHERE user_id = $1
-	`, userID, stats.TotalMatches, stats.Wins, stats.Losses, stats.WinRate,
-		stats.CurrentStreak, stats.BestStreak, stats.SkillLevel, stats.SkillPoints)
+	after1, after2 := RateDoubles(before1, before2, match.Scores, match.Result == string(model.MatchResultTeam1))
 
-	return err
+	after := make(map[int64]Rating, len(after1)+len(after2))
+	for i, id := range match.Team1 {
+		after[id] = after1[i]
+	}
+	for i, id := range match.Team2 {
+		after[id] = after2[i]
+	}
+	return after
 }
 
-func calculateSkillLevel(winRate float64, matches int) string {
-	if matches < 5 {
-		return "Beginner"
+// GetRatingHistory returns a player's rating changes, most recent first
+func (s *UserService) GetRatingHistory(userID int64, limit int) ([]model.RatingChange, error) {
+	if limit <= 0 {
+		limit = 50
 	}
-	if winRate >= 75 {
-		return "Expert"
+
+	return s.store.Stats().RatingHistory(context.Background(), userID, limit)
+}
+
+func (s *UserService) getRatings(ctx context.Context, repo database.Store, userIDs []int64) (map[int64]Rating, error) {
+	stats, err := repo.Stats().GetMany(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	ratings := make(map[int64]Rating, len(userIDs))
+	for _, id := range userIDs {
+		ratings[id] = NewRating()
+		if st, ok := stats[id]; ok {
+			ratings[id] = Rating{Rating: st.Rating, Deviation: st.RatingDev, Volatility: st.Volatility}
+		}
 	}
-	if winRate >= 55 {
-		return "Advanced"
+
+	return ratings, nil
+}
+
+// saveRating stores a player's rating change from a match. Replayed matches keep the time
+// they ended so the history stays in match order.
+func (s *UserService) saveRating(ctx context.Context, repo database.Store, match model.Match, userID int64, before, after Rating) error {
+	change := model.RatingChange{
+		MatchID:         match.ID,
+		UserID:          userID,
+		RatingBefore:    before.Rating,
+		RatingAfter:     after.Rating,
+		DeviationBefore: before.Deviation,
+		DeviationAfter:  after.Deviation,
 	}
-	if winRate >= 40 {
-		return "Intermediate"
+	if match.EndedAt != nil {
+		change.CreatedAt = *match.EndedAt
 	}
-	return "Beginner"
+	return repo.Stats().SaveRating(ctx, change, after.Volatility)
 }
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"backend/database"
+	"backend/model"
+	"context"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	playersPerMatch = 4
+	// durationSampleSize is how many recent completed matches the duration estimate uses
+	durationSampleSize = 30
+	// minDurationSamples is the fewest completed matches trusted over the defaults
+	minDurationSamples = 3
+
+	defaultMatchMinutes = 20.0
+	defaultMatchSpread  = 6.0
+	minMatchSpread      = 2.0
+	// minRemainingMinutes is assumed left on a match already running past the typical duration
+	minRemainingMinutes = 2.0
+	// waitRangeZ widens the estimate to an 80% range
+	waitRangeZ = 1.2816
+)
+
+// WaitInputs is what the wait-time estimator knows about a queue
+type WaitInputs struct {
+	GroupsAhead int             // Matches that will start before the player's own
+	Courts      int             // Active courts available to the queue
+	Elapsed     []time.Duration // Time already played on each in-progress match
+	Durations   []time.Duration // Recent completed match durations
+}
+
+// EstimateWait simulates courts freeing up and being refilled, in queue order, until
+// the player's group gets a court. Each match is assumed to take the recent mean duration;
+// the range grows with every match duration on the path to the player's court.
+// Returns nil when there are no courts to wait for.
+func EstimateWait(in WaitInputs) *model.WaitEstimate {
+	if in.Courts <= 0 {
+		return nil
+	}
+
+	mean, spread := durationStats(in.Durations)
+
+	// Minutes until each court is free, with the variance of that prediction
+	type court struct{ free, variance float64 }
+	courts := make([]court, in.Courts)
+	for i, elapsed := range in.Elapsed {
+		if i >= len(courts) {
+			break
+		}
+		remaining := math.Max(mean-elapsed.Minutes(), minRemainingMinutes)
+		courts[i] = court{free: remaining, variance: spread * spread}
+	}
+
+	var next court
+	for g := 0; g <= in.GroupsAhead; g++ {
+		sort.Slice(courts, func(i, j int) bool { return courts[i].free < courts[j].free })
+		next = courts[0]
+		courts[0].free += mean
+		courts[0].variance += spread * spread
+	}
+
+	margin := waitRangeZ * math.Sqrt(next.variance)
+	return &model.WaitEstimate{
+		Minutes:      int(math.Round(next.free)),
+		LowMinutes:   int(math.Round(math.Max(next.free-margin, 0))),
+		HighMinutes:  int(math.Round(next.free + margin)),
+		MatchesAhead: in.GroupsAhead,
+		Courts:       in.Courts,
+		SampleSize:   len(in.Durations),
+	}
+}
+
+// durationStats returns the mean and standard deviation of match durations in minutes
+func durationStats(durations []time.Duration) (float64, float64) {
+	if len(durations) < minDurationSamples {
+		return defaultMatchMinutes, defaultMatchSpread
+	}
+
+	var sum float64
+	for _, d := range durations {
+		sum += d.Minutes()
+	}
+	mean := sum / float64(len(durations))
+
+	var sq float64
+	for _, d := range durations {
+		sq += (d.Minutes() - mean) * (d.Minutes() - mean)
+	}
+	spread := math.Sqrt(sq / float64(len(durations)-1))
+
+	return mean, math.Max(spread, minMatchSpread)
+}
+
+// waitInputs gathers court usage and recent match durations for a club session's queue.
+// A player at the given waiting position is preceded by every called group.
+func (s *QueueService) waitInputs(ctx context.Context, clubID, sessionID int64, position int) (WaitInputs, error) {
+	in := WaitInputs{}
+
+	called, err := s.store.Queue().Count(ctx, clubID, sessionID, model.QueueStatusCalled)
+	if err != nil {
+		return in, err
+	}
+	in.GroupsAhead = (called+playersPerMatch-1)/playersPerMatch + (position-1)/playersPerMatch
+
+	// Active courts, limited to those booked for the session if it has any
+	courts, err := s.courtService.List(clubID, true)
+	if err != nil {
+		return in, err
+	}
+	booked := s.sessionService.CourtIDs(sessionID)
+	onCourt := make(map[string]bool, len(courts))
+	for _, c := range courts {
+		if len(booked) == 0 || contains(booked, c.ID) {
+			onCourt[c.Name] = true
+			in.Courts++
+		}
+	}
+
+	active, err := s.store.Matches().Active(ctx, database.MatchFilter{ClubID: clubID})
+	if err != nil {
+		return in, err
+	}
+	for _, m := range active {
+		if onCourt[m.Court] {
+			in.Elapsed = append(in.Elapsed, time.Since(m.StartedAt))
+		}
+	}
+
+	// Results entered hours late would skew the mean, so ignore implausible durations
+	in.Durations, err = s.store.Matches().Durations(ctx, clubID, durationSampleSize, 2*time.Hour)
+	return in, err
+}
@@ -19,8 +19,16 @@ import {
   QueueInfo,
   MatchHistory,
   UserListItem,
+  WaitEstimate,
 } from "../lib/api";
 
+function formatWait(wait?: WaitEstimate): string {
+  if (!wait) return "—";
+  if (wait.high_minutes === 0) return "Next up!";
+  if (wait.low_minutes === wait.high_minutes) return `~${wait.minutes} min`;
+  return `~${wait.minutes} min (${wait.low_minutes}–${wait.high_minutes})`;
+}
+
 export default function DashboardPage() {
   const [isLoading, setIsLoading] = useState(true);
   const [profile, setProfile] = useState<UserProfile | null>(null);
@@ -410,7 +418,7 @@ export default function DashboardPage() {
                 </div>
                 <div className="flex justify-between py-3 border-b border-[var(--border)]">
                   <span className="text-[var(--muted)]">Estimated Wait</span>
-                  <span className="font-medium">{queueInfo?.estimated_wait || "—"}</span>
+                  <span className="font-medium">{formatWait(queueInfo?.estimated_wait)}</span>
                 </div>
                 <div className="flex justify-between py-3">
                   <span className="text-[var(--muted)]">In Queue</span>
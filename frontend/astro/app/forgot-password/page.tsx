@@ -6,10 +6,10 @@ import { useState } from "react";
 const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080/api';
 
 export default function ForgotPasswordPage() {
-  const [step, setStep] = useState<'verify' | 'reset'>('verify');
+  const [step, setStep] = useState<'request' | 'reset'>('request');
   const [formData, setFormData] = useState({
     username: "",
-    phone: "",
+    code: "",
     newPassword: "",
     confirmPassword: "",
   });
@@ -19,7 +19,7 @@ export default function ForgotPasswordPage() {
   const [error, setError] = useState("");
   const [success, setSuccess] = useState("");
 
-  const handleVerify = async (e: React.FormEvent) => {
+  const handleRequest = async (e: React.FormEvent) => {
     e.preventDefault();
     setError("");
     setIsLoading(true);
@@ -30,17 +30,16 @@ export default function ForgotPasswordPage() {
         headers: { 'Content-Type': 'application/json' },
         body: JSON.stringify({
           username: formData.username,
-          phone: formData.phone,
         }),
       });
 
       const data = await response.json();
 
       if (data.success) {
-        setSuccess("Verification successful! Please enter your new password.");
+        setSuccess("If the account exists, a reset code has been sent. Enter it below with your new password.");
         setStep('reset');
       } else {
-        setError(data.error?.message || "Verification failed. Please check your username and phone number.");
+        setError(data.error?.message || "Could not send a reset code. Please try again.");
       }
     } catch {
       setError("Network error. Please check your connection.");
@@ -71,7 +70,7 @@ export default function ForgotPasswordPage() {
         headers: { 'Content-Type': 'application/json' },
         body: JSON.stringify({
           username: formData.username,
-          phone: formData.phone,
+          code: formData.code,
           new_password: formData.newPassword,
         }),
       });
@@ -124,12 +123,12 @@ export default function ForgotPasswordPage() {
               <span className="text-3xl">🔑</span>
             </div>
             <h1 className="text-2xl font-bold mb-2">
-              {step === 'verify' ? 'Forgot Password' : 'Reset Password'}
+              {step === 'request' ? 'Forgot Password' : 'Reset Password'}
             </h1>
             <p className="text-[var(--muted)]">
-              {step === 'verify' 
-                ? 'Enter your username and phone number to verify your identity'
-                : 'Enter your new password'}
+              {step === 'request'
+                ? 'Enter your username or phone number and we will send you a reset code'
+                : 'Enter the code you received and your new password'}
             </p>
           </div>
 
@@ -145,17 +144,17 @@ export default function ForgotPasswordPage() {
             </div>
           )}
 
-          {step === 'verify' ? (
-            <form onSubmit={handleVerify} className="space-y-5">
+          {step === 'request' ? (
+            <form onSubmit={handleRequest} className="space-y-5">
               <div>
                 <label htmlFor="username" className="label">
-                  Username
+                  Username or Phone
                 </label>
                 <input
                   type="text"
                   id="username"
                   className="input"
-                  placeholder="Enter your username"
+                  placeholder="Enter your username or phone number"
                   value={formData.username}
                   onChange={(e) =>
                     setFormData({ ...formData, username: e.target.value })
@@ -164,37 +163,36 @@ export default function ForgotPasswordPage() {
                 />
               </div>
 
+              <button
+                type="submit"
+                disabled={isLoading}
+                className="btn-primary w-full py-3 disabled:opacity-50 disabled:cursor-not-allowed"
+              >
+                {isLoading ? "Sending..." : "Send Reset Code"}
+              </button>
+            </form>
+          ) : (
+            <form onSubmit={handleReset} className="space-y-5">
               <div>
-                <label htmlFor="phone" className="label">
-                  Phone Number
+                <label htmlFor="code" className="label">
+                  Reset Code
                 </label>
                 <input
-                  type="tel"
-                  id="phone"
+                  type="text"
+                  id="code"
+                  inputMode="numeric"
+                  autoComplete="one-time-code"
                   className="input"
-                  placeholder="08xxxxxxxx or 09xxxxxxxx"
-                  value={formData.phone}
+                  placeholder="6-digit code"
+                  value={formData.code}
                   onChange={(e) => {
-                    const val = e.target.value.replace(/\D/g, "").slice(0, 10);
-                    setFormData({ ...formData, phone: val });
+                    const val = e.target.value.replace(/\D/g, "").slice(0, 6);
+                    setFormData({ ...formData, code: val });
                   }}
                   required
                 />
-                <p className="text-xs text-[var(--muted)] mt-2">
-                  Enter the phone number registered with your account
-                </p>
               </div>
 
-              <button
-                type="submit"
-                disabled={isLoading}
-                className="btn-primary w-full py-3 disabled:opacity-50 disabled:cursor-not-allowed"
-              >
-                {isLoading ? "Verifying..." : "Verify Account"}
-              </button>
-            </form>
-          ) : (
-            <form onSubmit={handleReset} className="space-y-5">
               <div>
                 <label htmlFor="newPassword" className="label">
                   New Password
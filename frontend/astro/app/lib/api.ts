@@ -43,10 +43,20 @@ export interface APIResponse<T> {
   };
 }
 
+export interface WaitEstimate {
+  minutes: number;
+  low_minutes: number;
+  high_minutes: number;
+  matches_ahead: number;
+  courts: number;
+  sample_size: number;
+}
+
 export interface QueueInfo {
+  session_id?: number;
   total_in_queue: number;
   your_position?: number;
-  estimated_wait?: string;
+  estimated_wait?: WaitEstimate;
   next_court?: string;
   currently_playing: QueueEntry[];
 }
@@ -37,7 +37,7 @@ usage() {
     echo "  stop       Stop PostgreSQL container"
     echo "  status     Check database status"
     echo "  create     Create the smashqueue database"
-    echo "  migrate    Run database migrations"
+    echo "  migrate    Run database migrations (up | down [steps] | status)"
     echo "  seed       Seed database with sample data"
     echo "  reset      Reset database (drop and recreate)"
     echo "  connect    Connect to database via psql"
@@ -116,100 +116,11 @@ create_db() {
 
 migrate_db() {
     echo -e "${BLUE}→${NC} Running database migrations..."
-    
-    PGPASSWORD=$DB_PASSWORD psql -h $DB_HOST -p $DB_PORT -U $DB_USER -d $DB_NAME << 'EOF'
--- Users table
-CREATE TABLE IF NOT EXISTS users (
-    id SERIAL PRIMARY KEY,
-    username VARCHAR(255) UNIQUE NOT NULL,
-    password_hash VARCHAR(255) NOT NULL,
-    name VARCHAR(255) NOT NULL DEFAULT '',
-    phone VARCHAR(20),
-    bio TEXT DEFAULT '',
-    role VARCHAR(50) NOT NULL DEFAULT 'player',
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
-);
-
--- Add unique constraint on phone (only for non-empty values)
-CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone_unique ON users(phone) WHERE phone IS NOT NULL AND phone != '';
-
--- User stats table
-CREATE TABLE IF NOT EXISTS user_stats (
-    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
-    total_matches INTEGER DEFAULT 0,
-    wins INTEGER DEFAULT 0,
-    losses INTEGER DEFAULT 0,
-    win_rate DECIMAL(5,2) DEFAULT 0,
-    current_streak INTEGER DEFAULT 0,
-    skill_level VARCHAR(50) DEFAULT 'Beginner',
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
-);
-
--- Matches table
-CREATE TABLE IF NOT EXISTS matches (
-    id SERIAL PRIMARY KEY,
-    court VARCHAR(100),
-    team1 INTEGER[] NOT NULL,
-    team2 INTEGER[] NOT NULL,
-    result VARCHAR(50) DEFAULT 'pending',
-    started_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-    ended_at TIMESTAMP WITH TIME ZONE,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
-);
-
--- Match scores table
-CREATE TABLE IF NOT EXISTS match_scores (
-    id SERIAL PRIMARY KEY,
-    match_id INTEGER REFERENCES matches(id) ON DELETE CASCADE,
-    game_number INTEGER NOT NULL,
-    team1_score INTEGER DEFAULT 0,
-    team2_score INTEGER DEFAULT 0
-);
-
--- Queue entries table
-CREATE TABLE IF NOT EXISTS queue_entries (
-    id SERIAL PRIMARY KEY,
-    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
-    position INTEGER NOT NULL,
-    status VARCHAR(50) DEFAULT 'waiting',
-    joined_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-    called_at TIMESTAMP WITH TIME ZONE
-);
 
--- Refresh tokens table
-CREATE TABLE IF NOT EXISTS refresh_tokens (
-    id SERIAL PRIMARY KEY,
-    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
-    token VARCHAR(255) UNIQUE NOT NULL,
-    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-    revoked_at TIMESTAMP WITH TIME ZONE
-);
-
--- Create indexes
-CREATE INDEX IF NOT EXISTS idx_users_username ON users(username);
-CREATE INDEX IF NOT EXISTS idx_queue_entries_status ON queue_entries(status);
-CREATE INDEX IF NOT EXISTS idx_matches_result ON matches(result);
-CREATE INDEX IF NOT EXISTS idx_refresh_tokens_token ON refresh_tokens(token);
-
--- Updated_at trigger function
-CREATE OR REPLACE FUNCTION update_updated_at_column()
-RETURNS TRIGGER AS $$
-BEGIN
-    NEW.updated_at = NOW();
-    RETURN NEW;
-END;
-$$ language 'plpgsql';
-
--- Apply trigger to users table
-DROP TRIGGER IF EXISTS update_users_updated_at ON users;
-CREATE TRIGGER update_users_updated_at
-    BEFORE UPDATE ON users
-    FOR EACH ROW
-    EXECUTE FUNCTION update_updated_at_column();
-
-EOF
+    # The schema lives in backend/database/migrations and is applied by the server binary
+    (cd "$PROJECT_DIR/backend" && \
+        DB_HOST=$DB_HOST DB_PORT=$DB_PORT DB_USER=$DB_USER DB_PASSWORD=$DB_PASSWORD DB_NAME=$DB_NAME \
+        go run main.go migrate "${@:-up}")
 
     echo -e "${GREEN}✓${NC} Migrations complete"
 }
@@ -277,7 +188,8 @@ case "${1:-}" in
         create_db
         ;;
     migrate)
-        migrate_db
+        shift
+        migrate_db "$@"
         ;;
     seed)
         seed_db
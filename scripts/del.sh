@@ -86,7 +86,10 @@ SELECT password_hash FROM users WHERE username = 'kong@admin';
 DELETE FROM match_scores;
 DELETE FROM matches;
 DELETE FROM queue_entries;
+DELETE FROM sessions;
 DELETE FROM refresh_tokens;
+DELETE FROM club_members;
+DELETE FROM clubs WHERE slug != 'default';
 DELETE FROM user_stats;
 DELETE FROM users;
 
@@ -95,6 +98,7 @@ ALTER SEQUENCE users_id_seq RESTART WITH 1;
 ALTER SEQUENCE matches_id_seq RESTART WITH 1;
 ALTER SEQUENCE match_scores_id_seq RESTART WITH 1;
 ALTER SEQUENCE queue_entries_id_seq RESTART WITH 1;
+ALTER SEQUENCE sessions_id_seq RESTART WITH 1;
 ALTER SEQUENCE refresh_tokens_id_seq RESTART WITH 1;
 
 -- Recreate admin user with ID 1
@@ -106,6 +110,10 @@ FROM temp_admin;
 INSERT INTO user_stats (user_id, total_matches, wins, losses, win_rate, skill_level, skill_points)
 VALUES (1, 0, 0, 0, 0, 'Expert', 0);
 
+-- Make the admin a member of the default club
+INSERT INTO club_members (club_id, user_id, role)
+SELECT id, 1, 'admin' FROM clubs WHERE slug = 'default';
+
 -- Commit transaction
 COMMIT;
 